@@ -0,0 +1,227 @@
+package scarlettctl
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// alsaTypeNames are the element type names used by alsactl and amixer
+var alsaTypeNames = map[ControlType]string{
+	ControlTypeBoolean:    "BOOLEAN",
+	ControlTypeInteger:    "INTEGER",
+	ControlTypeEnumerated: "ENUMERATED",
+	ControlTypeBytes:      "BYTES",
+	ControlTypeIEC958:     "IEC958",
+	ControlTypeInteger64:  "INTEGER64",
+}
+
+// WriteALSACtlState writes the card's controls in the layout of 'alsactl store',
+// so values can be cross-referenced with an alsactl state file. Byte controls,
+// which can't be read, are listed without a value.
+func (c *Card) WriteALSACtlState(w io.Writer) error {
+	elements, values, err := c.dumpElements()
+	if err != nil {
+		return err
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "state.%s {\n", alsaQuote(c.alsaID()))
+	for _, element := range elements {
+		ctl := element[0]
+		fmt.Fprintf(&sb, "\tcontrol.%d {\n", ctl.NumID)
+		fmt.Fprintf(&sb, "\t\tiface %s\n", strings.ToUpper(ctl.Interface.String()))
+		if ctl.Device != 0 {
+			fmt.Fprintf(&sb, "\t\tdevice %d\n", ctl.Device)
+		}
+		if ctl.Subdevice != 0 {
+			fmt.Fprintf(&sb, "\t\tsubdevice %d\n", ctl.Subdevice)
+		}
+		fmt.Fprintf(&sb, "\t\tname %s\n", alsaQuote(ctl.Name))
+
+		elementValues := alsactlValues(element, values)
+		if len(elementValues) == 1 {
+			fmt.Fprintf(&sb, "\t\tvalue %s\n", elementValues[0])
+		} else {
+			for i, value := range elementValues {
+				fmt.Fprintf(&sb, "\t\tvalue.%d %s\n", i, value)
+			}
+		}
+
+		sb.WriteString("\t\tcomment {\n")
+		fmt.Fprintf(&sb, "\t\t\taccess %s\n", alsaQuote(alsactlAccess(ctl)))
+		fmt.Fprintf(&sb, "\t\t\ttype %s\n", alsaTypeNames[ctl.Type])
+		fmt.Fprintf(&sb, "\t\t\tcount %d\n", ctl.Count)
+		switch ctl.Type {
+		case ControlTypeInteger, ControlTypeInteger64:
+			rng := fmt.Sprintf("%d - %d", ctl.Min, ctl.Max)
+			if ctl.Step > 1 {
+				rng += fmt.Sprintf(" (step %d)", ctl.Step)
+			}
+			fmt.Fprintf(&sb, "\t\t\trange %s\n", alsaQuote(rng))
+		case ControlTypeEnumerated:
+			for i, item := range ctl.Items {
+				fmt.Fprintf(&sb, "\t\t\titem.%d %s\n", i, alsaQuote(item))
+			}
+		}
+		sb.WriteString("\t\t}\n")
+		sb.WriteString("\t}\n")
+	}
+	sb.WriteString("}\n")
+
+	_, err = io.WriteString(w, sb.String())
+	return err
+}
+
+// WriteAmixerContents writes the card's controls in the layout of
+// 'amixer contents': the element id, its type and range, and its values
+func (c *Card) WriteAmixerContents(w io.Writer) error {
+	elements, values, err := c.dumpElements()
+	if err != nil {
+		return err
+	}
+
+	var sb strings.Builder
+	for _, element := range elements {
+		ctl := element[0]
+		fmt.Fprintf(&sb, "numid=%d,iface=%s,name='%s'", ctl.NumID, strings.ToUpper(ctl.Interface.String()), ctl.Name)
+		if ctl.Device != 0 {
+			fmt.Fprintf(&sb, ",device=%d", ctl.Device)
+		}
+		if ctl.Subdevice != 0 {
+			fmt.Fprintf(&sb, ",subdevice=%d", ctl.Subdevice)
+		}
+		sb.WriteString("\n")
+
+		fmt.Fprintf(&sb, "  ; type=%s,access=%s,values=%d", alsaTypeNames[ctl.Type], amixerAccess(ctl), ctl.Count)
+		switch ctl.Type {
+		case ControlTypeInteger, ControlTypeInteger64:
+			fmt.Fprintf(&sb, ",min=%d,max=%d,step=%d", ctl.Min, ctl.Max, ctl.Step)
+		case ControlTypeEnumerated:
+			fmt.Fprintf(&sb, ",items=%d", len(ctl.Items))
+		}
+		sb.WriteString("\n")
+		for i, item := range ctl.Items {
+			fmt.Fprintf(&sb, "  ; Item #%d '%s'\n", i, item)
+		}
+
+		var parts []string
+		for _, el := range element {
+			value, ok := values[el.Key()]
+			if !ok {
+				break
+			}
+			switch ctl.Type {
+			case ControlTypeBoolean:
+				parts = append(parts, strings.ToLower(el.FormatValue(value)))
+			default:
+				parts = append(parts, strconv.FormatInt(value, 10))
+			}
+		}
+		if len(parts) == len(element) {
+			fmt.Fprintf(&sb, "  : values=%s\n", strings.Join(parts, ","))
+		}
+	}
+
+	_, err = io.WriteString(w, sb.String())
+	return err
+}
+
+// dumpElements returns the card's controls grouped into elements, with values
+func (c *Card) dumpElements() ([][]*Control, map[ControlKey]int64, error) {
+	controls, err := c.GetControls()
+	if err != nil {
+		return nil, nil, err
+	}
+	return GroupElements(controls), c.readValues(controls), nil
+}
+
+// alsactlValues formats an element's values as alsactl writes them: booleans
+// as true/false, enum items by name, and IEC958 status as hex
+func alsactlValues(element []*Control, values map[ControlKey]int64) []string {
+	ctl := element[0]
+	if ctl.Type == ControlTypeIEC958 {
+		status, err := ctl.GetIEC958Status()
+		if err != nil {
+			return nil
+		}
+		return []string{hex.EncodeToString(status.Raw)}
+	}
+
+	var result []string
+	for _, el := range element {
+		value, ok := values[el.Key()]
+		if !ok {
+			return nil
+		}
+		switch ctl.Type {
+		case ControlTypeBoolean:
+			result = append(result, strconv.FormatBool(value != 0))
+		case ControlTypeEnumerated:
+			result = append(result, alsaQuote(el.FormatValue(value)))
+		default:
+			result = append(result, strconv.FormatInt(value, 10))
+		}
+	}
+	return result
+}
+
+// alsaID returns the card's ALSA id (e.g. "USB"), which names its alsactl
+// state block, falling back to the card name without spaces
+func (c *Card) alsaID() string {
+	if id, err := getCardID(c.Number); err == nil && id != "" {
+		return id
+	}
+	return strings.ReplaceAll(c.Name, " ", "")
+}
+
+// alsactlAccess describes a control's access as alsactl does
+func alsactlAccess(ctl *Control) string {
+	if ctl.Writable {
+		return "read write"
+	}
+	return "read"
+}
+
+// amixerAccess describes a control's access as amixer does
+func amixerAccess(ctl *Control) string {
+	if ctl.Writable {
+		return "rw------"
+	}
+	return "r-------"
+}
+
+// alsaQuote quotes a string as alsa-lib configuration files do: bare when it
+// is a plain word, otherwise in single quotes with escapes
+func alsaQuote(s string) string {
+	plain := s != "" && (s[0] < '0' || s[0] > '9')
+	for _, r := range s {
+		if r <= 31 || r >= 127 || strings.ContainsRune(" #,;={}.'\"\\[]", r) {
+			plain = false
+			break
+		}
+	}
+	if plain {
+		return s
+	}
+
+	var sb strings.Builder
+	sb.WriteByte('\'')
+	for _, r := range s {
+		switch r {
+		case '\'', '\\':
+			sb.WriteByte('\\')
+			sb.WriteRune(r)
+		case '\n':
+			sb.WriteString(`\n`)
+		case '\t':
+			sb.WriteString(`\t`)
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	sb.WriteByte('\'')
+	return sb.String()
+}
@@ -0,0 +1,164 @@
+package scarlettctl
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// AutomationEvent is a single recorded control change
+type AutomationEvent struct {
+	Elapsed time.Duration `json:"elapsed"` // time since recording started
+	NumID   uint          `json:"numid"`
+	ID      string        `json:"id"` // full control ID, used to resolve the control on playback
+	Value   int64         `json:"value"`
+}
+
+// EventRecorder logs the control changes seen by an event monitor, with their
+// timing, as JSON lines of AutomationEvent for later replay
+type EventRecorder struct {
+	monitor *EventMonitor
+	w       io.Writer
+}
+
+// NewEventRecorder creates a recorder writing the monitor's changes to w
+func NewEventRecorder(em *EventMonitor, w io.Writer) *EventRecorder {
+	return &EventRecorder{monitor: em, w: w}
+}
+
+// Record writes every control change to the recorder's writer until Stop is
+// called. Level meters are not recorded.
+func (er *EventRecorder) Record() error {
+	card := er.monitor.card
+
+	// start from the current state so only real changes are recorded
+	last, err := card.ReadAllValues()
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(er.w)
+	start := time.Now()
+
+	return er.monitor.WatchControls(func(control *Control, value int64) error {
+		if isMeter(control) {
+			return nil
+		}
+
+		key := control.Key()
+		if lastValue, exists := last[key]; exists && lastValue == value {
+			return nil
+		}
+		last[key] = value
+
+		return enc.Encode(AutomationEvent{
+			Elapsed: time.Since(start),
+			NumID:   control.NumID,
+			ID:      control.FullID(),
+			Value:   value,
+		})
+	})
+}
+
+// Stop ends the recording
+func (er *EventRecorder) Stop() {
+	er.monitor.Stop()
+}
+
+// RecordAutomation writes every control change seen by the monitor to w as
+// JSON lines until the monitor is stopped. Level meters are not recorded.
+func (em *EventMonitor) RecordAutomation(w io.Writer) error {
+	return NewEventRecorder(em, w).Record()
+}
+
+// PlayAutomation replays a recording made by RecordAutomation with its original timing
+func (c *Card) PlayAutomation(r io.Reader) error {
+	return c.Replay(r, 1)
+}
+
+// Replay re-applies a recording made by an EventRecorder, scaling its timing
+// by speed (2 plays twice as fast, 0.5 at half speed).
+// Controls are resolved by ID rather than numid so recordings survive reboots;
+// events for controls that no longer exist are skipped, and values are clamped
+// to the control's current range.
+func (c *Card) Replay(r io.Reader, speed float64) error {
+	if speed <= 0 {
+		return fmt.Errorf("invalid replay speed %v", speed)
+	}
+
+	controls, err := c.GetControls()
+	if err != nil {
+		return err
+	}
+
+	byID := make(map[string]*Control, len(controls))
+	for _, ctl := range controls {
+		byID[ctl.FullID()] = ctl
+	}
+
+	scanner := bufio.NewScanner(r)
+	start := time.Now()
+	line := 0
+
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		var event AutomationEvent
+		if err := json.Unmarshal([]byte(text), &event); err != nil {
+			return fmt.Errorf("invalid automation event on line %d: %v", line, err)
+		}
+
+		ctl, ok := byID[event.ID]
+		if !ok {
+			continue // control doesn't exist on this device
+		}
+
+		at := time.Duration(float64(event.Elapsed) / speed)
+		if wait := time.Until(start.Add(at)); wait > 0 {
+			time.Sleep(wait)
+		}
+
+		if err := ctl.SetValue(ctl.clamp(event.Value)); err != nil {
+			return fmt.Errorf("failed to play back %s: %v", event.ID, err)
+		}
+	}
+
+	return scanner.Err()
+}
+
+// clamp limits a value to the control's valid range
+func (ctl *Control) clamp(value int64) int64 {
+	switch ctl.Type {
+	case ControlTypeInteger, ControlTypeInteger64:
+		if value < ctl.Min {
+			return ctl.Min
+		}
+		if value > ctl.Max {
+			return ctl.Max
+		}
+	case ControlTypeEnumerated:
+		if value < 0 {
+			return 0
+		}
+		if value >= int64(len(ctl.Items)) {
+			return int64(len(ctl.Items)) - 1
+		}
+	case ControlTypeBoolean:
+		if value != 0 {
+			return 1
+		}
+	}
+	return value
+}
+
+// isMeter checks if a control is a level meter, whose value changes constantly
+func isMeter(ctl *Control) bool {
+	return strings.Contains(ctl.Name, "Meter")
+}
@@ -0,0 +1,111 @@
+package scarlettctl
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// monitor button controls on the larger interfaces. These are whole-monitor
+// switches, distinct from the per-channel "Line NN Mute Playback Switch".
+var (
+	talkbackRe   = regexp.MustCompile(`^Talkback (?:Playback )?(?:Switch|Enum)$`)
+	dimRe        = regexp.MustCompile(`^Dim (?:Playback )?Switch$`)
+	masterMuteRe = regexp.MustCompile(`^Mute (?:Playback )?Switch$`)
+)
+
+// findButton locates a monitor button control by name
+func (c *Card) findButton(re *regexp.Regexp, what string) (*Control, error) {
+	controls, err := c.GetControls()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ctl := range controls {
+		if ctl.Type != ControlTypeBoolean && ctl.Type != ControlTypeEnumerated {
+			continue
+		}
+		if re.MatchString(ctl.Name) {
+			return ctl, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%s: %w", what, ErrNotSupported)
+}
+
+// getButton reads a button control as on/off
+func (c *Card) getButton(re *regexp.Regexp, what string) (bool, error) {
+	ctl, err := c.findButton(re, what)
+	if err != nil {
+		return false, err
+	}
+
+	value, err := ctl.GetValue()
+	if err != nil {
+		return false, err
+	}
+
+	if ctl.Type == ControlTypeEnumerated {
+		return strings.EqualFold(ctl.FormatValue(value), "On"), nil
+	}
+	return value != 0, nil
+}
+
+// setButton turns a button control on or off. Enumerated buttons (e.g. talkback
+// as "Disabled"/"Off"/"On") are set by item name.
+func (c *Card) setButton(re *regexp.Regexp, what string, enabled bool) error {
+	ctl, err := c.findButton(re, what)
+	if err != nil {
+		return err
+	}
+
+	if ctl.Type == ControlTypeBoolean {
+		var value int64
+		if enabled {
+			value = 1
+		}
+		return ctl.SetValue(value)
+	}
+
+	state := "Off"
+	if enabled {
+		state = "On"
+	}
+	for i, item := range ctl.Items {
+		if strings.EqualFold(item, state) {
+			return ctl.SetValue(int64(i))
+		}
+	}
+
+	return fmt.Errorf("%s has no '%s' state (valid: %s)", ctl.Name, state, strings.Join(ctl.Items, ", "))
+}
+
+// GetTalkback returns whether talkback is on
+func (c *Card) GetTalkback() (bool, error) {
+	return c.getButton(talkbackRe, "talkback")
+}
+
+// SetTalkback turns talkback on or off
+func (c *Card) SetTalkback(enabled bool) error {
+	return c.setButton(talkbackRe, "talkback", enabled)
+}
+
+// GetDim returns whether the monitor outputs are dimmed
+func (c *Card) GetDim() (bool, error) {
+	return c.getButton(dimRe, "dim")
+}
+
+// SetDim dims or undims the monitor outputs
+func (c *Card) SetDim(enabled bool) error {
+	return c.setButton(dimRe, "dim", enabled)
+}
+
+// GetMasterMute returns whether the monitor outputs are muted
+func (c *Card) GetMasterMute() (bool, error) {
+	return c.getButton(masterMuteRe, "master mute")
+}
+
+// SetMasterMute mutes or unmutes the monitor outputs
+func (c *Card) SetMasterMute(enabled bool) error {
+	return c.setButton(masterMuteRe, "master mute", enabled)
+}
@@ -0,0 +1,49 @@
+package scarlettctl
+
+// EnableCache turns value caching on or off
+// With caching on, GetValue returns the last value read or written for a control
+// instead of querying the hardware. Writes through the card update the cache, and
+// an event monitor on the card invalidates it whenever the hardware reports a
+// change, so values stay current as long as a monitor is running or all changes
+// go through this card. Disabling the cache discards it.
+func (c *Card) EnableCache(enabled bool) {
+	c.cacheMu.Lock()
+	defer c.cacheMu.Unlock()
+
+	if !enabled {
+		c.cache = nil
+		return
+	}
+	if c.cache == nil {
+		c.cache = make(map[ControlKey]int64)
+	}
+}
+
+// InvalidateCache discards all cached values
+func (c *Card) InvalidateCache() {
+	c.cacheMu.Lock()
+	defer c.cacheMu.Unlock()
+
+	if c.cache != nil {
+		c.cache = make(map[ControlKey]int64)
+	}
+}
+
+// cachedValue returns the cached value for a control, if any
+func (c *Card) cachedValue(key ControlKey) (int64, bool) {
+	c.cacheMu.Lock()
+	defer c.cacheMu.Unlock()
+
+	value, ok := c.cache[key]
+	return value, ok
+}
+
+// storeValue records a value in the cache when caching is enabled
+func (c *Card) storeValue(key ControlKey, value int64) {
+	c.cacheMu.Lock()
+	defer c.cacheMu.Unlock()
+
+	if c.cache != nil {
+		c.cache[key] = value
+	}
+}
@@ -0,0 +1,62 @@
+package scarlettctl
+
+import "testing"
+
+func TestCacheServesWrittenValue(t *testing.T) {
+	card, dev := newFakeCard(t,
+		fakeElement{numid: 1, name: "Line 01 (Monitor L) Playback Volume", typ: ControlTypeInteger, max: 127},
+	)
+	card.EnableCache(true)
+
+	ctl, err := card.FindControl("Line 01 (Monitor L) Playback Volume")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ctl.SetValue(100); err != nil {
+		t.Fatal(err)
+	}
+
+	readsBefore, _ := dev.counts()
+	value, err := ctl.GetValue()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value != 100 {
+		t.Errorf("GetValue() = %d, want 100", value)
+	}
+	if reads, _ := dev.counts(); reads != readsBefore {
+		t.Errorf("GetValue read the hardware %d times, want it served from the cache", reads-readsBefore)
+	}
+
+	// a change the cache doesn't know about is only seen once it's invalidated
+	dev.values[1][0] = 50
+	if value, _ := ctl.GetValue(); value != 100 {
+		t.Errorf("GetValue() = %d before invalidating, want the cached 100", value)
+	}
+	card.InvalidateCache()
+	if value, _ := ctl.GetValue(); value != 50 {
+		t.Errorf("GetValue() = %d after invalidating, want 50", value)
+	}
+}
+
+func TestCacheDisabledReadsHardware(t *testing.T) {
+	card, dev := newFakeCard(t,
+		fakeElement{numid: 1, name: "Line 01 (Monitor L) Playback Volume", typ: ControlTypeInteger, max: 127},
+	)
+
+	ctl, err := card.FindControl("Line 01 (Monitor L) Playback Volume")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ctl.SetValue(100); err != nil {
+		t.Fatal(err)
+	}
+
+	readsBefore, _ := dev.counts()
+	if _, err := ctl.GetValue(); err != nil {
+		t.Fatal(err)
+	}
+	if reads, _ := dev.counts(); reads != readsBefore+1 {
+		t.Errorf("GetValue made %d hardware reads, want 1", reads-readsBefore)
+	}
+}
@@ -1,13 +1,28 @@
 package scarlettctl
 
 import (
+	"context"
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // OpenCard opens an ALSA control connection to the specified card number
 func OpenCard(cardNum int) (*Card, error) {
-	handle, err := openCard(cardNum)
+	return openCardNumber(cardNum, true)
+}
+
+// OpenCardWithoutEvents opens a card like OpenCard but without subscribing to
+// control events or fetching poll descriptors, which one-shot reads and writes
+// don't need. Event monitors on the card fall back to polling control values.
+func OpenCardWithoutEvents(cardNum int) (*Card, error) {
+	return openCardNumber(cardNum, false)
+}
+
+// openCardNumber opens a card by number, subscribing to events if asked
+func openCardNumber(cardNum int, subscribe bool) (*Card, error) {
+	handle, err := openCard(cardNum, subscribe)
 	if err != nil {
 		return nil, err
 	}
@@ -17,20 +32,190 @@ func OpenCard(cardNum int) (*Card, error) {
 		closeCard(handle)
 		return nil, err
 	}
+	logger.Debug("opened card", "number", cardNum, "name", name, "events", subscribe)
 
 	return &Card{
 		Number: cardNum,
 		Name:   name,
+		Device: fmt.Sprintf("hw:%d", cardNum),
+		handle: handle,
+	}, nil
+}
+
+// OpenCardByName opens an ALSA control connection to an arbitrary control device
+// such as "hw:USB", "hw:CARD=USB" or a remote "aserver" device. PCM device strings
+// like "plughw:1" are mapped to the control device of the underlying card. Unlike
+// FindCard, the device is not required to be a Focusrite interface.
+func OpenCardByName(device string) (*Card, error) {
+	return openCardDevice(device, true)
+}
+
+// openCardDevice opens a card by control device, subscribing to events if asked
+func openCardDevice(device string, subscribe bool) (*Card, error) {
+	device = controlDevice(device)
+
+	handle, err := openDevice(device, subscribe)
+	if err != nil {
+		return nil, err
+	}
+
+	name, number, err := getDeviceInfo(device)
+	if err != nil {
+		closeCard(handle)
+		return nil, err
+	}
+	logger.Debug("opened device", "device", device, "number", number, "name", name, "events", subscribe)
+
+	return &Card{
+		Number: number,
+		Name:   name,
+		Device: device,
 		handle: handle,
 	}, nil
 }
 
+// OpenCardByID opens the card with the given ALSA id (e.g. "USB", as in
+// /proc/asound/cards), matched case-insensitively. Unlike the card number, the
+// id is stable across reboots and replugs. As with OpenCardByName, the card is
+// not required to be a Focusrite interface.
+func OpenCardByID(id string) (*Card, error) {
+	cardNumbers, err := listCardNumbers()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, i := range cardNumbers {
+		cardID, err := getCardID(i)
+		if err != nil {
+			logger.Debug("skipping inaccessible card", "number", i, "error", err)
+			continue
+		}
+		if strings.EqualFold(cardID, id) {
+			return OpenCard(i)
+		}
+	}
+
+	return nil, fmt.Errorf("no card with id '%s' found", id)
+}
+
+// controlDevice maps a PCM device string to the control device of its card
+// plughw has no control counterpart; it addresses the same card as hw.
+func controlDevice(device string) string {
+	if strings.HasPrefix(device, "plughw:") {
+		return "hw:" + strings.TrimPrefix(device, "plughw:")
+	}
+	return device
+}
+
+// isDeviceString reports whether identifier names an ALSA device rather than a
+// card number or name
+func isDeviceString(identifier string) bool {
+	return strings.HasPrefix(identifier, "hw:") || strings.HasPrefix(identifier, "plughw:")
+}
+
 // Close closes the connection to the card
+// Closing more than once is harmless; after Close, operations on the card and
+// its controls fail with ErrClosed. Close doesn't wait for a hardware call that
+// was abandoned after a timeout and is still stuck; the handle is closed in the
+// background once that call returns.
 func (c *Card) Close() error {
-	if c.handle == nil {
+	c.handleMu.Lock()
+	handle, stalled := c.handle, c.stalled > 0
+	c.handle = nil
+	c.handleMu.Unlock()
+
+	if handle == nil {
 		return nil
 	}
-	return closeCard(c.handle)
+	if stalled {
+		logger.Debug("deferring close until stalled call returns", "card", c.Name)
+		go hardware.close(handle)
+		return nil
+	}
+	return hardware.close(handle)
+}
+
+// checkOpen returns ErrClosed once the card has been closed
+func (c *Card) checkOpen() error {
+	_, err := c.openHandle()
+	return err
+}
+
+// openHandle returns the card's ALSA handle, or ErrClosed once the card has
+// been closed
+func (c *Card) openHandle() (*alsaHandle, error) {
+	c.handleMu.Lock()
+	defer c.handleMu.Unlock()
+
+	if c.handle == nil {
+		return nil, ErrClosed
+	}
+	return c.handle, nil
+}
+
+// SetTimeout bounds how long each hardware call (enumeration, read, write) may
+// take before it is abandoned with an error. A zero duration disables the limit.
+// An abandoned call keeps running in the background, so a timed-out write may
+// still take effect, and later calls wait for it to finish before starting;
+// Close doesn't.
+func (c *Card) SetTimeout(d time.Duration) {
+	c.timeout = d
+}
+
+// SetDryRun puts the card in dry-run mode: writes are validated and passed to
+// report with the control's current and intended values, but nothing is written.
+// A nil report turns dry-run mode off.
+func (c *Card) SetDryRun(report func(ctl *Control, oldValue, newValue int64)) {
+	c.dryRun = report
+}
+
+// SetVerify makes every write read the control back from the hardware and fail
+// with ErrVerifyFailed if the device didn't apply it (see SetValueVerified)
+func (c *Card) SetVerify(verify bool) {
+	c.verify = verify
+}
+
+// call runs a hardware operation on the card's handle, racing it against the
+// card's timeout
+func (c *Card) call(fn func(h *alsaHandle) error) error {
+	h, err := c.openHandle()
+	if err != nil {
+		return err
+	}
+	if c.timeout <= 0 {
+		return fn(h)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- fn(h)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		c.stall(done)
+		return fmt.Errorf("hardware call timed out after %v: %w", c.timeout, ctx.Err())
+	}
+}
+
+// stall counts a call abandoned after a timeout as stalled until it returns, so
+// Close knows not to wait on the handle it holds
+func (c *Card) stall(done <-chan error) {
+	c.handleMu.Lock()
+	c.stalled++
+	c.handleMu.Unlock()
+
+	go func() {
+		<-done
+		c.handleMu.Lock()
+		c.stalled--
+		c.handleMu.Unlock()
+	}()
 }
 
 // String returns a string representation of the card
@@ -51,6 +236,7 @@ func ListCards() ([]*Card, error) {
 	for _, i := range cardNumbers {
 		name, err := getCardInfo(i)
 		if err != nil {
+			logger.Debug("skipping inaccessible card", "number", i, "error", err)
 			continue // card can't be accessed
 		}
 
@@ -63,6 +249,7 @@ func ListCards() ([]*Card, error) {
 			cards = append(cards, &Card{
 				Number: i,
 				Name:   name,
+				Device: fmt.Sprintf("hw:%d", i),
 			})
 		}
 	}
@@ -74,35 +261,87 @@ func ListCards() ([]*Card, error) {
 	return cards, nil
 }
 
-// FindCard finds a card by number or name substring
+// FindCard finds a card by number, ALSA id or name substring
+// Device strings of the form "hw:..." or "plughw:..." are opened directly with
+// OpenCardByName.
 func FindCard(identifier string) (*Card, error) {
+	return findCard(identifier, true)
+}
+
+// FindCardWithoutEvents finds a card like FindCard, opening it without
+// subscribing to control events (see OpenCardWithoutEvents)
+func FindCardWithoutEvents(identifier string) (*Card, error) {
+	return findCard(identifier, false)
+}
+
+// findCard finds and opens a card, subscribing to events if asked
+func findCard(identifier string, subscribe bool) (*Card, error) {
+	if isDeviceString(identifier) {
+		return openCardDevice(identifier, subscribe)
+	}
+
+	card, err := LookupCard(identifier)
+	if err != nil {
+		return nil, err
+	}
+	return openCardNumber(card.Number, subscribe)
+}
+
+// LookupCard finds the card FindCard would open, without opening it. The card
+// returned only describes the device; it has no control connection.
+func LookupCard(identifier string) (*Card, error) {
+	if isDeviceString(identifier) {
+		device := controlDevice(identifier)
+		name, number, err := getDeviceInfo(device)
+		if err != nil {
+			return nil, err
+		}
+		return &Card{Number: number, Name: name, Device: device}, nil
+	}
+
 	cards, err := ListCards()
 	if err != nil {
 		return nil, err
 	}
 
 	// try parsing as card number
-	var cardNum int
-	if _, err := fmt.Sscanf(identifier, "%d", &cardNum); err == nil {
+	if cardNum, err := strconv.Atoi(identifier); err == nil {
 		for _, card := range cards {
 			if card.Number == cardNum {
-				return OpenCard(card.Number)
+				return card, nil
 			}
 		}
 		return nil, fmt.Errorf("card %d not found", cardNum)
 	}
 
+	// try matching by ALSA id
+	for _, card := range cards {
+		if id, err := getCardID(card.Number); err == nil && strings.EqualFold(id, identifier) {
+			return card, nil
+		}
+	}
+
 	// try matching by name substring
 	identifierLower := strings.ToLower(identifier)
 	for _, card := range cards {
 		if strings.Contains(strings.ToLower(card.Name), identifierLower) {
-			return OpenCard(card.Number)
+			return card, nil
 		}
 	}
 
 	return nil, fmt.Errorf("no card matching '%s' found", identifier)
 }
 
+// ApplyToAll runs fn against each card in turn, continuing past failures
+// The returned slice holds each card's error (nil on success) in card order.
+func ApplyToAll(cards []*Card, fn func(*Card) error) []error {
+	errs := make([]error, len(cards))
+	for i, card := range cards {
+		errs[i] = fn(card)
+	}
+	return errs
+}
+
 // IsScarlett checks if this card is a supported Scarlett device
 func (c *Card) IsScarlett() bool {
 	nameLower := strings.ToLower(c.Name)
@@ -113,8 +352,9 @@ func (c *Card) IsScarlett() bool {
 
 // GetPollFds returns the file descriptors to poll for events
 func (c *Card) GetPollFds() []int {
-	if c.handle == nil {
+	h, err := c.openHandle()
+	if err != nil {
 		return nil
 	}
-	return c.handle.pollFds
+	return h.pollFds
 }
@@ -0,0 +1,165 @@
+package scarlettctl
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCloseDoesNotWaitForStalledCall(t *testing.T) {
+	handle := &alsaHandle{}
+	card := &Card{Name: "test", handle: handle}
+	card.SetTimeout(10 * time.Millisecond)
+
+	// a wedged call holds the handle until released
+	release := make(chan struct{})
+	defer close(release)
+	err := card.call(func(h *alsaHandle) error {
+		handle.mu.Lock()
+		defer handle.mu.Unlock()
+		<-release
+		return nil
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected a timeout, got %v", err)
+	}
+
+	closed := make(chan error, 1)
+	go func() {
+		closed <- card.Close()
+	}()
+	select {
+	case err := <-closed:
+		if err != nil {
+			t.Fatalf("close failed: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("close blocked on the stalled call")
+	}
+
+	if err := card.checkOpen(); !errors.Is(err, ErrClosed) {
+		t.Fatalf("expected ErrClosed after close, got %v", err)
+	}
+}
+
+func TestCloseTwice(t *testing.T) {
+	card, _ := newFakeCard(t,
+		fakeElement{numid: 1, name: "Line In 1 Gain Capture Volume", typ: ControlTypeInteger, max: 70},
+	)
+	if _, err := card.GetControls(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := card.Close(); err != nil {
+		t.Fatalf("first close failed: %v", err)
+	}
+	if err := card.Close(); err != nil {
+		t.Fatalf("second close failed: %v", err)
+	}
+
+	if _, err := card.GetControls(); !errors.Is(err, ErrClosed) {
+		t.Fatalf("expected ErrClosed from GetControls, got %v", err)
+	}
+	if fds := card.GetPollFds(); fds != nil {
+		t.Fatalf("expected no poll fds after close, got %v", fds)
+	}
+}
+
+func TestCloseWhileReadingHandle(t *testing.T) {
+	card := &Card{Name: "test", handle: &alsaHandle{pollFds: []int{3}}}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 1000; i++ {
+			card.GetPollFds()
+			card.call(func(h *alsaHandle) error {
+				if h == nil {
+					t.Error("call ran without a handle")
+				}
+				return nil
+			})
+		}
+	}()
+	card.Close()
+	<-done
+}
+
+// benchmarkOpenAndGet opens the first Scarlett card with open, reads one
+// control and closes it again, as a one-shot 'get' does. Skips when no card is
+// attached.
+func benchmarkOpenAndGet(b *testing.B, open func(cardNum int) (*Card, error)) {
+	cards, err := ListCards()
+	if err != nil || len(cards) == 0 {
+		b.Skip("no Scarlett card attached")
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		card, err := open(cards[0].Number)
+		if err != nil {
+			b.Fatal(err)
+		}
+		controls, err := card.GetControls()
+		if err != nil || len(controls) == 0 {
+			b.Fatalf("no controls: %v", err)
+		}
+		if _, err := controls[0].GetValue(); err != nil {
+			b.Fatal(err)
+		}
+		card.Close()
+	}
+}
+
+func BenchmarkOpenAndGet(b *testing.B) {
+	benchmarkOpenAndGet(b, OpenCard)
+}
+
+func BenchmarkOpenAndGetWithoutEvents(b *testing.B) {
+	benchmarkOpenAndGet(b, OpenCardWithoutEvents)
+}
+
+func TestCloseDuringStalledCall(t *testing.T) {
+	card, dev := newFakeCard(t,
+		fakeElement{numid: 1, name: "Line In 1 Gain Capture Volume", typ: ControlTypeInteger, max: 70},
+	)
+	ctl, err := card.FindControl("Line In 1 Gain Capture Volume")
+	if err != nil {
+		t.Fatal(err)
+	}
+	handle := card.handle
+
+	dev.stall = make(chan struct{})
+	card.SetTimeout(10 * time.Millisecond)
+	if _, err := ctl.GetValue(); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected a timeout, got %v", err)
+	}
+
+	// Close leaves the handle to be closed once the stalled read lets go of it
+	if err := card.Close(); err != nil {
+		t.Fatal(err)
+	}
+	close(dev.stall)
+	deadline := time.Now().Add(time.Second)
+	for {
+		handle.mu.Lock()
+		closed := handle.ptr == 0
+		handle.mu.Unlock()
+		if closed {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("handle was never closed")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// a call that got the handle before Close must fail rather than use it
+	if _, err := hardware.read(handle, ctl); !errors.Is(err, ErrClosed) {
+		t.Fatalf("expected ErrClosed from a call on the closed handle, got %v", err)
+	}
+	if _, err := ctl.GetValue(); !errors.Is(err, ErrClosed) {
+		t.Fatalf("expected ErrClosed from GetValue, got %v", err)
+	}
+}
@@ -26,29 +26,43 @@ static int get_enum_item_name(snd_ctl_t *handle, snd_ctl_elem_info_t *info, unsi
 import "C"
 import (
 	"fmt"
+	"math"
 	"unsafe"
 )
 
-// alsaError converts ALSA error codes to Go errors
+// alsaError converts ALSA error codes to Go errors, wrapping a sentinel such
+// as ErrDeviceDisconnected for codes that have one
 func alsaError(code C.int, operation string) error {
 	if code >= 0 {
 		return nil
 	}
 	errStr := C.GoString(C.snd_strerror(code))
+	if sentinel := alsaSentinel(int(code)); sentinel != nil {
+		return fmt.Errorf("%s: %s: %w", operation, errStr, sentinel)
+	}
 	return fmt.Errorf("%s: %s", operation, errStr)
 }
 
 // openCard opens an ALSA control handle for the specified card number
-func openCard(cardNum int) (*alsaHandle, error) {
+func openCard(cardNum int, subscribe bool) (*alsaHandle, error) {
+	return openDevice(fmt.Sprintf("hw:%d", cardNum), subscribe)
+}
+
+// openDevice opens an ALSA control handle for the specified control device.
+// Without subscribe, control events aren't requested and no poll descriptors
+// are fetched, which one-shot reads and writes don't need.
+func openDevice(device string, subscribe bool) (*alsaHandle, error) {
 	var handle *C.snd_ctl_t
-	cardName := fmt.Sprintf("hw:%d", cardNum)
-	cCardName := C.CString(cardName)
+	cCardName := C.CString(device)
 	defer C.free(unsafe.Pointer(cCardName))
 
 	err := C.snd_ctl_open(&handle, cCardName, 0)
 	if err < 0 {
 		return nil, alsaError(err, "open card")
 	}
+	if !subscribe {
+		return &alsaHandle{ptr: uintptr(unsafe.Pointer(handle))}, nil
+	}
 
 	// subscribe to events
 	err = C.snd_ctl_subscribe_events(handle, 1)
@@ -57,11 +71,15 @@ func openCard(cardNum int) (*alsaHandle, error) {
 		return nil, alsaError(err, "subscribe to events")
 	}
 
-	// get poll descriptors
+	// get poll descriptors; some drivers provide none, in which case the
+	// event monitor falls back to polling control values
 	count := C.snd_ctl_poll_descriptors_count(handle)
-	if count <= 0 {
+	if count < 0 {
 		C.snd_ctl_close(handle)
-		return nil, fmt.Errorf("no poll descriptors available")
+		return nil, alsaError(count, "count poll descriptors")
+	}
+	if count == 0 {
+		return &alsaHandle{ptr: uintptr(unsafe.Pointer(handle))}, nil
 	}
 
 	pfds := make([]C.struct_pollfd, count)
@@ -84,7 +102,13 @@ func openCard(cardNum int) (*alsaHandle, error) {
 
 // closeCard closes an ALSA control handle
 func closeCard(h *alsaHandle) error {
-	if h == nil || h.ptr == 0 {
+	if h == nil {
+		return nil
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.ptr == 0 {
 		return nil
 	}
 	handle := (*C.snd_ctl_t)(unsafe.Pointer(h.ptr))
@@ -95,13 +119,45 @@ func closeCard(h *alsaHandle) error {
 
 // getCardInfo retrieves card information
 func getCardInfo(cardNum int) (string, error) {
+	name, _, err := getDeviceInfo(fmt.Sprintf("hw:%d", cardNum))
+	return name, err
+}
+
+// getDeviceInfo retrieves the card name and number behind a control device
+func getDeviceInfo(device string) (string, int, error) {
 	var info *C.snd_ctl_card_info_t
 	C.snd_ctl_card_info_malloc(&info)
 	defer C.snd_ctl_card_info_free(info)
 
 	var handle *C.snd_ctl_t
-	cardName := fmt.Sprintf("hw:%d", cardNum)
-	cCardName := C.CString(cardName)
+	cCardName := C.CString(device)
+	defer C.free(unsafe.Pointer(cCardName))
+
+	err := C.snd_ctl_open(&handle, cCardName, 0)
+	if err < 0 {
+		return "", 0, alsaError(err, "open card for info")
+	}
+	defer C.snd_ctl_close(handle)
+
+	err = C.snd_ctl_card_info(handle, info)
+	if err < 0 {
+		return "", 0, alsaError(err, "get card info")
+	}
+
+	name := C.GoString(C.snd_ctl_card_info_get_name(info))
+	number := int(C.snd_ctl_card_info_get_card(info))
+	return name, number, nil
+}
+
+// getCardID retrieves the ALSA id of a card (e.g. "USB"), which stays the same
+// when the card's number changes
+func getCardID(cardNum int) (string, error) {
+	var info *C.snd_ctl_card_info_t
+	C.snd_ctl_card_info_malloc(&info)
+	defer C.snd_ctl_card_info_free(info)
+
+	var handle *C.snd_ctl_t
+	cCardName := C.CString(fmt.Sprintf("hw:%d", cardNum))
 	defer C.free(unsafe.Pointer(cCardName))
 
 	err := C.snd_ctl_open(&handle, cCardName, 0)
@@ -115,12 +171,68 @@ func getCardInfo(cardNum int) (string, error) {
 		return "", alsaError(err, "get card info")
 	}
 
-	name := C.GoString(C.snd_ctl_card_info_get_name(info))
-	return name, nil
+	return C.GoString(C.snd_ctl_card_info_get_id(info)), nil
+}
+
+// countControls returns the number of control elements on the card, without
+// querying any of them
+func countControls(h *alsaHandle) (int, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.ptr == 0 {
+		return 0, ErrClosed
+	}
+
+	handle := (*C.snd_ctl_t)(unsafe.Pointer(h.ptr))
+	var list *C.snd_ctl_elem_list_t
+	C.snd_ctl_elem_list_malloc(&list)
+	defer C.snd_ctl_elem_list_free(list)
+
+	err := C.snd_ctl_elem_list(handle, list)
+	if err < 0 {
+		return 0, alsaError(err, "get element list")
+	}
+	return int(C.snd_ctl_elem_list_get_count(list)), nil
+}
+
+// elementsWritable reports whether each element is currently writable. Unlike
+// the rest of an element's metadata, write access can change at runtime.
+func elementsWritable(h *alsaHandle, numids []uint) ([]bool, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.ptr == 0 {
+		return nil, ErrClosed
+	}
+
+	handle := (*C.snd_ctl_t)(unsafe.Pointer(h.ptr))
+	var info *C.snd_ctl_elem_info_t
+	C.snd_ctl_elem_info_malloc(&info)
+	defer C.snd_ctl_elem_info_free(info)
+
+	writable := make([]bool, len(numids))
+	for i, numid := range numids {
+		C.snd_ctl_elem_info_clear(info)
+		C.snd_ctl_elem_info_set_numid(info, C.uint(numid))
+		if err := C.snd_ctl_elem_info(handle, info); err < 0 {
+			return nil, alsaError(err, "query control")
+		}
+		writable[i] = C.snd_ctl_elem_info_is_writable(info) != 0
+	}
+	return writable, nil
 }
 
-// enumerateControls lists all controls on a card
-func enumerateControls(h *alsaHandle) ([]*Control, error) {
+// enumerateControls lists all controls on a card, along with the elements that
+// couldn't be queried
+func enumerateControls(h *alsaHandle) ([]*Control, []ControlError, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.ptr == 0 {
+		return nil, nil, ErrClosed
+	}
+
 	handle := (*C.snd_ctl_t)(unsafe.Pointer(h.ptr))
 	var info *C.snd_ctl_elem_info_t
 	C.snd_ctl_elem_info_malloc(&info)
@@ -132,22 +244,23 @@ func enumerateControls(h *alsaHandle) ([]*Control, error) {
 
 	err := C.snd_ctl_elem_list(handle, list)
 	if err < 0 {
-		return nil, alsaError(err, "get element list")
+		return nil, nil, alsaError(err, "get element list")
 	}
 
 	count := C.snd_ctl_elem_list_get_count(list)
 	err = C.snd_ctl_elem_list_alloc_space(list, count)
 	if err < 0 {
-		return nil, alsaError(err, "allocate element list space")
+		return nil, nil, alsaError(err, "allocate element list space")
 	}
 	defer C.snd_ctl_elem_list_free_space(list)
 
 	err = C.snd_ctl_elem_list(handle, list)
 	if err < 0 {
-		return nil, alsaError(err, "fill element list")
+		return nil, nil, alsaError(err, "fill element list")
 	}
 
 	controls := make([]*Control, 0, count)
+	var failed []ControlError
 
 	for i := C.uint(0); i < count; i++ {
 		numid := C.snd_ctl_elem_list_get_numid(list, i)
@@ -155,7 +268,13 @@ func enumerateControls(h *alsaHandle) ([]*Control, error) {
 		C.snd_ctl_elem_info_set_numid(info, numid)
 		err = C.snd_ctl_elem_info(handle, info)
 		if err < 0 {
-			continue // skip controls we can't query
+			// skip controls we can't query, reporting them to the caller
+			failed = append(failed, ControlError{
+				NumID: uint(numid),
+				Name:  C.GoString(C.snd_ctl_elem_list_get_name(list, i)),
+				Err:   alsaError(err, "query control"),
+			})
+			continue
 		}
 
 		// get basic info
@@ -167,6 +286,7 @@ func enumerateControls(h *alsaHandle) ([]*Control, error) {
 		ctlInterface := InterfaceType(C.snd_ctl_elem_info_get_interface(info))
 		ctlDevice := uint(C.snd_ctl_elem_info_get_device(info))
 		ctlSubdevice := uint(C.snd_ctl_elem_info_get_subdevice(info))
+		ctlWritable := C.snd_ctl_elem_info_is_writable(info) != 0
 
 		// create control for each value in multi-value controls
 		for idx := 0; idx < ctlCount; idx++ {
@@ -179,6 +299,7 @@ func enumerateControls(h *alsaHandle) ([]*Control, error) {
 				Interface: ctlInterface,
 				Device:    ctlDevice,
 				Subdevice: ctlSubdevice,
+				Writable:  ctlWritable,
 			}
 
 			// get type-specific information
@@ -186,10 +307,12 @@ func enumerateControls(h *alsaHandle) ([]*Control, error) {
 			case ControlTypeInteger:
 				ctl.Min = int64(C.snd_ctl_elem_info_get_min(info))
 				ctl.Max = int64(C.snd_ctl_elem_info_get_max(info))
+				ctl.Step = int64(C.snd_ctl_elem_info_get_step(info))
 
 			case ControlTypeInteger64:
 				ctl.Min = int64(C.snd_ctl_elem_info_get_min64(info))
 				ctl.Max = int64(C.snd_ctl_elem_info_get_max64(info))
+				ctl.Step = int64(C.snd_ctl_elem_info_get_step64(info))
 
 			case ControlTypeEnumerated:
 				itemCount := C.snd_ctl_elem_info_get_items(info)
@@ -207,11 +330,18 @@ func enumerateControls(h *alsaHandle) ([]*Control, error) {
 		}
 	}
 
-	return controls, nil
+	return controls, failed, nil
 }
 
 // readControl reads the current value of a control
 func readControl(h *alsaHandle, ctl *Control) (int64, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.ptr == 0 {
+		return 0, ErrClosed
+	}
+
 	handle := (*C.snd_ctl_t)(unsafe.Pointer(h.ptr))
 	var value *C.snd_ctl_elem_value_t
 	C.snd_ctl_elem_value_malloc(&value)
@@ -240,8 +370,115 @@ func readControl(h *alsaHandle, ctl *Control) (int64, error) {
 	return int64(result), nil
 }
 
+// readElement reads every value of an element with a single ALSA read
+func readElement(h *alsaHandle, numid uint, ctlType ControlType, count int) ([]int64, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.ptr == 0 {
+		return nil, ErrClosed
+	}
+
+	handle := (*C.snd_ctl_t)(unsafe.Pointer(h.ptr))
+	var value *C.snd_ctl_elem_value_t
+	C.snd_ctl_elem_value_malloc(&value)
+	defer C.snd_ctl_elem_value_free(value)
+
+	C.snd_ctl_elem_value_set_numid(value, C.uint(numid))
+	err := C.snd_ctl_elem_read(handle, value)
+	if err < 0 {
+		return nil, alsaError(err, "read control")
+	}
+
+	values := make([]int64, count)
+	for i := 0; i < count; i++ {
+		idx := C.uint(i)
+		switch ctlType {
+		case ControlTypeBoolean:
+			values[i] = int64(C.snd_ctl_elem_value_get_boolean(value, idx))
+		case ControlTypeInteger:
+			values[i] = int64(C.snd_ctl_elem_value_get_integer(value, idx))
+		case ControlTypeEnumerated:
+			values[i] = int64(C.snd_ctl_elem_value_get_enumerated(value, idx))
+		case ControlTypeInteger64:
+			values[i] = int64(C.snd_ctl_elem_value_get_integer64(value, idx))
+		default:
+			return nil, fmt.Errorf("unsupported control type: %v", ctlType)
+		}
+	}
+
+	return values, nil
+}
+
+// readIEC958 reads the channel status bytes of an IEC958 element
+func readIEC958(h *alsaHandle, numid uint) ([]byte, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.ptr == 0 {
+		return nil, ErrClosed
+	}
+
+	handle := (*C.snd_ctl_t)(unsafe.Pointer(h.ptr))
+	var value *C.snd_ctl_elem_value_t
+	C.snd_ctl_elem_value_malloc(&value)
+	defer C.snd_ctl_elem_value_free(value)
+
+	C.snd_ctl_elem_value_set_numid(value, C.uint(numid))
+	err := C.snd_ctl_elem_read(handle, value)
+	if err < 0 {
+		return nil, alsaError(err, "read control")
+	}
+
+	var iec C.snd_aes_iec958_t
+	C.snd_ctl_elem_value_get_iec958(value, &iec)
+	return C.GoBytes(unsafe.Pointer(&iec.status[0]), C.int(len(iec.status))), nil
+}
+
+// readTLV reads an element's raw TLV data
+func readTLV(h *alsaHandle, numid uint) ([]byte, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.ptr == 0 {
+		return nil, ErrClosed
+	}
+
+	handle := (*C.snd_ctl_t)(unsafe.Pointer(h.ptr))
+	var id *C.snd_ctl_elem_id_t
+	C.snd_ctl_elem_id_malloc(&id)
+	defer C.snd_ctl_elem_id_free(id)
+
+	C.snd_ctl_elem_id_set_numid(id, C.uint(numid))
+
+	buf := make([]C.uint, maxTLVSize/4)
+	err := C.snd_ctl_elem_tlv_read(handle, id, &buf[0], C.uint(maxTLVSize))
+	if err == -C.ENXIO {
+		// the element has no TLV data
+		return nil, fmt.Errorf("read tlv: numid %d has no tlv: %w", numid, ErrNotSupported)
+	}
+	if err < 0 {
+		return nil, alsaError(err, "read tlv")
+	}
+
+	// the top-level header gives the payload length in bytes
+	size := tlvHeaderSize + int(buf[1])
+	if size > maxTLVSize {
+		size = maxTLVSize
+	}
+
+	return C.GoBytes(unsafe.Pointer(&buf[0]), C.int(size)), nil
+}
+
 // writeControl writes a value to a control
 func writeControl(h *alsaHandle, ctl *Control, value int64) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.ptr == 0 {
+		return ErrClosed
+	}
+
 	handle := (*C.snd_ctl_t)(unsafe.Pointer(h.ptr))
 	var elemValue *C.snd_ctl_elem_value_t
 	C.snd_ctl_elem_value_malloc(&elemValue)
@@ -255,6 +492,17 @@ func writeControl(h *alsaHandle, ctl *Control, value int64) error {
 	}
 
 	// set the new value
+	if err := setElemValue(elemValue, ctl, value); err != nil {
+		return err
+	}
+
+	// write it back
+	err = C.snd_ctl_elem_write(handle, elemValue)
+	return alsaError(err, "write control")
+}
+
+// setElemValue sets a control's value in an element value container
+func setElemValue(elemValue *C.snd_ctl_elem_value_t, ctl *Control, value int64) error {
 	switch ctl.Type {
 	case ControlTypeBoolean:
 		C.snd_ctl_elem_value_set_boolean(elemValue, C.uint(ctl.Index), C.long(value))
@@ -267,34 +515,82 @@ func writeControl(h *alsaHandle, ctl *Control, value int64) error {
 	default:
 		return fmt.Errorf("unsupported control type for writing: %v", ctl.Type)
 	}
+	return nil
+}
 
-	// write it back
-	err = C.snd_ctl_elem_write(handle, elemValue)
+// newElemValue allocates an element value container for repeated writes to a
+// control, returned as a uintptr like alsaHandle.ptr
+func newElemValue(ctl *Control) (uintptr, error) {
+	var elemValue *C.snd_ctl_elem_value_t
+	if err := C.snd_ctl_elem_value_malloc(&elemValue); err < 0 {
+		return 0, alsaError(err, "allocate element value")
+	}
+	C.snd_ctl_elem_value_set_numid(elemValue, C.uint(ctl.NumID))
+	return uintptr(unsafe.Pointer(elemValue)), nil
+}
+
+// freeElemValue frees a container from newElemValue
+func freeElemValue(v uintptr) {
+	C.snd_ctl_elem_value_free((*C.snd_ctl_elem_value_t)(unsafe.Pointer(v)))
+}
+
+// writeElemValue writes a value through a container from newElemValue. The
+// element is only read first when it holds other values (count > 1) that the
+// write must preserve.
+func writeElemValue(h *alsaHandle, v uintptr, ctl *Control, value int64) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.ptr == 0 {
+		return ErrClosed
+	}
+
+	handle := (*C.snd_ctl_t)(unsafe.Pointer(h.ptr))
+	elemValue := (*C.snd_ctl_elem_value_t)(unsafe.Pointer(v))
+
+	if ctl.Count > 1 {
+		if err := C.snd_ctl_elem_read(handle, elemValue); err < 0 {
+			return alsaError(err, "read before write")
+		}
+	}
+
+	if err := setElemValue(elemValue, ctl, value); err != nil {
+		return err
+	}
+
+	err := C.snd_ctl_elem_write(handle, elemValue)
 	return alsaError(err, "write control")
 }
 
-// checkEvent checks if there's a pending event
-func checkEvent(h *alsaHandle) (bool, error) {
+// checkEvent reads a pending event, returning the numid of the element it
+// reports a change to; ok is false when no element event was pending
+func checkEvent(h *alsaHandle) (numid uint, ok bool, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.ptr == 0 {
+		return 0, false, ErrClosed
+	}
+
 	handle := (*C.snd_ctl_t)(unsafe.Pointer(h.ptr))
 	var event *C.snd_ctl_event_t
 	C.snd_ctl_event_malloc(&event)
 	defer C.snd_ctl_event_free(event)
 
-	err := C.snd_ctl_read(handle, event)
-	if err < 0 {
-		if err == -C.EAGAIN {
-			return false, nil // no event available
+	code := C.snd_ctl_read(handle, event)
+	if code < 0 {
+		if code == -C.EAGAIN {
+			return 0, false, nil // no event available
 		}
-		return false, alsaError(err, "read event")
+		return 0, false, alsaError(code, "read event")
 	}
 
 	// check if it's an element event
-	eventType := C.snd_ctl_event_get_type(event)
-	if eventType == C.SND_CTL_EVENT_ELEM {
-		return true, nil
+	if C.snd_ctl_event_get_type(event) != C.SND_CTL_EVENT_ELEM {
+		return 0, false, nil
 	}
 
-	return false, nil
+	return uint(C.snd_ctl_event_elem_get_numid(event)), true, nil
 }
 
 // listCardNumbers returns the indices of all available ALSA cards
@@ -316,6 +612,218 @@ func listCardNumbers() ([]int, error) {
 	return cards, nil
 }
 
+// openSimpleMixer opens an ALSA simple mixer on a control device, returned in
+// an alsaHandle whose ptr is the snd_mixer_t*
+func openSimpleMixer(device string) (*alsaHandle, error) {
+	var mixer *C.snd_mixer_t
+	if err := C.snd_mixer_open(&mixer, 0); err < 0 {
+		return nil, alsaError(err, "open mixer")
+	}
+
+	cDevice := C.CString(device)
+	defer C.free(unsafe.Pointer(cDevice))
+
+	if err := C.snd_mixer_attach(mixer, cDevice); err < 0 {
+		C.snd_mixer_close(mixer)
+		return nil, alsaError(err, "attach mixer")
+	}
+	if err := C.snd_mixer_selem_register(mixer, nil, nil); err < 0 {
+		C.snd_mixer_close(mixer)
+		return nil, alsaError(err, "register simple mixer")
+	}
+	if err := C.snd_mixer_load(mixer); err < 0 {
+		C.snd_mixer_close(mixer)
+		return nil, alsaError(err, "load mixer")
+	}
+
+	return &alsaHandle{ptr: uintptr(unsafe.Pointer(mixer))}, nil
+}
+
+// closeSimpleMixer closes a simple mixer from openSimpleMixer
+func closeSimpleMixer(h *alsaHandle) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.ptr == 0 {
+		return nil
+	}
+	err := C.snd_mixer_close((*C.snd_mixer_t)(unsafe.Pointer(h.ptr)))
+	h.ptr = 0
+	return alsaError(err, "close mixer")
+}
+
+// listSimpleElements returns the active elements of a simple mixer. A volume
+// element's range and dB range are those of its playback side when it has one,
+// otherwise of its capture side.
+func listSimpleElements(h *alsaHandle) []*SimpleElement {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.ptr == 0 {
+		return nil
+	}
+
+	mixer := (*C.snd_mixer_t)(unsafe.Pointer(h.ptr))
+	var elements []*SimpleElement
+	for elem := C.snd_mixer_first_elem(mixer); elem != nil; elem = C.snd_mixer_elem_next(elem) {
+		if C.snd_mixer_selem_is_active(elem) == 0 {
+			continue
+		}
+
+		e := &SimpleElement{
+			Name:           C.GoString(C.snd_mixer_selem_get_name(elem)),
+			Index:          int(C.snd_mixer_selem_get_index(elem)),
+			PlaybackVolume: C.snd_mixer_selem_has_playback_volume(elem) != 0,
+			CaptureVolume:  C.snd_mixer_selem_has_capture_volume(elem) != 0,
+			PlaybackSwitch: C.snd_mixer_selem_has_playback_switch(elem) != 0,
+			CaptureSwitch:  C.snd_mixer_selem_has_capture_switch(elem) != 0,
+			elem:           uintptr(unsafe.Pointer(elem)),
+		}
+
+		var min, max C.long
+		switch {
+		case e.PlaybackVolume:
+			C.snd_mixer_selem_get_playback_volume_range(elem, &min, &max)
+			e.Min, e.Max = int64(min), int64(max)
+			if C.snd_mixer_selem_get_playback_dB_range(elem, &min, &max) >= 0 {
+				e.HasDB, e.MinDB, e.MaxDB = true, float64(min)/100, float64(max)/100
+			}
+		case e.CaptureVolume:
+			C.snd_mixer_selem_get_capture_volume_range(elem, &min, &max)
+			e.Min, e.Max = int64(min), int64(max)
+			if C.snd_mixer_selem_get_capture_dB_range(elem, &min, &max) >= 0 {
+				e.HasDB, e.MinDB, e.MaxDB = true, float64(min)/100, float64(max)/100
+			}
+		}
+
+		elements = append(elements, e)
+	}
+
+	return elements
+}
+
+// simpleMixerElem locks a simple mixer, brings its element values up to date
+// with pending hardware events, and returns the element. On success the mixer
+// is left locked for the caller to unlock.
+func simpleMixerElem(h *alsaHandle, elem uintptr) (*C.snd_mixer_elem_t, error) {
+	h.mu.Lock()
+	if h.ptr == 0 {
+		h.mu.Unlock()
+		return nil, fmt.Errorf("simple mixer is closed")
+	}
+	if err := C.snd_mixer_handle_events((*C.snd_mixer_t)(unsafe.Pointer(h.ptr))); err < 0 {
+		h.mu.Unlock()
+		return nil, alsaError(err, "handle mixer events")
+	}
+	return (*C.snd_mixer_elem_t)(unsafe.Pointer(elem)), nil
+}
+
+// simpleChannel is the channel read from a simple mixer element; joined
+// channels all hold the same value
+const simpleChannel = C.snd_mixer_selem_channel_id_t(C.SND_MIXER_SCHN_MONO)
+
+// simpleGetVolume reads a simple element's raw volume
+func simpleGetVolume(h *alsaHandle, elem uintptr, capture bool) (int64, error) {
+	e, err := simpleMixerElem(h, elem)
+	if err != nil {
+		return 0, err
+	}
+	defer h.mu.Unlock()
+
+	var value C.long
+	var rc C.int
+	if capture {
+		rc = C.snd_mixer_selem_get_capture_volume(e, simpleChannel, &value)
+	} else {
+		rc = C.snd_mixer_selem_get_playback_volume(e, simpleChannel, &value)
+	}
+	return int64(value), alsaError(rc, "get volume")
+}
+
+// simpleSetVolume sets a simple element's raw volume on all channels
+func simpleSetVolume(h *alsaHandle, elem uintptr, capture bool, value int64) error {
+	e, err := simpleMixerElem(h, elem)
+	if err != nil {
+		return err
+	}
+	defer h.mu.Unlock()
+
+	if capture {
+		return alsaError(C.snd_mixer_selem_set_capture_volume_all(e, C.long(value)), "set volume")
+	}
+	return alsaError(C.snd_mixer_selem_set_playback_volume_all(e, C.long(value)), "set volume")
+}
+
+// simpleGetDB reads a simple element's volume in dB
+func simpleGetDB(h *alsaHandle, elem uintptr, capture bool) (float64, error) {
+	e, err := simpleMixerElem(h, elem)
+	if err != nil {
+		return 0, err
+	}
+	defer h.mu.Unlock()
+
+	var value C.long
+	var rc C.int
+	if capture {
+		rc = C.snd_mixer_selem_get_capture_dB(e, simpleChannel, &value)
+	} else {
+		rc = C.snd_mixer_selem_get_playback_dB(e, simpleChannel, &value)
+	}
+	return float64(value) / 100, alsaError(rc, "get dB")
+}
+
+// simpleSetDB sets a simple element's volume in dB on all channels, rounding
+// down to the nearest step
+func simpleSetDB(h *alsaHandle, elem uintptr, capture bool, db float64) error {
+	e, err := simpleMixerElem(h, elem)
+	if err != nil {
+		return err
+	}
+	defer h.mu.Unlock()
+
+	centi := C.long(math.Round(db * 100))
+	if capture {
+		return alsaError(C.snd_mixer_selem_set_capture_dB_all(e, centi, -1), "set dB")
+	}
+	return alsaError(C.snd_mixer_selem_set_playback_dB_all(e, centi, -1), "set dB")
+}
+
+// simpleGetSwitch reads a simple element's switch
+func simpleGetSwitch(h *alsaHandle, elem uintptr, capture bool) (bool, error) {
+	e, err := simpleMixerElem(h, elem)
+	if err != nil {
+		return false, err
+	}
+	defer h.mu.Unlock()
+
+	var value C.int
+	var rc C.int
+	if capture {
+		rc = C.snd_mixer_selem_get_capture_switch(e, simpleChannel, &value)
+	} else {
+		rc = C.snd_mixer_selem_get_playback_switch(e, simpleChannel, &value)
+	}
+	return value != 0, alsaError(rc, "get switch")
+}
+
+// simpleSetSwitch sets a simple element's switch on all channels
+func simpleSetSwitch(h *alsaHandle, elem uintptr, capture bool, on bool) error {
+	e, err := simpleMixerElem(h, elem)
+	if err != nil {
+		return err
+	}
+	defer h.mu.Unlock()
+
+	var value C.int
+	if on {
+		value = 1
+	}
+	if capture {
+		return alsaError(C.snd_mixer_selem_set_capture_switch_all(e, value), "set switch")
+	}
+	return alsaError(C.snd_mixer_selem_set_playback_switch_all(e, value), "set switch")
+}
+
 // cstrlen finds the length of a null-terminated C string in a byte slice
 func cstrlen(b []byte) int {
 	for i, c := range b {
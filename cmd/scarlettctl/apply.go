@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/michaelquigley/scarlettctl"
+	"github.com/spf13/cobra"
+)
+
+var applyCmd = &cobra.Command{
+	Use:   "apply <card>",
+	Short: "Bring the card to the state in the startup config",
+	Long: `Bring the card to the state described by a config file
+(default ~/.config/scarlettctl/startup.yaml), writing only the declared
+settings that differ, and list those that drifted. Every setting is resolved
+before anything is written, so a config naming a missing control changes
+nothing.
+
+With --oneshot the command waits up to --wait for the card to appear, which
+suits a systemd oneshot unit started at boot or by udev when the interface is
+plugged in.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, _ := cmd.Flags().GetString("file")
+		if path == "" {
+			var err error
+			if path, err = scarlettctl.DefaultConfigPath(); err != nil {
+				return err
+			}
+		}
+
+		cfg, err := scarlettctl.LoadDeviceConfig(path)
+		if err != nil {
+			return err
+		}
+
+		var card *scarlettctl.Card
+		if oneshot, _ := cmd.Flags().GetBool("oneshot"); oneshot {
+			wait, _ := cmd.Flags().GetDuration("wait")
+			card, err = waitForCard(cmd, args[0], wait)
+		} else {
+			card, err = findCard(cmd, args[0])
+		}
+		if err != nil {
+			return err
+		}
+		defer card.Close()
+
+		drift, err := card.CheckConfig(*cfg)
+		if err != nil {
+			return err
+		}
+		if len(drift) == 0 {
+			fmt.Printf("%s already matches %s\n", card, path)
+			return nil
+		}
+		for _, d := range drift {
+			fmt.Printf("  %s\n", d)
+		}
+
+		if check, _ := cmd.Flags().GetBool("check"); check {
+			fmt.Printf("%d setting(s) differ from %s\n", len(drift), path)
+			return nil
+		}
+
+		if err := card.ApplyConfig(*cfg); err != nil {
+			return err
+		}
+		fmt.Printf("applied %d setting(s) from %s to %s\n", len(drift), path, card)
+		return nil
+	},
+}
+
+// waitForCard retries opening a card until it appears or wait runs out
+func waitForCard(cmd *cobra.Command, identifier string, wait time.Duration) (*scarlettctl.Card, error) {
+	deadline := time.Now().Add(wait)
+	for {
+		card, err := findCard(cmd, identifier)
+		if err == nil {
+			return card, nil
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("card '%s' did not appear within %v: %v", identifier, wait, err)
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+}
+
+func init() {
+	applyCmd.Flags().String("file", "", "Config file (default ~/.config/scarlettctl/startup.yaml)")
+	applyCmd.Flags().Bool("check", false, "Only list the settings that differ, without writing")
+	applyCmd.Flags().Bool("oneshot", false, "Wait for the card to appear before applying, for systemd units")
+	applyCmd.Flags().Duration("wait", 30*time.Second, "How long --oneshot waits for the card")
+
+	rootCmd.AddCommand(applyCmd)
+}
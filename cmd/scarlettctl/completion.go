@@ -0,0 +1,171 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/michaelquigley/scarlettctl"
+	"github.com/spf13/cobra"
+)
+
+var completionCmd = &cobra.Command{
+	Use:   "completion <bash|zsh|fish|powershell>",
+	Short: "Generate a shell completion script",
+	Long: `Generate a shell completion script for scarlettctl.
+
+To load completions in the current bash session:
+
+  source <(scarlettctl completion bash)
+
+Control and routing names are completed dynamically from the card named
+earlier on the command line.`,
+	Args:                  cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+	ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+	DisableFlagsInUseLine: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		switch args[0] {
+		case "bash":
+			return rootCmd.GenBashCompletionV2(os.Stdout, true)
+		case "zsh":
+			return rootCmd.GenZshCompletion(os.Stdout)
+		case "fish":
+			return rootCmd.GenFishCompletion(os.Stdout, true)
+		case "powershell":
+			return rootCmd.GenPowerShellCompletionWithDesc(os.Stdout)
+		}
+		return fmt.Errorf("unsupported shell: %s", args[0])
+	},
+}
+
+// completeCards completes the card argument with detected card numbers
+func completeCards(toComplete string) ([]string, cobra.ShellCompDirective) {
+	cards, err := scarlettctl.ListCards()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var completions []string
+	for _, card := range cards {
+		number := fmt.Sprintf("%d", card.Number)
+		if strings.HasPrefix(number, toComplete) {
+			completions = append(completions, number+"\t"+card.Name)
+		}
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completionCard opens the card named by the first argument, returning nil if
+// it can't be resolved yet (e.g. the user is still typing it)
+func completionCard(args []string) *scarlettctl.Card {
+	if len(args) == 0 {
+		return nil
+	}
+	card, err := scarlettctl.FindCardWithoutEvents(args[0])
+	if err != nil {
+		return nil
+	}
+	return card
+}
+
+// completeUnique filters names by prefix, dropping duplicates
+func completeUnique(names []string, toComplete string) []string {
+	seen := make(map[string]bool)
+	var completions []string
+	for _, name := range names {
+		if seen[name] || !strings.HasPrefix(strings.ToLower(name), strings.ToLower(toComplete)) {
+			continue
+		}
+		seen[name] = true
+		completions = append(completions, name)
+	}
+	return completions
+}
+
+// completeControls completes "<card> <control-name> [value]" arguments
+func completeControls(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		return completeCards(toComplete)
+	}
+
+	card := completionCard(args)
+	if card == nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	defer card.Close()
+
+	switch len(args) {
+	case 1:
+		controls, err := card.GetControls()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		names := make([]string, 0, len(controls))
+		for _, ctl := range controls {
+			names = append(names, ctl.Name)
+		}
+		return completeUnique(names, toComplete), cobra.ShellCompDirectiveNoFileComp
+
+	case 2:
+		// only "set" takes a value; offer the valid values for the control
+		if cmd != setCmd {
+			break
+		}
+		ctl, err := card.FindControl(args[1])
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		switch ctl.Type {
+		case scarlettctl.ControlTypeBoolean:
+			return completeUnique([]string{"on", "off"}, toComplete), cobra.ShellCompDirectiveNoFileComp
+		case scarlettctl.ControlTypeEnumerated:
+			return completeUnique(ctl.Items, toComplete), cobra.ShellCompDirectiveNoFileComp
+		}
+	}
+
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeRoute completes "<card> <sink> <source>" arguments
+func completeRoute(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		return completeCards(toComplete)
+	}
+
+	card := completionCard(args)
+	if card == nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	defer card.Close()
+
+	var names []string
+	switch len(args) {
+	case 1:
+		sinks, err := card.GetRoutingSinks()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		for _, sink := range sinks {
+			names = append(names, sink.Name)
+		}
+
+	case 2:
+		sources, err := card.GetRoutingSources()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		for _, src := range sources {
+			names = append(names, src.Name)
+		}
+	}
+
+	return completeUnique(names, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+func init() {
+	getCmd.ValidArgsFunction = completeControls
+	setCmd.ValidArgsFunction = completeControls
+	routeCmd.ValidArgsFunction = completeRoute
+
+	rootCmd.AddCommand(completionCmd)
+}
@@ -0,0 +1,251 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/michaelquigley/scarlettctl"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+// cliConfig holds persistent defaults for the command line, read from
+// ~/.config/scarlettctl/config.yaml. Flags and arguments given on the command
+// line override it.
+//
+//	card: USB                  # used when a command's <card> is left out
+//	output: json               # default for --output
+//	aliases:
+//	  vox: Line In 1 Gain Capture Volume
+//	watch:
+//	  exclude: Meter           # defaults for watch --match and --exclude
+//	gain_caps:
+//	  1: 40                    # refuse gains above 40 on input 1 without --force
+//	undo_depth: 20             # writes kept for 'scarlettctl undo', per card
+//	discovery_cache: true      # default for --discovery-cache
+type cliConfig struct {
+	Card    string            `yaml:"card"`
+	Output  string            `yaml:"output"`
+	Aliases map[string]string `yaml:"aliases"`
+	Watch   struct {
+		Match   string `yaml:"match"`
+		Exclude string `yaml:"exclude"`
+	} `yaml:"watch"`
+	GainCaps       map[int]int64 `yaml:"gain_caps"`
+	UndoDepth      int           `yaml:"undo_depth"`
+	DiscoveryCache bool          `yaml:"discovery_cache"`
+}
+
+// controlAliases maps lowercased alias names to control names, from the config
+var controlAliases = map[string]string{}
+
+// gainCaps are the per-channel gain caps set on every card opened, from the config
+var gainCaps = map[int]int64{}
+
+// cliConfigPath returns the location of the CLI config file
+func cliConfigPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "scarlettctl", "config.yaml"), nil
+}
+
+// LoadConfig reads the CLI config file; a missing file gives an empty config
+func LoadConfig(path string) (*cliConfig, error) {
+	cfg := &cliConfig{}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config file '%s': %v", path, err)
+	}
+	return cfg, nil
+}
+
+// setupConfig loads the CLI config and applies it as flag defaults, aliases
+// and a default card, before the command line is parsed
+func setupConfig() error {
+	cfg := &cliConfig{}
+	if path, err := cliConfigPath(); err == nil {
+		if cfg, err = LoadConfig(path); err != nil {
+			return err
+		}
+	}
+
+	if cfg.Output != "" {
+		if err := setFlagDefault(rootCmd.PersistentFlags().Lookup("output"), cfg.Output); err != nil {
+			return err
+		}
+	}
+	if cfg.Watch.Match != "" {
+		if err := setFlagDefault(watchCmd.Flags().Lookup("match"), cfg.Watch.Match); err != nil {
+			return err
+		}
+	}
+	if cfg.Watch.Exclude != "" {
+		if err := setFlagDefault(watchCmd.Flags().Lookup("exclude"), cfg.Watch.Exclude); err != nil {
+			return err
+		}
+	}
+
+	if cfg.DiscoveryCache {
+		if err := setFlagDefault(rootCmd.PersistentFlags().Lookup("discovery-cache"), "true"); err != nil {
+			return err
+		}
+	}
+
+	for alias, name := range cfg.Aliases {
+		controlAliases[strings.ToLower(alias)] = name
+	}
+
+	for channel, max := range cfg.GainCaps {
+		gainCaps[channel] = max
+	}
+	if cfg.UndoDepth > 0 {
+		undoDepth = cfg.UndoDepth
+	}
+
+	useDefaultCard(rootCmd, defaultCard(cfg))
+	return nil
+}
+
+// defaultCardEnv names the environment variable giving the default card
+const defaultCardEnv = "SCARLETTCTL_CARD"
+
+// defaultCard returns the card to use when a command's <card> is left out:
+// $SCARLETTCTL_CARD, then the config's card, then the first Scarlett detected.
+// Detection only happens when a card is actually needed.
+func defaultCard(cfg *cliConfig) func() (string, error) {
+	return func() (string, error) {
+		if card := os.Getenv(defaultCardEnv); card != "" {
+			return card, nil
+		}
+		if cfg.Card != "" {
+			return cfg.Card, nil
+		}
+
+		cards, err := scarlettctl.ListCards()
+		if err != nil {
+			return "", err
+		}
+		if len(cards) == 0 {
+			return "", fmt.Errorf("no card given and no Scarlett interface detected")
+		}
+		return strconv.Itoa(cards[0].Number), nil
+	}
+}
+
+// setFlagDefault changes a flag's default, which a value given on the command
+// line still overrides
+func setFlagDefault(flag *pflag.Flag, value string) error {
+	if err := flag.Value.Set(value); err != nil {
+		return fmt.Errorf("invalid config value for --%s: %v", flag.Name, err)
+	}
+	flag.DefValue = value
+	return nil
+}
+
+// useDefaultCard lets every command taking a <card> leave it out (see
+// cardArguments)
+func useDefaultCard(cmd *cobra.Command, card func() (string, error)) {
+	for _, sub := range cmd.Commands() {
+		useDefaultCard(sub, card)
+	}
+	if !strings.Contains(cmd.Use, "<card>") || cmd.RunE == nil {
+		return
+	}
+
+	validate, run := cmd.Args, cmd.RunE
+	if validate == nil {
+		validate = cobra.ArbitraryArgs
+	}
+
+	cmd.Args = func(cmd *cobra.Command, args []string) error {
+		if fitsWithCard(cmd, validate, args) {
+			return nil
+		}
+		return validate(cmd, args)
+	}
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		args, err := cardArguments(cmd, validate, args, card)
+		if err != nil {
+			return err
+		}
+		return run(cmd, args)
+	}
+}
+
+// cardArguments puts the default card in front of a command's arguments when
+// they leave the card out. Arguments that only fit the command with a card in
+// front get the default card. Where they fit either way, as 'speakers alt' and
+// 'speakers USB' both do, the first is taken as the card only if it names a
+// connected one.
+func cardArguments(cmd *cobra.Command, validate cobra.PositionalArgs, args []string, card func() (string, error)) ([]string, error) {
+	if !fitsWithCard(cmd, validate, args) {
+		return args, nil
+	}
+	if validate(cmd, args) == nil && len(args) > 0 && namesCard(args[0]) {
+		return args, nil
+	}
+
+	identifier, err := card()
+	if err != nil {
+		return nil, err
+	}
+	return append([]string{identifier}, args...), nil
+}
+
+// fitsWithCard reports whether the arguments fit the command with a card put
+// in front; argument validation only counts, so a placeholder stands in for it
+func fitsWithCard(cmd *cobra.Command, validate cobra.PositionalArgs, args []string) bool {
+	return validate(cmd, append([]string{"0"}, args...)) == nil
+}
+
+// lookupCard finds the card an argument names without opening it
+var lookupCard = scarlettctl.LookupCard
+
+// namesCard reports whether an argument names a connected card: by number, ALSA
+// id or device, or by whole words of its name such as "2i2", but not by a
+// fragment of a word, so that "on" isn't taken for a "Vocaster One"
+func namesCard(identifier string) bool {
+	card, err := lookupCard(identifier)
+	if err != nil {
+		return false
+	}
+	if _, err := strconv.Atoi(identifier); err == nil || strings.Contains(identifier, ":") {
+		return true
+	}
+	if !strings.Contains(strings.ToLower(card.Name), strings.ToLower(identifier)) {
+		return true // matched the ALSA id
+	}
+	return containsWords(card.Name, identifier)
+}
+
+// containsWords reports whether the words of s appear in a row among the words
+// of name, ignoring case
+func containsWords(name, s string) bool {
+	nameWords, words := strings.Fields(strings.ToLower(name)), strings.Fields(strings.ToLower(s))
+	if len(words) == 0 {
+		return false
+	}
+	for i := 0; i+len(words) <= len(nameWords); i++ {
+		if slices.Equal(nameWords[i:i+len(words)], words) {
+			return true
+		}
+	}
+	return false
+}
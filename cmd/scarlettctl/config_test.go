@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/michaelquigley/scarlettctl"
+)
+
+// testCards stand in for the connected cards
+var testCards = []*scarlettctl.Card{
+	{Number: 1, Name: "Scarlett 2i2 4th Gen", Device: "hw:1"},
+	{Number: 2, Name: "Vocaster One", Device: "hw:2"},
+}
+
+// testLookupCard matches the test cards by number or name substring, as
+// LookupCard does
+func testLookupCard(identifier string) (*scarlettctl.Card, error) {
+	for _, card := range testCards {
+		if identifier == fmt.Sprint(card.Number) || strings.Contains(strings.ToLower(card.Name), strings.ToLower(identifier)) {
+			return card, nil
+		}
+	}
+	return nil, fmt.Errorf("no card matching '%s' found", identifier)
+}
+
+func TestCardArguments(t *testing.T) {
+	lookupCard = testLookupCard
+	defer func() { lookupCard = scarlettctl.LookupCard }()
+
+	defaultCard := func() (string, error) { return "default", nil }
+
+	tests := []struct {
+		args []string // command name and arguments
+		want []string
+	}{
+		// speakers <card> [main|alt]
+		{[]string{"speakers"}, []string{"default"}},
+		{[]string{"speakers", "alt"}, []string{"default", "alt"}},
+		{[]string{"speakers", "2i2"}, []string{"2i2"}},
+		{[]string{"speakers", "1", "main"}, []string{"1", "main"}},
+
+		// talkback, dim and mute-master <card> [on|off]
+		{[]string{"talkback", "on"}, []string{"default", "on"}},
+		{[]string{"talkback", "vocaster"}, []string{"vocaster"}},
+		{[]string{"dim", "off"}, []string{"default", "off"}},
+		{[]string{"mute-master", "2", "on"}, []string{"2", "on"}},
+
+		// controls <card> [control-name]
+		{[]string{"controls", "Line In 1 Gain Capture Volume"}, []string{"default", "Line In 1 Gain Capture Volume"}},
+		{[]string{"controls", "1"}, []string{"1"}},
+		{[]string{"controls"}, []string{"default"}},
+
+		// input <card> <channels> [mode]
+		{[]string{"input", "1"}, []string{"default", "1"}},
+		{[]string{"input", "1", "inst"}, []string{"1", "inst"}},
+		{[]string{"input", "3", "inst"}, []string{"default", "3", "inst"}},
+		{[]string{"input", "2i2", "1", "inst"}, []string{"2i2", "1", "inst"}},
+
+		// undo <card> [count]
+		{[]string{"undo", "3"}, []string{"default", "3"}},
+		{[]string{"undo", "2i2", "3"}, []string{"2i2", "3"}},
+
+		// scene <card> [name]
+		{[]string{"scene", "tracking"}, []string{"default", "tracking"}},
+		{[]string{"scene", "1", "tracking"}, []string{"1", "tracking"}},
+
+		// monitor <card> [mode]
+		{[]string{"monitor", "stereo"}, []string{"default", "stereo"}},
+		{[]string{"monitor", "vocaster one"}, []string{"vocaster one"}},
+
+		// commands without optional arguments only fill in a missing card
+		{[]string{"get", "vox"}, []string{"default", "vox"}},
+		{[]string{"get", "on", "vox"}, []string{"on", "vox"}},
+	}
+
+	for _, tt := range tests {
+		cmd, args, err := rootCmd.Find(tt.args)
+		if err != nil {
+			t.Fatalf("%v: %v", tt.args, err)
+		}
+		validate := cmd.Args
+		if validate == nil {
+			t.Fatalf("%v: command has no argument validation", tt.args)
+		}
+
+		got, err := cardArguments(cmd, validate, args, defaultCard)
+		if err != nil {
+			t.Errorf("%v: %v", tt.args, err)
+			continue
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%v: got %q, want %q", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestContainsWords(t *testing.T) {
+	tests := []struct {
+		name, s string
+		want    bool
+	}{
+		{"Scarlett 2i2 4th Gen", "2i2", true},
+		{"Scarlett 2i2 4th Gen", "scarlett 2i2", true},
+		{"Scarlett 2i2 4th Gen", "2i2 gen", false},
+		{"Scarlett 2i2 4th Gen", "2i", false},
+		{"Vocaster One", "on", false},
+		{"Vocaster One", "one", true},
+		{"Vocaster One", "", false},
+	}
+
+	for _, tt := range tests {
+		if got := containsWords(tt.name, tt.s); got != tt.want {
+			t.Errorf("containsWords(%q, %q) = %v, want %v", tt.name, tt.s, got, tt.want)
+		}
+	}
+}
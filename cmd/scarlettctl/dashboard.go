@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/michaelquigley/scarlettctl"
+	"github.com/spf13/cobra"
+)
+
+// ansi sequences for the full-screen snapshot view
+const (
+	ansiAltScreen  = "\033[?1049h\033[?25l" // switch to the alternate screen, hide the cursor
+	ansiMainScreen = "\033[?25h\033[?1049l" // show the cursor, back to the main screen
+	ansiHome       = "\033[H"
+	ansiClearBelow = "\033[J"
+	ansiClearLine  = "\033[K"
+)
+
+// snapshotGroups render the groups 'watch --snapshot' can show
+var snapshotGroups = map[string]func(*scarlettctl.Card, *scarlettctl.Renderer) error{
+	"mixer":   (*scarlettctl.Card).RenderMixerState,
+	"preamp":  (*scarlettctl.Card).RenderPreampState,
+	"routing": (*scarlettctl.Card).RenderRoutingMatrix,
+}
+
+// watchSnapshot reprints a group in place every interval, like top, until
+// interrupted. A terminal resize redraws at once at the new width.
+func watchSnapshot(cmd *cobra.Command, card *scarlettctl.Card, sigChan <-chan os.Signal) error {
+	group, _ := cmd.Flags().GetString("group")
+	render, ok := snapshotGroups[strings.ToLower(group)]
+	if !ok {
+		return fmt.Errorf("unknown group '%s' (use mixer, preamp or routing)", group)
+	}
+
+	// --interval is the refresh rate here, a steadier 1s unless given
+	interval := time.Second
+	if cmd.Flags().Changed("interval") {
+		interval, _ = cmd.Flags().GetDuration("interval")
+	}
+	if interval <= 0 {
+		return fmt.Errorf("--interval must be positive")
+	}
+
+	resize := make(chan os.Signal, 1)
+	signal.Notify(resize, syscall.SIGWINCH)
+	defer signal.Stop(resize)
+
+	fmt.Print(ansiAltScreen)
+	defer fmt.Print(ansiMainScreen)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := drawSnapshot(cmd, card, render, interval); err != nil {
+			return err
+		}
+
+		select {
+		case <-ticker.C:
+		case <-resize:
+			// clear everything, as the terminal may have rewrapped the old frame
+			fmt.Print(ansiHome + ansiClearBelow)
+		case <-sigChan:
+			return nil
+		}
+	}
+}
+
+// drawSnapshot renders one frame off-screen, then writes it over the previous
+// one in a single write, so the view doesn't flicker
+func drawSnapshot(cmd *cobra.Command, card *scarlettctl.Card, render func(*scarlettctl.Card, *scarlettctl.Renderer) error, interval time.Duration) error {
+	// size from the terminal on every frame, to follow resizes
+	term := newRenderer(cmd)
+
+	var buf bytes.Buffer
+	r := scarlettctl.NewRenderer(&buf)
+	r.Width, r.Color = term.Width, term.Color
+
+	r.Line(fmt.Sprintf("%s  [%s, every %v, ctrl+c to stop]", card, time.Now().Format("15:04:05"), interval))
+	if err := render(card, r); err != nil {
+		return err
+	}
+
+	// clear the rest of each line, and below the frame, instead of the whole
+	// screen
+	frame := strings.ReplaceAll(buf.String(), "\n", ansiClearLine+"\n")
+	fmt.Print(ansiHome + frame + ansiClearBelow)
+	return nil
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var digitalCmd = &cobra.Command{
+	Use:   "digital <card> [spdif|adat on|off]",
+	Short: "Show S/PDIF and ADAT status, or enable either",
+	Long: `Show the digital I/O mode and the S/PDIF and ADAT ports, with the ADAT
+channels live at the current sample rate. Given a kind and on or off, switch
+the digital I/O mode to one that enables or disables it first; interfaces
+without a mode control can't switch.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 && len(args) != 3 {
+			return fmt.Errorf("accepts a card, optionally followed by spdif|adat and on|off")
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		card, err := findCard(cmd, args[0])
+		if err != nil {
+			return err
+		}
+		defer card.Close()
+
+		if len(args) == 3 {
+			enabled, err := parseOnOff(args[2])
+			if err != nil {
+				return err
+			}
+			if err := card.SetDigitalIOEnabled(args[1], enabled); err != nil {
+				return err
+			}
+		}
+
+		status, err := card.GetDigitalIOStatus()
+		if err != nil {
+			return err
+		}
+
+		if structured(cmd) {
+			return writeResult(cmd, digitalResult{
+				Mode:         status.Mode,
+				Modes:        status.Modes,
+				SPDIFInputs:  status.SPDIFInputs,
+				SPDIFOutputs: status.SPDIFOutputs,
+				ADATInputs:   status.ADATInputs,
+				ADATOutputs:  status.ADATOutputs,
+				ADATLive:     status.ADATLive,
+			})
+		}
+
+		if status.Mode != "" {
+			fmt.Printf("mode:   %s (available: %s)\n", status.Mode, strings.Join(status.Modes, ", "))
+		}
+		fmt.Printf("S/PDIF: %d in, %d out\n", status.SPDIFInputs, status.SPDIFOutputs)
+		adat := fmt.Sprintf("ADAT:   %d in, %d out", status.ADATInputs, status.ADATOutputs)
+		if status.ADATLive != status.ADATInputs {
+			adat += fmt.Sprintf(" (%d inputs live at the current sample rate)", status.ADATLive)
+		}
+		fmt.Println(adat)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(digitalCmd)
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"os"
+
+	"github.com/michaelquigley/scarlettctl"
+	"github.com/spf13/cobra"
+)
+
+var dumpCmd = &cobra.Command{
+	Use:   "dump <card>",
+	Short: "Dump every control in amixer or alsactl format",
+	Long: `Dump every control with its id, type and value in the layout of
+'amixer contents', or with --alsactl in the layout of 'alsactl store', so the
+output can be cross-referenced with the standard ALSA tools and scripts built
+around them. With --output json or yaml, every element is written with its
+values, as by 'controls --collapse'.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		card, err := findCard(cmd, args[0])
+		if err != nil {
+			return err
+		}
+		defer card.Close()
+
+		if structured(cmd) {
+			return writeElements(cmd, card)
+		}
+		if alsactl, _ := cmd.Flags().GetBool("alsactl"); alsactl {
+			return card.WriteALSACtlState(os.Stdout)
+		}
+		return card.WriteAmixerContents(os.Stdout)
+	},
+}
+
+// writeElements writes every control element with its values as a result
+func writeElements(cmd *cobra.Command, card *scarlettctl.Card) error {
+	controls, err := card.GetControls()
+	if err != nil {
+		return err
+	}
+	values, err := card.ReadAllValues()
+	if err != nil {
+		return err
+	}
+
+	elements := scarlettctl.GroupElements(controls)
+	result := make([]elementResult, 0, len(elements))
+	for _, element := range elements {
+		result = append(result, newElementResult(element, values))
+	}
+	return writeResult(cmd, result)
+}
+
+func init() {
+	dumpCmd.Flags().Bool("alsactl", false, "Use the 'alsactl store' state file layout")
+
+	rootCmd.AddCommand(dumpCmd)
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// defaultHealthTimeout bounds each hardware call of 'health' unless --timeout
+// is given, so a wedged interface fails the check instead of hanging it
+const defaultHealthTimeout = "5s"
+
+var healthCmd = &cobra.Command{
+	Use:   "health <card>",
+	Short: "Check that a card responds, for watchdogs and monitoring",
+	Long: `Check that the card opens, its controls enumerate and at least one value
+reads back from the hardware. The exit status is 0 when the card is healthy and
+non-zero otherwise, so the command suits a systemd watchdog or a cron check.
+
+Each hardware call is bounded by --timeout, 5s unless given.`,
+	Args: cobra.ExactArgs(1),
+	// a failed check prints just its status line
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !cmd.Flags().Changed("timeout") {
+			if err := cmd.Flags().Set("timeout", defaultHealthTimeout); err != nil {
+				return err
+			}
+		}
+
+		card, err := findCard(cmd, args[0])
+		if err != nil {
+			return reportHealth(cmd, args[0], err)
+		}
+		defer card.Close()
+
+		return reportHealth(cmd, card.String(), card.HealthCheck())
+	},
+}
+
+// healthResult is the outcome of a health check in structured output
+type healthResult struct {
+	Card    string `json:"card"`
+	Healthy bool   `json:"healthy"`
+	Error   string `json:"error,omitempty"`
+}
+
+// reportHealth prints the outcome of a check, returning an error when it failed
+func reportHealth(cmd *cobra.Command, card string, err error) error {
+	if structured(cmd) {
+		result := healthResult{Card: card, Healthy: err == nil}
+		if err != nil {
+			result.Error = err.Error()
+		}
+		if err := writeResult(cmd, result); err != nil {
+			return err
+		}
+	} else if err == nil {
+		fmt.Printf("ok: %s\n", card)
+	}
+
+	if err != nil {
+		return fmt.Errorf("unhealthy: %s: %v", card, err)
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(healthCmd)
+}
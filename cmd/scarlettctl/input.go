@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var inputCmd = &cobra.Command{
+	Use:   "input <card> <channels> [mode]",
+	Short: "Get or set the input mode (line, instrument, mic) of preamp channels",
+	Long: `Show or select the input source of one or more channels. Channels are a
+number, a range or a list, e.g. 1, 1-2 or 1,3. The mode is validated against
+the channel's input select or level control, e.g. Line, Inst or Instrument;
+interfaces with an impedance switch instead take Line or Inst.`,
+	Args: cobra.RangeArgs(2, 3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		channels, err := parseChannelSpec(args[1])
+		if err != nil {
+			return err
+		}
+
+		card, err := findCard(cmd, args[0])
+		if err != nil {
+			return err
+		}
+		defer card.Close()
+
+		var results []inputResult
+		report := func(channel int, format string) error {
+			mode, err := card.GetInputMode(channel)
+			if err != nil {
+				return err
+			}
+			if structured(cmd) {
+				results = append(results, inputResult{Card: card.Name, Channel: channel, Mode: mode})
+				return nil
+			}
+			fmt.Printf(format, channel, mode)
+			return nil
+		}
+
+		if len(args) == 2 {
+			err = forChannels(channels, func(channel int) error {
+				return report(channel, "channel %d input: %s\n")
+			})
+		} else {
+			err = forChannels(channels, func(channel int) error {
+				if err := card.SetInputMode(channel, args[2]); err != nil {
+					return err
+				}
+				return report(channel, "set input mode for channel %d to '%s'\n")
+			})
+		}
+
+		if structured(cmd) && len(results) > 0 {
+			if werr := writeResult(cmd, results); werr != nil {
+				return werr
+			}
+		}
+		return err
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(inputCmd)
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/michaelquigley/scarlettctl"
+	"github.com/spf13/cobra"
+)
+
+var lockCmd = &cobra.Command{
+	Use:   "lock <card>",
+	Short: "Refuse control writes to a card until it is unlocked",
+	Long: `Lock a card against accidental changes, e.g. during a live show.
+
+While a card is locked every command that writes to it (set, route, gain,
+phantom, scene, ...) fails; reading commands such as get, controls and routing
+keep working. The lock is stored in the state file and lasts until
+'scarlettctl unlock'.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setCardLocked(cmd, args[0], true)
+	},
+}
+
+var unlockCmd = &cobra.Command{
+	Use:   "unlock <card>",
+	Short: "Allow control writes to a locked card again",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setCardLocked(cmd, args[0], false)
+	},
+}
+
+// setCardLocked records a card's lock in the state file
+func setCardLocked(cmd *cobra.Command, identifier string, locked bool) error {
+	card, err := openCardTimeout(cmd, identifier)
+	if err != nil {
+		return err
+	}
+	defer card.Close()
+
+	path, err := scarlettctl.DefaultStatePath()
+	if err != nil {
+		return err
+	}
+
+	state, err := scarlettctl.LoadState(path)
+	if err != nil {
+		return err
+	}
+
+	state.SetLocked(card.StateKey(), locked)
+	if err := state.Save(path); err != nil {
+		return err
+	}
+
+	if locked {
+		fmt.Printf("locked %s; writes are refused until 'scarlettctl unlock'\n", card)
+	} else {
+		fmt.Printf("unlocked %s\n", card)
+	}
+	return nil
+}
+
+// cardLocked reports whether the state file marks the card as locked
+func cardLocked(card *scarlettctl.Card) (bool, error) {
+	path, err := scarlettctl.DefaultStatePath()
+	if err != nil {
+		return false, err
+	}
+
+	state, err := scarlettctl.LoadState(path)
+	if err != nil {
+		return false, err
+	}
+	return state.IsLocked(card.StateKey()), nil
+}
+
+func init() {
+	rootCmd.AddCommand(lockCmd)
+	rootCmd.AddCommand(unlockCmd)
+}
@@ -1,15 +1,24 @@
 package main
 
 import (
+	"bufio"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"log/slog"
 	"os"
 	"os/signal"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"syscall"
+	"time"
 
 	"github.com/michaelquigley/scarlettctl"
 	"github.com/spf13/cobra"
+	"golang.org/x/sys/unix"
 )
 
 var rootCmd = &cobra.Command{
@@ -19,6 +28,39 @@ var rootCmd = &cobra.Command{
 Vocaster, and Clarett audio interfaces via the ALSA control interface.
 
 It provides access to mixer controls, routing, preamp settings, and more.`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := validateOutput(cmd); err != nil {
+			return err
+		}
+		return setupLogging(cmd)
+	},
+}
+
+// setupLogging configures the log level from the global --quiet and --verbose
+// flags: warnings by default, only errors when quiet, and debug detail (every
+// ALSA call and control resolution) when verbose
+func setupLogging(cmd *cobra.Command) error {
+	quiet, _ := cmd.Flags().GetBool("quiet")
+	// 'controls' has its own --verbose for showing values, which shadows the global one
+	verbose := false
+	if flag := cmd.Flags().Lookup("verbose"); flag != nil && flag == cmd.Root().PersistentFlags().Lookup("verbose") {
+		verbose, _ = cmd.Flags().GetBool("verbose")
+	}
+	if quiet && verbose {
+		return fmt.Errorf("--quiet and --verbose are mutually exclusive")
+	}
+
+	level := slog.LevelWarn
+	if quiet {
+		level = slog.LevelError
+	} else if verbose {
+		level = slog.LevelDebug
+	}
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level}))
+	slog.SetDefault(logger)
+	scarlettctl.SetLogger(logger)
+	return nil
 }
 
 var listCmd = &cobra.Command{
@@ -30,6 +72,14 @@ var listCmd = &cobra.Command{
 			return err
 		}
 
+		if structured(cmd) {
+			result := make([]cardResult, 0, len(cards))
+			for _, card := range cards {
+				result = append(result, cardResult{Number: card.Number, Name: card.Name, Device: card.Device})
+			}
+			return writeResult(cmd, result)
+		}
+
 		fmt.Println("available scarlett devices:")
 		for _, card := range cards {
 			fmt.Printf("  %d: %s\n", card.Number, card.Name)
@@ -40,25 +90,96 @@ var listCmd = &cobra.Command{
 }
 
 var controlsCmd = &cobra.Command{
-	Use:   "controls <card>",
+	Use:   "controls <card> [control-name]",
 	Short: "List all controls on a card",
-	Args:  cobra.ExactArgs(1),
+	Args:  cobra.RangeArgs(1, 2),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		card, err := scarlettctl.FindCard(args[0])
+		showTLV, _ := cmd.Flags().GetBool("tlv")
+		if showTLV && len(args) < 2 {
+			return fmt.Errorf("--tlv requires a control name")
+		}
+		if collapse, _ := cmd.Flags().GetBool("collapse"); collapse && (!structured(cmd) || len(args) == 2) {
+			return fmt.Errorf("--collapse applies to listing all controls with --json or --output json/yaml")
+		}
+		byChannel, _ := cmd.Flags().GetBool("by-channel")
+		if byChannel && (structured(cmd) || len(args) == 2) {
+			return fmt.Errorf("--by-channel applies to the text listing of all controls")
+		}
+		hasItem, _ := cmd.Flags().GetString("has-item")
+		if hasItem != "" && len(args) == 2 {
+			return fmt.Errorf("--has-item applies to listing all controls")
+		}
+
+		card, err := findCard(cmd, args[0])
 		if err != nil {
 			return err
 		}
 		defer card.Close()
 
-		controls, err := card.GetControls()
+		if len(args) == 2 {
+			ctl, err := findControl(card, args[1])
+			if err != nil {
+				return err
+			}
+
+			if structured(cmd) {
+				values := make(map[scarlettctl.ControlKey]int64)
+				if value, err := ctl.GetValue(); err == nil {
+					values[ctl.Key()] = value
+				}
+				return writeResult(cmd, newControlResult(ctl, values))
+			}
+
+			fmt.Println(ctl.DetailedString())
+			if min, max, step, err := ctl.DecibelRange(); err == nil {
+				fmt.Printf("  range: %s\n", formatDecibelRange(min, max, step))
+			}
+			if showTLV {
+				return printTLV(ctl)
+			}
+			return nil
+		}
+
+		controls, failed, err := card.GetControlsWithErrors()
 		if err != nil {
 			return err
 		}
+		if showErrors, _ := cmd.Flags().GetBool("show-errors"); showErrors {
+			// on stderr, so structured output stays parseable
+			defer printControlErrors(failed)
+		}
+		if hasItem != "" {
+			ignoreCase, _ := cmd.Flags().GetBool("ignore-case")
+			controls = controlsWithItem(controls, hasItem, ignoreCase)
+			if len(controls) == 0 {
+				return fmt.Errorf("no controls with item '%s' found", hasItem)
+			}
+		}
+
+		if structured(cmd) {
+			values, err := card.ReadAllValues()
+			if err != nil {
+				return err
+			}
+			if collapse, _ := cmd.Flags().GetBool("collapse"); collapse {
+				elements := scarlettctl.GroupElements(controls)
+				result := make([]elementResult, 0, len(elements))
+				for _, element := range elements {
+					result = append(result, newElementResult(element, values))
+				}
+				return writeResult(cmd, result)
+			}
+
+			result := make([]controlResult, 0, len(controls))
+			for _, ctl := range controls {
+				result = append(result, newControlResult(ctl, values))
+			}
+			return writeResult(cmd, result)
+		}
 
 		verbose, _ := cmd.Flags().GetBool("verbose")
 
-		fmt.Printf("controls for %s:\n\n", card)
-		for _, ctl := range controls {
+		printControl := func(ctl *scarlettctl.Control) {
 			if verbose {
 				fmt.Println(ctl.DetailedString())
 			} else {
@@ -66,29 +187,163 @@ var controlsCmd = &cobra.Command{
 			}
 		}
 
+		if hasItem != "" {
+			fmt.Printf("controls for %s with item '%s':\n\n", card, hasItem)
+		} else {
+			fmt.Printf("controls for %s:\n\n", card)
+		}
+		if byChannel {
+			for i, group := range groupByChannel(controls) {
+				if i > 0 {
+					fmt.Println()
+				}
+				fmt.Printf("%s:\n", group.name)
+				for _, ctl := range group.controls {
+					printControl(ctl)
+				}
+			}
+		} else {
+			for _, ctl := range controls {
+				printControl(ctl)
+			}
+		}
+
 		fmt.Printf("\ntotal: %d controls\n", len(controls))
 		return nil
 	},
 }
 
+// controlsWithItem keeps the enumerated controls offering an item, matched
+// case-insensitively if asked
+func controlsWithItem(controls []*scarlettctl.Control, item string, ignoreCase bool) []*scarlettctl.Control {
+	var matched []*scarlettctl.Control
+	for _, ctl := range controls {
+		if ctl.Type != scarlettctl.ControlTypeEnumerated {
+			continue
+		}
+		for _, candidate := range ctl.Items {
+			if candidate == item || (ignoreCase && strings.EqualFold(candidate, item)) {
+				matched = append(matched, ctl)
+				break
+			}
+		}
+	}
+	return matched
+}
+
+// printControlErrors reports the elements enumeration couldn't query
+func printControlErrors(failed []scarlettctl.ControlError) {
+	if len(failed) == 0 {
+		fmt.Fprintln(os.Stderr, "no controls failed to enumerate")
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "\n%d control(s) could not be queried:\n", len(failed))
+	for _, failure := range failed {
+		fmt.Fprintf(os.Stderr, "  %v\n", failure)
+	}
+}
+
+// channelGroup is the controls of one channel, e.g. every "Line In 1" control
+type channelGroup struct {
+	name     string
+	controls []*scarlettctl.Control
+}
+
+// groupByChannel clusters controls by the family and channel parsed from their
+// names, sorted by family then channel number, with the controls whose names
+// carry no channel last under "other"
+func groupByChannel(controls []*scarlettctl.Control) []channelGroup {
+	type channelKey struct {
+		family  string
+		channel int
+	}
+
+	groups := make(map[channelKey]*channelGroup)
+	var keys []channelKey
+	var other []*scarlettctl.Control
+	for _, ctl := range controls {
+		parsed, ok := scarlettctl.ParseControlName(ctl.Name)
+		if !ok {
+			other = append(other, ctl)
+			continue
+		}
+
+		key := channelKey{family: parsed.Family, channel: parsed.ChannelNum}
+		group, exists := groups[key]
+		if !exists {
+			group = &channelGroup{name: fmt.Sprintf("%s %d", parsed.Family, parsed.ChannelNum)}
+			groups[key] = group
+			keys = append(keys, key)
+		}
+		group.controls = append(group.controls, ctl)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].family != keys[j].family {
+			return keys[i].family < keys[j].family
+		}
+		return keys[i].channel < keys[j].channel
+	})
+
+	result := make([]channelGroup, 0, len(keys)+1)
+	for _, key := range keys {
+		result = append(result, *groups[key])
+	}
+	if len(other) > 0 {
+		result = append(result, channelGroup{name: "other", controls: other})
+	}
+	return result
+}
+
+// formatDecibelRange formats a dB range, e.g. "-127.0 dB .. +12.0 dB, 1.0 dB steps"
+func formatDecibelRange(min, max, step float64) string {
+	steps := "variable steps"
+	if step > 0 {
+		steps = fmt.Sprintf("%.1f dB steps", step)
+	}
+	return fmt.Sprintf("%+.1f dB .. %+.1f dB, %s", min, max, steps)
+}
+
+// printTLV hex-dumps a control's raw TLV data followed by its decoded form
+func printTLV(ctl *scarlettctl.Control) error {
+	raw, err := ctl.ReadTLV()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("\ntlv (%d bytes):\n%s", len(raw), hex.Dump(raw))
+
+	blocks, err := scarlettctl.ParseTLV(raw)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("\ndecoded:\n%s", scarlettctl.DescribeTLV(blocks))
+	return nil
+}
+
 var getCmd = &cobra.Command{
 	Use:   "get <card> <control-name>",
 	Short: "Get the value of a control",
 	Args:  cobra.ExactArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		card, err := scarlettctl.FindCard(args[0])
+		card, err := findCard(cmd, args[0])
 		if err != nil {
 			return err
 		}
 		defer card.Close()
 
-		ctl, err := card.FindControl(args[1])
+		ctl, err := findControl(card, args[1])
 		if err != nil {
-			// Try prefix match
-			ctl, err = card.FindControlByPrefix(args[1])
+			return err
+		}
+
+		if structured(cmd) {
+			result, err := newValueResult(card, ctl)
 			if err != nil {
 				return err
 			}
+			return writeResult(cmd, result)
 		}
 
 		value, err := ctl.GetValueString()
@@ -101,35 +356,86 @@ var getCmd = &cobra.Command{
 	},
 }
 
-var setCmd = &cobra.Command{
-	Use:   "set <card> <control-name> <value>",
-	Short: "Set the value of a control",
-	Args:  cobra.ExactArgs(3),
+var findCmd = &cobra.Command{
+	Use:   "find <card> <regex>",
+	Short: "Find controls whose name matches a regular expression",
+	Long: `Find controls whose name matches a Go regular expression and print their
+current values, e.g.
+
+  scarlettctl find 0 '^Line In \d+ .*Switch$'
+  scarlettctl find 0 '(?i)mix a'`,
+	Args: cobra.ExactArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		card, err := scarlettctl.FindCard(args[0])
+		card, err := findCard(cmd, args[0])
 		if err != nil {
 			return err
 		}
 		defer card.Close()
 
-		ctl, err := card.FindControl(args[1])
+		controls, err := card.FindControlsByRegex(args[1])
 		if err != nil {
-			// Try prefix match
-			ctl, err = card.FindControlByPrefix(args[1])
+			return err
+		}
+
+		if structured(cmd) {
+			values, err := card.ReadAllValues()
 			if err != nil {
 				return err
 			}
+			result := make([]controlResult, 0, len(controls))
+			for _, ctl := range controls {
+				result = append(result, newControlResult(ctl, values))
+			}
+			return writeResult(cmd, result)
+		}
+
+		for _, ctl := range controls {
+			name := ctl.Name
+			if ctl.IsAmbiguous() {
+				name = ctl.FullID()
+			}
+
+			value, err := ctl.GetValueString()
+			if err != nil {
+				fmt.Printf("%s = error: %v\n", name, err)
+				continue
+			}
+			fmt.Printf("%s = %s\n", name, value)
+		}
+		return nil
+	},
+}
+
+var setCmd = &cobra.Command{
+	Use:   "set <card> <control-name> <value>",
+	Short: "Set the value of a control",
+	Args:  cardArgs(2),
+	RunE: forCards(func(cmd *cobra.Command, card *scarlettctl.Card, args []string) error {
+		ctl, err := findControl(card, args[0])
+		if err != nil {
+			return err
 		}
 
-		err = ctl.SetValueByString(args[2])
+		raw, err := ctl.ParseValue(args[1])
 		if err != nil {
 			return err
 		}
+		if err := ctl.SetValueVerified(raw, true); err != nil {
+			return err
+		}
+
+		if structured(cmd) {
+			result, err := newValueResult(card, ctl)
+			if err != nil {
+				return err
+			}
+			return writeResult(cmd, result)
+		}
 
 		value, _ := ctl.GetValueString()
 		fmt.Printf("%s = %s\n", ctl.Name, value)
 		return nil
-	},
+	}),
 }
 
 var routingCmd = &cobra.Command{
@@ -137,13 +443,55 @@ var routingCmd = &cobra.Command{
 	Short: "Show the current routing matrix",
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		card, err := scarlettctl.FindCard(args[0])
+		card, err := findCard(cmd, args[0])
 		if err != nil {
 			return err
 		}
 		defer card.Close()
 
-		return card.PrintRoutingMatrix()
+		paired, _ := cmd.Flags().GetBool("paired")
+
+		if structured(cmd) {
+			result, err := newRoutingResult(card)
+			if err != nil {
+				return err
+			}
+			if paired {
+				if result.Pairs, err = newPairResults(card); err != nil {
+					return err
+				}
+			}
+			return writeResult(cmd, result)
+		}
+
+		showSinks, _ := cmd.Flags().GetBool("sinks")
+		showSources, _ := cmd.Flags().GetBool("sources")
+
+		r := newRenderer(cmd)
+		if showSources {
+			if err := card.RenderRoutingSources(r); err != nil {
+				return err
+			}
+		}
+		if showSinks {
+			if showSources {
+				r.Printf("\n")
+			}
+			if err := card.RenderRoutingSinks(r); err != nil {
+				return err
+			}
+		}
+		if showSinks || showSources {
+			return nil
+		}
+
+		if grid, _ := cmd.Flags().GetBool("grid"); grid {
+			return card.RenderRoutingGrid(r)
+		}
+		if paired {
+			return card.RenderPairedRoutingMatrix(r)
+		}
+		return card.RenderRoutingMatrix(r)
 	},
 }
 
@@ -152,51 +500,112 @@ var routeCmd = &cobra.Command{
 	Short: "Set a routing connection",
 	Long: `Set a routing connection from a source to a sink.
 Both sink and source can be specified by name or pattern.
-Source can also be specified as a numeric ID.`,
-	Args: cobra.ExactArgs(3),
-	RunE: func(cmd *cobra.Command, args []string) error {
-		card, err := scarlettctl.FindCard(args[0])
-		if err != nil {
-			return err
+Source can also be specified as a numeric ID.
+
+With --who-uses <source>, list the sinks a source currently feeds instead; the
+source is again a name, pattern or numeric ID.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if whoUses, _ := cmd.Flags().GetString("who-uses"); whoUses != "" {
+			return cardArgs(0)(cmd, args)
+		}
+		return cardArgs(2)(cmd, args)
+	},
+	RunE: forCards(func(cmd *cobra.Command, card *scarlettctl.Card, args []string) error {
+		if whoUses, _ := cmd.Flags().GetString("who-uses"); whoUses != "" {
+			return printSinksForSource(cmd, card, whoUses)
 		}
-		defer card.Close()
 
-		sinkName := args[1]
-		sourceArg := args[2]
+		sinkName := args[0]
+		sourceArg := args[1]
 
 		// try to parse source as numeric ID first
 		if sourceID, err := strconv.Atoi(sourceArg); err == nil {
-			// find matching sink
-			sinks, err := card.GetRoutingSinks()
-			if err != nil {
+			if err := card.SetRoutingBySinkPattern(sinkName, sourceID); err != nil {
 				return err
 			}
 
-			for _, sink := range sinks {
-				if strings.Contains(strings.ToLower(sink.Name), strings.ToLower(sinkName)) {
-					err = sink.Control.SetValue(int64(sourceID))
-					if err != nil {
-						return err
-					}
+			sink, err := card.FindRoutingSink(sinkName)
+			if err != nil {
+				return err
+			}
 
-					value, _ := sink.Control.GetValueString()
-					fmt.Printf("%s -> %s\n", sink.Name, value)
-					return nil
-				}
+			if structured(cmd) {
+				return writeRouteResult(cmd, card, *sink)
 			}
 
-			return fmt.Errorf("sink matching '%s' not found", sinkName)
+			value, _ := sink.Control.GetValueString()
+			fmt.Printf("%s -> %s\n", sink.Name, value)
+			return nil
 		}
 
 		// otherwise treat as source name
-		err = card.SetRoutingByNames(sinkName, sourceArg)
+		err := card.SetRoutingByNames(sinkName, sourceArg)
 		if err != nil {
 			return err
 		}
 
+		if structured(cmd) {
+			sink, err := card.FindRoutingSink(sinkName)
+			if err != nil {
+				return err
+			}
+			return writeRouteResult(cmd, card, *sink)
+		}
+
 		fmt.Printf("routing updated: %s -> %s\n", sinkName, sourceArg)
 		return nil
-	},
+	}),
+}
+
+// printSinksForSource lists the sinks fed by a source, grouped by category
+func printSinksForSource(cmd *cobra.Command, card *scarlettctl.Card, source string) error {
+	sinks, err := card.FindSinksForSource(source)
+	if err != nil {
+		return err
+	}
+
+	if structured(cmd) {
+		result := make([]routeResult, 0, len(sinks))
+		for _, sink := range sinks {
+			value, err := sink.Control.GetValue()
+			if err != nil {
+				return err
+			}
+			result = append(result, routeResult{Card: card.Name, Sink: sink.Name, SourceID: value, Source: sink.Control.FormatValue(value)})
+		}
+		return writeResult(cmd, result)
+	}
+
+	if len(sinks) == 0 {
+		fmt.Printf("%s is not routed to any sink\n", source)
+		return nil
+	}
+
+	fmt.Printf("%s feeds %d sink(s):\n", source, len(sinks))
+	for _, group := range []struct {
+		category scarlettctl.PortCategory
+		title    string
+	}{
+		{scarlettctl.PortCategoryHW, "hardware outputs"},
+		{scarlettctl.PortCategoryPCM, "PCM capture"},
+		{scarlettctl.PortCategoryMix, "mixer inputs"},
+		{scarlettctl.PortCategoryDSP, "dsp inputs"},
+	} {
+		var names []string
+		for _, sink := range sinks {
+			if sink.Category == group.category {
+				names = append(names, sink.Name)
+			}
+		}
+		if len(names) == 0 {
+			continue
+		}
+		fmt.Printf("\n%s:\n", group.title)
+		for _, name := range names {
+			fmt.Printf("  %s\n", name)
+		}
+	}
+	return nil
 }
 
 var mixerCmd = &cobra.Command{
@@ -204,13 +613,21 @@ var mixerCmd = &cobra.Command{
 	Short: "Show the current mixer state",
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		card, err := scarlettctl.FindCard(args[0])
+		card, err := findCard(cmd, args[0])
 		if err != nil {
 			return err
 		}
 		defer card.Close()
 
-		return card.PrintMixerState()
+		if structured(cmd) {
+			result, err := newMixerResult(card)
+			if err != nil {
+				return err
+			}
+			return writeResult(cmd, result)
+		}
+
+		return card.RenderMixerState(newRenderer(cmd))
 	},
 }
 
@@ -219,37 +636,79 @@ var preampCmd = &cobra.Command{
 	Short: "Show the current preamp state",
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		card, err := scarlettctl.FindCard(args[0])
+		card, err := findCard(cmd, args[0])
 		if err != nil {
 			return err
 		}
 		defer card.Close()
 
-		return card.PrintPreampState()
+		if structured(cmd) {
+			states, err := card.GetPreampState()
+			if err != nil {
+				return err
+			}
+			return writeResult(cmd, states)
+		}
+
+		return card.RenderPreampState(newRenderer(cmd))
 	},
 }
 
 var watchCmd = &cobra.Command{
-	Use:   "watch <card>",
+	Use:   "watch <card> [card...]",
 	Short: "Monitor control changes in real-time",
-	Args:  cobra.ExactArgs(1),
+	Long: `Monitor control changes in real-time. Given several cards, each is watched
+at once and changes are prefixed with the card; a card that fails doesn't
+stop the others.`,
+	Args: cobra.MinimumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		card, err := scarlettctl.FindCard(args[0])
+		if len(args) > 1 {
+			return watchCards(cmd, args)
+		}
+
+		card, err := findCard(cmd, args[0])
 		if err != nil {
 			return err
 		}
 		defer card.Close()
 
-		fmt.Printf("monitoring controls for %s\n", card)
-
 		// set up signal handler for ctrl+c
 		sigChan := make(chan os.Signal, 1)
 		signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
 
+		if snapshot, _ := cmd.Flags().GetBool("snapshot"); snapshot {
+			return watchSnapshot(cmd, card, sigChan)
+		}
+
+		fmt.Printf("monitoring controls for %s\n", card)
+
 		errChan := make(chan error, 1)
 
+		monitor := card.NewEventMonitor()
+		if interval, _ := cmd.Flags().GetDuration("interval"); interval > 0 {
+			monitor.SetPollInterval(interval)
+		}
+		if timeout, _ := cmd.Flags().GetDuration("poll-timeout"); timeout > 0 {
+			monitor.SetPollTimeout(timeout)
+		}
+		filter, err := watchFilter(cmd)
+		if err != nil {
+			return err
+		}
+		monitor.SetFilter(filter)
+
+		if showStats, _ := cmd.Flags().GetBool("stats"); showStats {
+			return watchStats(cmd, monitor, sigChan)
+		}
+
+		minInterval, _ := cmd.Flags().GetDuration("min-interval")
+		since, _ := cmd.Flags().GetDuration("since")
+		if minInterval > 0 || since > 0 {
+			return watchThrottled(monitor, minInterval, since, sigChan)
+		}
+
 		go func() {
-			errChan <- card.WatchWithDisplay()
+			errChan <- monitor.WatchWithDisplay()
 		}()
 
 		select {
@@ -262,95 +721,1329 @@ var watchCmd = &cobra.Command{
 	},
 }
 
-var gainCmd = &cobra.Command{
-	Use:   "gain <card> <channel> <value>",
-	Short: "Set preamp gain for a channel",
-	Args:  cobra.ExactArgs(3),
-	RunE: func(cmd *cobra.Command, args []string) error {
-		card, err := scarlettctl.FindCard(args[0])
-		if err != nil {
-			return err
-		}
-		defer card.Close()
+// watchFilter builds a control filter from --match and --exclude, nil when
+// neither is given
+func watchFilter(cmd *cobra.Command) (func(*scarlettctl.Control) bool, error) {
+	match, _ := cmd.Flags().GetString("match")
+	exclude, _ := cmd.Flags().GetString("exclude")
+	if match == "" && exclude == "" {
+		return nil, nil
+	}
 
-		channel, err := strconv.Atoi(args[1])
-		if err != nil {
-			return fmt.Errorf("invalid channel number: %s", args[1])
+	var matchRe, excludeRe *regexp.Regexp
+	var err error
+	if match != "" {
+		if matchRe, err = regexp.Compile(match); err != nil {
+			return nil, fmt.Errorf("invalid --match pattern: %v", err)
 		}
-
-		value, err := strconv.ParseInt(args[2], 10, 64)
-		if err != nil {
-			return fmt.Errorf("invalid gain value: %s", args[2])
+	}
+	if exclude != "" {
+		if excludeRe, err = regexp.Compile(exclude); err != nil {
+			return nil, fmt.Errorf("invalid --exclude pattern: %v", err)
 		}
+	}
 
-		err = card.SetPreampGain(channel, value)
-		if err != nil {
-			return err
+	return func(ctl *scarlettctl.Control) bool {
+		if matchRe != nil && !matchRe.MatchString(ctl.Name) {
+			return false
 		}
+		return excludeRe == nil || !excludeRe.MatchString(ctl.Name)
+	}, nil
+}
 
-		fmt.Printf("set preamp gain for channel %d to %d\n", channel, value)
-		return nil
-	},
+// throttledChange is a change held back by --min-interval
+type throttledChange struct {
+	ctl   *scarlettctl.Control
+	value int64
 }
 
-var phantomCmd = &cobra.Command{
-	Use:   "phantom <card> <channel> <on|off>",
-	Short: "Set phantom power for a channel",
-	Args:  cobra.ExactArgs(3),
-	RunE: func(cmd *cobra.Command, args []string) error {
-		card, err := scarlettctl.FindCard(args[0])
-		if err != nil {
-			return err
-		}
-		defer card.Close()
+// watchThrottled prints control changes as WatchWithDisplay does, except that
+// changes in the first 'since' of the session are ignored, and each control is
+// printed at most once per minInterval: changes within the interval are held
+// and only the latest is printed when it ends
+func watchThrottled(monitor *scarlettctl.EventMonitor, minInterval, since time.Duration, sigChan <-chan os.Signal) error {
+	var mu sync.Mutex
+	lastValue := make(map[scarlettctl.ControlKey]int64)
+	printedValue := make(map[scarlettctl.ControlKey]int64)
+	printedAt := make(map[scarlettctl.ControlKey]time.Time)
+	pending := make(map[scarlettctl.ControlKey]throttledChange)
+	settled := time.Now().Add(since)
 
-		channel, err := strconv.Atoi(args[1])
-		if err != nil {
-			return fmt.Errorf("invalid channel number: %s", args[1])
+	printChange := func(ctl *scarlettctl.Control, value int64, now time.Time) {
+		name := ctl.Name
+		if ctl.Count > 1 {
+			name = fmt.Sprintf("%s[%d]", ctl.Name, ctl.Index)
 		}
+		fmt.Printf("[%s] %-50s = %s\n", now.Format("15:04:05"), name, ctl.FormatValue(value))
+		printedValue[ctl.Key()] = value
+		printedAt[ctl.Key()] = now
+	}
 
-		enabled := false
-		switch strings.ToLower(args[2]) {
-		case "on", "true", "1", "yes":
-			enabled = true
-		case "off", "false", "0", "no":
-			enabled = false
-		default:
-			return fmt.Errorf("invalid value: %s (use on/off)", args[2])
-		}
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- monitor.WatchControls(func(ctl *scarlettctl.Control, value int64) error {
+			mu.Lock()
+			defer mu.Unlock()
 
-		err = card.SetPreampPhantom(channel, enabled)
-		if err != nil {
-			return err
-		}
+			key := ctl.Key()
+			if last, ok := lastValue[key]; ok && last == value {
+				return nil
+			}
+			lastValue[key] = value
 
-		state := "off"
-		if enabled {
-			state = "on"
-		}
-		fmt.Printf("set phantom power for channel %d to '%s'\n", channel, state)
-		return nil
-	},
-}
+			// values seen while settling become the baseline without being printed
+			now := time.Now()
+			if now.Before(settled) {
+				return nil
+			}
 
-func init() {
-	rootCmd.AddCommand(listCmd)
-	rootCmd.AddCommand(controlsCmd)
+			if at, ok := printedAt[key]; ok && now.Sub(at) < minInterval {
+				if value == printedValue[key] {
+					delete(pending, key)
+				} else {
+					pending[key] = throttledChange{ctl: ctl, value: value}
+				}
+				return nil
+			}
+			delete(pending, key)
+			printChange(ctl, value, now)
+			return nil
+		})
+	}()
+
+	// flush held changes whose interval has ended
+	var tick <-chan time.Time
+	if minInterval > 0 {
+		ticker := time.NewTicker(minInterval / 2)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-tick:
+			mu.Lock()
+			now := time.Now()
+			var due []scarlettctl.ControlKey
+			for key := range pending {
+				if now.Sub(printedAt[key]) >= minInterval {
+					due = append(due, key)
+				}
+			}
+			sort.Slice(due, func(i, j int) bool {
+				if due[i].NumID != due[j].NumID {
+					return due[i].NumID < due[j].NumID
+				}
+				return due[i].Index < due[j].Index
+			})
+			for _, key := range due {
+				printChange(pending[key].ctl, pending[key].value, now)
+				delete(pending, key)
+			}
+			mu.Unlock()
+		case <-sigChan:
+			monitor.Stop()
+			fmt.Println("\nstopping monitor...")
+			return nil
+		case err := <-errChan:
+			return err
+		}
+	}
+}
+
+// watchStats counts control changes instead of printing each one, and prints
+// the most frequently changed controls every --every until interrupted
+func watchStats(cmd *cobra.Command, monitor *scarlettctl.EventMonitor, sigChan <-chan os.Signal) error {
+	every, _ := cmd.Flags().GetDuration("every")
+	top, _ := cmd.Flags().GetInt("top")
+	if every <= 0 {
+		return fmt.Errorf("--every must be positive")
+	}
+
+	stats := scarlettctl.NewChangeStats()
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- monitor.WatchControls(stats.Callback())
+	}()
+
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			printChangeStats(stats, top)
+		case <-sigChan:
+			monitor.Stop()
+			fmt.Println()
+			printChangeStats(stats, top)
+			return nil
+		case err := <-errChan:
+			return err
+		}
+	}
+}
+
+// printChangeStats prints the most frequently changed controls
+func printChangeStats(stats *scarlettctl.ChangeStats, top int) {
+	total, elapsed := stats.Total()
+	fmt.Printf("[%s] %d changes in %v\n", time.Now().Format("15:04:05"), total, elapsed.Round(time.Second))
+
+	for _, count := range stats.Top(top) {
+		name := count.Control.Name
+		if count.Control.Count > 1 || count.Control.IsAmbiguous() {
+			name = count.Control.FullID()
+		}
+		perMinute := float64(count.Changes) / elapsed.Minutes()
+		fmt.Printf("  %6d  %7.1f/min  %s\n", count.Changes, perMinute, name)
+	}
+}
+
+var gainCmd = &cobra.Command{
+	Use:   "gain <card> <channels> <value>",
+	Short: "Set preamp gain for one or more channels",
+	Long: `Set preamp gain. Channels are a number, a range or a list, e.g. 1, 1-4
+or 1,3,5; each channel is set in turn and failures don't stop the rest.
+
+With --normalize, reconcile linked channel pairs whose gains have drifted
+apart instead: both channels are set to their average, or with --first to the
+first channel's gain.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if normalize, _ := cmd.Flags().GetBool("normalize"); normalize {
+			return cardArgs(0)(cmd, args)
+		}
+		return cardArgs(2)(cmd, args)
+	},
+	RunE: forCards(func(cmd *cobra.Command, card *scarlettctl.Card, args []string) error {
+		if normalize, _ := cmd.Flags().GetBool("normalize"); normalize {
+			return normalizeLinkedGain(cmd, card)
+		}
+
+		channels, err := parseChannelSpec(args[0])
+		if err != nil {
+			return err
+		}
+
+		value, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid gain value: %s", args[1])
+		}
+
+		// in dry-run mode the write is reported as "dry run: ..." instead
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		return forChannels(channels, func(channel int) error {
+			if err := card.SetPreampGain(channel, value); err != nil {
+				if errors.Is(err, scarlettctl.ErrAboveGainCap) {
+					return fmt.Errorf("%v; use --force to override", err)
+				}
+				return err
+			}
+			if !dryRun {
+				fmt.Printf("set preamp gain for channel %d to %d\n", channel, value)
+			}
+			return nil
+		})
+	}),
+}
+
+// normalizeLinkedGain evens out the gains of linked channel pairs
+func normalizeLinkedGain(cmd *cobra.Command, card *scarlettctl.Card) error {
+	mode := scarlettctl.NormalizeAverage
+	if first, _ := cmd.Flags().GetBool("first"); first {
+		mode = scarlettctl.NormalizeFirst
+	}
+
+	changed, err := card.NormalizeLinkedGain(mode)
+	if len(changed) > 0 {
+		fmt.Printf("normalized linked gain for channels %s\n", joinInts(changed))
+	} else if err == nil {
+		fmt.Println("linked channel gains already match")
+	}
+	if errors.Is(err, scarlettctl.ErrAboveGainCap) {
+		return fmt.Errorf("%v; use --force to override", err)
+	}
+	return err
+}
+
+var phantomCmd = &cobra.Command{
+	Use:   "phantom <card> <channels|all> <on|off>",
+	Short: "Set phantom power for one or more channels",
+	Long: `Set phantom power. Channels are a number, a range or a list, e.g. 1, 1-4
+or 1,3,5, or 'all' for every channel with phantom power; with a range or list,
+each channel is set in turn and failures don't stop the rest.`,
+	Args: cobra.ExactArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		card, err := findCard(cmd, args[0])
+		if err != nil {
+			return err
+		}
+		defer card.Close()
+
+		enabled, err := parseOnOff(args[2])
+		if err != nil {
+			return err
+		}
+
+		state := "off"
+		if enabled {
+			state = "on"
+		}
+
+		if strings.EqualFold(args[1], "all") {
+			changed, err := card.SetAllPhantom(enabled)
+			if len(changed) > 0 {
+				fmt.Printf("set phantom power to '%s' for channels %s\n", state, joinInts(changed))
+			} else if err == nil {
+				fmt.Printf("phantom power already '%s' on all channels\n", state)
+			}
+			return err
+		}
+
+		channels, err := parseChannelSpec(args[1])
+		if err != nil {
+			return err
+		}
+
+		// one confirmation for every channel rather than a prompt per channel
+		if enabled && len(channels) > 1 && card.PhantomPowerWarning != nil {
+			if err := card.PhantomPowerWarning(channels); err != nil {
+				return err
+			}
+			card.PhantomPowerWarning = nil
+		}
+
+		return forChannels(channels, func(channel int) error {
+			if err := card.SetPreampPhantom(channel, enabled); err != nil {
+				return err
+			}
+			fmt.Printf("set phantom power for channel %d to '%s'\n", channel, state)
+			return nil
+		})
+	},
+}
+
+// preampSwitchCommand builds a command that switches a preamp control on some
+// channels or on all channels that have it
+func preampSwitchCommand(use, short, label string,
+	pick func(scarlettctl.PreampChannel) *scarlettctl.Control,
+	set func(*scarlettctl.Card, int, bool) error,
+	setAll func(*scarlettctl.Card, bool) ([]int, error)) *cobra.Command {
+	return &cobra.Command{
+		Use:   use + " <card> <channels|all> <on|off>",
+		Short: short,
+		Args:  cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			card, err := findCard(cmd, args[0])
+			if err != nil {
+				return err
+			}
+			defer card.Close()
+
+			enabled, err := parseOnOff(args[2])
+			if err != nil {
+				return err
+			}
+
+			state := "off"
+			if enabled {
+				state = "on"
+			}
+
+			if strings.EqualFold(args[1], "all") {
+				channels, err := card.GetPreampChannels()
+				if err != nil {
+					return err
+				}
+				skipped := 0
+				for _, ch := range channels {
+					if pick(ch) == nil {
+						skipped++
+					}
+				}
+
+				changed, err := setAll(card, enabled)
+				if len(changed) > 0 {
+					fmt.Printf("set %s to '%s' for %d channel(s): %s\n", label, state, len(changed), joinInts(changed))
+				} else if err == nil {
+					fmt.Printf("%s already '%s' on all channels\n", label, state)
+				}
+				if skipped > 0 {
+					fmt.Printf("skipped %d channel(s) without %s\n", skipped, label)
+				}
+				return err
+			}
+
+			channels, err := parseChannelSpec(args[1])
+			if err != nil {
+				return err
+			}
+
+			return forChannels(channels, func(channel int) error {
+				if err := set(card, channel, enabled); err != nil {
+					return err
+				}
+				fmt.Printf("set %s for channel %d to '%s'\n", label, channel, state)
+				return nil
+			})
+		},
+	}
+}
+
+var airCmd = preampSwitchCommand("air", "Set air mode for a channel or all channels", "air",
+	func(ch scarlettctl.PreampChannel) *scarlettctl.Control { return ch.Air },
+	(*scarlettctl.Card).SetPreampAir, (*scarlettctl.Card).SetAllAir)
+
+var padCmd = preampSwitchCommand("pad", "Set pad for a channel or all channels", "pad",
+	func(ch scarlettctl.PreampChannel) *scarlettctl.Control { return ch.Pad },
+	(*scarlettctl.Card).SetPreampPad, (*scarlettctl.Card).SetAllPad)
+
+var sceneCmd = &cobra.Command{
+	Use:   "scene <card> [name]",
+	Short: "Apply or save a named routing/mixer scene",
+	Long: `Apply a named scene, or save the current state as a scene with --save.
+A scene only lists the controls that matter for it (routing and mixer levels
+by default), so applying it leaves all other state untouched. Re-saving an
+existing scene captures the same controls again.`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, _ := cmd.Flags().GetString("file")
+		if path == "" {
+			var err error
+			if path, err = scarlettctl.DefaultScenesPath(); err != nil {
+				return err
+			}
+		}
+
+		saveName, _ := cmd.Flags().GetString("save")
+		if saveName == "" && len(args) < 2 {
+			return fmt.Errorf("scene name required (or use --save <name>)")
+		}
+
+		card, err := findCard(cmd, args[0])
+		if err != nil {
+			return err
+		}
+		defer card.Close()
+
+		scenes, err := scarlettctl.LoadScenes(path)
+		if err != nil {
+			return err
+		}
+
+		if saveName != "" {
+			scene, err := card.CaptureScene(scenes[saveName])
+			if err != nil {
+				return err
+			}
+			scenes[saveName] = scene
+			if err := scenes.Save(path); err != nil {
+				return err
+			}
+
+			fmt.Printf("saved scene '%s' (%d controls) to %s\n", saveName, len(scene.Controls), path)
+			return nil
+		}
+
+		scene, ok := scenes[args[1]]
+		if !ok {
+			return fmt.Errorf("scene '%s' not found in %s", args[1], path)
+		}
+		if err := card.RestoreSnapshot(scene); err != nil {
+			return err
+		}
+
+		fmt.Printf("applied scene '%s' (%d controls)\n", args[1], len(scene.Controls))
+		return nil
+	},
+}
+
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot <card> <file>",
+	Short: "Save the writable controls, or a group of them, to a file",
+	Long: `Save the current values of the card's writable controls to a JSON file.
+--group limits the snapshot to one or more groups (preamp, routing, mixer,
+other), e.g. just the preamps:
+
+  scarlettctl snapshot 0 preamp.json --group preamp
+
+Restore it with 'restore'.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		filter, err := groupFilter(cmd)
+		if err != nil {
+			return err
+		}
+
+		card, err := findCard(cmd, args[0])
+		if err != nil {
+			return err
+		}
+		defer card.Close()
+
+		f, err := os.Create(args[1])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		if err := card.SaveSubset(f, filter); err != nil {
+			return err
+		}
+
+		fmt.Printf("saved snapshot to %s\n", args[1])
+		return f.Close()
+	},
+}
+
+var restoreCmd = &cobra.Command{
+	Use:   "restore <card> <file>",
+	Short: "Restore controls from a snapshot file",
+	Long: `Restore the controls saved by 'snapshot'. --group restores only the
+controls in the given groups, so part of a full snapshot can be applied:
+
+  scarlettctl restore 0 full.json --group routing
+
+By default every entry is attempted and failures are reported together. With
+--atomic nothing is written unless every entry matches a control, and a
+failed write rolls back the controls already restored.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		filter, err := groupFilter(cmd)
+		if err != nil {
+			return err
+		}
+
+		card, err := findCard(cmd, args[0])
+		if err != nil {
+			return err
+		}
+		defer card.Close()
+
+		f, err := os.Open(args[1])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		snapshot, err := card.ReadSubset(f, filter)
+		if err != nil {
+			return err
+		}
+
+		if atomic, _ := cmd.Flags().GetBool("atomic"); atomic {
+			err = card.RestoreSnapshotAtomic(snapshot)
+		} else {
+			err = card.RestoreSnapshot(snapshot)
+		}
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("restored snapshot from %s\n", args[1])
+		return nil
+	},
+}
+
+// groupFilter builds a control filter from the --group flag, nil for all controls
+func groupFilter(cmd *cobra.Command) (func(*scarlettctl.Control) bool, error) {
+	names, _ := cmd.Flags().GetStringSlice("group")
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	groups := make([]scarlettctl.ControlGroup, 0, len(names))
+	for _, name := range names {
+		group, err := scarlettctl.ParseControlGroup(name)
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, group)
+	}
+	return scarlettctl.InGroups(groups...), nil
+}
+
+var recordCmd = &cobra.Command{
+	Use:   "record <card> <file>",
+	Short: "Record control changes to an automation file",
+	Long: `Record control changes (gain moves, mixer levels, routing) with their timing
+until ctrl+c is pressed. Play the recording back with 'replay'.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		card, err := findCard(cmd, args[0])
+		if err != nil {
+			return err
+		}
+		defer card.Close()
+
+		f, err := os.Create(args[1])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		recorder := scarlettctl.NewEventRecorder(card.NewEventMonitor(), f)
+
+		sigChan := make(chan os.Signal, 1)
+		signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+		go func() {
+			<-sigChan
+			recorder.Stop()
+		}()
+
+		fmt.Printf("recording control changes for %s to %s (press ctrl+c to stop)\n", card, args[1])
+		if err := recorder.Record(); err != nil {
+			return err
+		}
+
+		fmt.Println("\nrecording stopped")
+		return nil
+	},
+}
+
+var replayCmd = &cobra.Command{
+	Use:     "replay <card> <file>",
+	Aliases: []string{"play"},
+	Short:   "Re-apply recorded control changes with their original timing",
+	Args:    cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		card, err := findCard(cmd, args[0])
+		if err != nil {
+			return err
+		}
+		defer card.Close()
+
+		f, err := os.Open(args[1])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		speed, _ := cmd.Flags().GetFloat64("speed")
+
+		fmt.Printf("playing %s on %s\n", args[1], card)
+		if err := card.Replay(f, speed); err != nil {
+			return err
+		}
+
+		fmt.Println("playback complete")
+		return nil
+	},
+}
+
+var autogainCmd = &cobra.Command{
+	Use:   "autogain <card> <channel>",
+	Short: "Run automatic gain adjustment on a channel",
+	Long: `Start autogain on a preamp channel and wait for it to finish, reporting the
+gain it reached. Make some noise into the input while it runs.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		card, err := findCard(cmd, args[0])
+		if err != nil {
+			return err
+		}
+		defer card.Close()
+
+		channel, err := strconv.Atoi(args[1])
+		if err != nil {
+			return fmt.Errorf("invalid channel number: %s", args[1])
+		}
+
+		if err := card.StartAutogain(channel); err != nil {
+			return err
+		}
+
+		if noWait, _ := cmd.Flags().GetBool("no-wait"); noWait {
+			fmt.Printf("started autogain on channel %d\n", channel)
+			return nil
+		}
+
+		fmt.Printf("running autogain on channel %d...\n", channel)
+		waitTimeout, _ := cmd.Flags().GetDuration("wait")
+		gain, err := card.WaitAutogain(channel, waitTimeout)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("autogain complete for channel %d: gain %d\n", channel, gain)
+		return nil
+	},
+}
+
+var mixSetCmd = &cobra.Command{
+	Use:   "mix-set <card> <mix> <level>",
+	Short: "Set every input of a mix to one level",
+	Long: `Set every input of a mix (e.g. "A" or "Mix A") to the same level.
+The level may be a raw value, a percentage of the range (e.g. 50%), a dB level
+(e.g. -6dB), or "unity" for 0 dB.`,
+	Args: cobra.ExactArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		card, err := findCard(cmd, args[0])
+		if err != nil {
+			return err
+		}
+		defer card.Close()
+
+		// resolve the level against the range of the mix's first input
+		mixName := scarlettctl.NormalizeMixName(args[1])
+		first, err := card.GetMixerInput(mixName, 1)
+		if err != nil {
+			return err
+		}
+
+		level, err := scarlettctl.ParseMixerLevel(first, args[2])
+		if err != nil {
+			return err
+		}
+
+		count, err := card.SetMixLevel(mixName, level)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("set %d inputs of %s to %d\n", count, mixName, level)
+		return nil
+	},
+}
+
+// findCard resolves and opens a card, applying the global flags to it
+func findCard(cmd *cobra.Command, identifier string) (*scarlettctl.Card, error) {
+	card, err := openCardTimeout(cmd, identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	locked, err := cardLocked(card)
+	if err != nil {
+		card.Close()
+		return nil, err
+	}
+	card.SetLocked(locked)
+	recordUndo(cmd, card)
+
+	if verify, _ := cmd.Flags().GetBool("verify"); verify {
+		card.SetVerify(true)
+	}
+
+	// --force on other commands, e.g. phantom, doesn't lift the caps
+	if force, _ := cmd.Flags().GetBool("force"); cmd.Name() != "gain" || !force {
+		for channel, max := range gainCaps {
+			card.SetGainCap(channel, max)
+		}
+	}
+
+	// commands run from a shell ask before switching phantom power on, however
+	// the change comes about; servers have nobody to ask
+	if phantomConfirmCommands[cmd.Name()] {
+		if force, _ := cmd.Flags().GetBool("force"); cmd.Name() != "phantom" || !force {
+			card.PhantomPowerWarning = confirmPhantom
+		}
+	}
+
+	if discovery, _ := cmd.Flags().GetBool("discovery-cache"); discovery {
+		if dir, err := scarlettctl.DefaultDiscoveryCacheDir(); err == nil {
+			card.EnableDiscoveryCache(dir)
+		}
+	}
+
+	if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+		card.SetDryRun(func(ctl *scarlettctl.Control, oldValue, newValue int64) {
+			fmt.Printf("dry run: %s: %s -> %s\n", ctl.Name, ctl.FormatValue(oldValue), ctl.FormatValue(newValue))
+		})
+	}
+
+	return card, nil
+}
+
+// cardArgs accepts a card followed by n arguments, or just the n arguments
+// when --all-cards is given
+func cardArgs(n int) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		if allCards, _ := cmd.Flags().GetBool("all-cards"); allCards {
+			return cobra.ExactArgs(n)(cmd, args)
+		}
+		return cobra.ExactArgs(n+1)(cmd, args)
+	}
+}
+
+// forCards runs a command body against the card named by the first argument,
+// or against every detected card when --all-cards is given. With --all-cards a
+// failing card doesn't stop the others; each card's outcome is reported, on
+// stderr when writing structured output.
+func forCards(run func(cmd *cobra.Command, card *scarlettctl.Card, args []string) error) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		if allCards, _ := cmd.Flags().GetBool("all-cards"); !allCards {
+			card, err := findCard(cmd, args[0])
+			if err != nil {
+				return err
+			}
+			defer card.Close()
+			return run(cmd, card, args[1:])
+		}
+
+		detected, err := scarlettctl.ListCards()
+		if err != nil {
+			return err
+		}
+
+		var cards []*scarlettctl.Card
+		var failed []string
+		for _, info := range detected {
+			card, err := findCard(cmd, strconv.Itoa(info.Number))
+			if err != nil {
+				failed = append(failed, fmt.Sprintf("%s: %v", info, err))
+				continue
+			}
+			defer card.Close()
+			cards = append(cards, card)
+		}
+
+		report := os.Stdout
+		if structured(cmd) {
+			report = os.Stderr
+		}
+
+		errs := scarlettctl.ApplyToAll(cards, func(card *scarlettctl.Card) error {
+			if !structured(cmd) {
+				fmt.Printf("%s:\n", card)
+			}
+			return run(cmd, card, args)
+		})
+
+		fmt.Fprintln(report)
+		for i, card := range cards {
+			if errs[i] != nil {
+				failed = append(failed, fmt.Sprintf("%s: %v", card, errs[i]))
+				continue
+			}
+			fmt.Fprintf(report, "ok      %s\n", card)
+		}
+		for _, failure := range failed {
+			fmt.Fprintf(report, "failed  %s\n", failure)
+		}
+
+		if len(failed) > 0 {
+			return fmt.Errorf("%d of %d cards failed", len(failed), len(detected))
+		}
+		return nil
+	}
+}
+
+// eventCommands are the commands that watch for control events; every other
+// command opens its card without subscribing to them
+var eventCommands = map[string]bool{"watch": true, "mqtt": true, "record": true, "serve": true}
+
+// openCardTimeout opens a card, applying the global --timeout to discovery and
+// to every subsequent hardware call on the card
+func openCardTimeout(cmd *cobra.Command, identifier string) (*scarlettctl.Card, error) {
+	find := scarlettctl.FindCardWithoutEvents
+	if eventCommands[cmd.Name()] {
+		find = scarlettctl.FindCard
+	}
+
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	if timeout <= 0 {
+		return find(identifier)
+	}
+
+	type result struct {
+		card *scarlettctl.Card
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		card, err := find(identifier)
+		done <- result{card, err}
+	}()
+
+	select {
+	case res := <-done:
+		if res.err != nil {
+			return nil, res.err
+		}
+		res.card.SetTimeout(timeout)
+		return res.card, nil
+	case <-time.After(timeout):
+		// close the card if discovery finishes after all
+		go func() {
+			if res := <-done; res.card != nil {
+				res.card.Close()
+			}
+		}()
+		return nil, fmt.Errorf("opening card '%s' timed out after %v", identifier, timeout)
+	}
+}
+
+// findControl resolves a control by alias, exact name or full ID, falling back
+// to a prefix match unless the exact name is ambiguous
+func findControl(card *scarlettctl.Card, name string) (*scarlettctl.Control, error) {
+	if target, ok := controlAliases[strings.ToLower(name)]; ok {
+		name = target
+	}
+
+	ctl, err := card.FindControl(name)
+	if err == nil || errors.Is(err, scarlettctl.ErrAmbiguous) || errors.Is(err, scarlettctl.ErrNotSupported) {
+		return ctl, err
+	}
+
+	// try prefix match
+	return card.FindControlByPrefix(name)
+}
+
+// phantomConfirmCommands names the commands that confirm phantom power changes
+// with confirmPhantom
+var phantomConfirmCommands = map[string]bool{
+	"set":     true,
+	"phantom": true,
+	"preset":  true,
+	"scene":   true,
+	"restore": true,
+	"replay":  true,
+	"undo":    true,
+}
+
+// confirmPhantom asks before energizing channels with 48V phantom power
+// Without a terminal on stdin (e.g. in scripts) it doesn't prompt.
+func confirmPhantom(channels []int) error {
+	if !isTerminal(os.Stdin) {
+		return nil
+	}
+
+	fmt.Printf("enable 48V phantom power on channel(s) %s? make sure connected mics can take it [y/N] ", joinInts(channels))
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return nil
+	}
+	return fmt.Errorf("phantom power not enabled")
+}
+
+// isTerminal reports whether f is a terminal
+func isTerminal(f *os.File) bool {
+	_, err := unix.IoctlGetTermios(int(f.Fd()), unix.TCGETS)
+	return err == nil
+}
+
+// joinInts formats a list of numbers as "1, 2, 3"
+func joinInts(values []int) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = strconv.Itoa(v)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// newRenderer builds a stdout renderer honoring the --width and --no-color flags
+func newRenderer(cmd *cobra.Command) *scarlettctl.Renderer {
+	r := scarlettctl.NewRenderer(os.Stdout)
+	if width, _ := cmd.Flags().GetInt("width"); width > 0 {
+		r.Width = width
+	}
+	if noColor, _ := cmd.Flags().GetBool("no-color"); noColor {
+		r.Color = false
+	}
+	return r
+}
+
+var monitorCmd = &cobra.Command{
+	Use:     "monitor <card> [mode]",
+	Aliases: []string{"directmonitor"},
+	Short:   "Get or set the direct monitor mode",
+	Long: `Get or set the direct monitor mode (e.g. off, mono, stereo).
+The available modes depend on the device; models with a plain direct
+monitor switch take off or on.`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		card, err := findCard(cmd, args[0])
+		if err != nil {
+			return err
+		}
+		defer card.Close()
+
+		if len(args) == 2 {
+			if err := card.SetDirectMonitor(args[1]); err != nil {
+				return err
+			}
+		}
+
+		mode, err := card.GetDirectMonitor()
+		if err != nil {
+			return err
+		}
+
+		if structured(cmd) {
+			return writeResult(cmd, settingResult{Card: card.Name, Setting: "direct_monitor", Value: mode})
+		}
+		fmt.Printf("direct monitor = %s\n", mode)
+		return nil
+	},
+}
+
+// buttonCommand builds a command that shows or switches a monitor button
+func buttonCommand(use, short, label string, get func(*scarlettctl.Card) (bool, error), set func(*scarlettctl.Card, bool) error) *cobra.Command {
+	return &cobra.Command{
+		Use:   use + " <card> [on|off]",
+		Short: short,
+		Args:  cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			card, err := findCard(cmd, args[0])
+			if err != nil {
+				return err
+			}
+			defer card.Close()
+
+			if len(args) == 2 {
+				enabled, err := parseOnOff(args[1])
+				if err != nil {
+					return err
+				}
+				if err := set(card, enabled); err != nil {
+					return err
+				}
+			}
+
+			enabled, err := get(card)
+			if err != nil {
+				return err
+			}
+
+			state := "off"
+			if enabled {
+				state = "on"
+			}
+			fmt.Printf("%s = %s\n", label, state)
+			return nil
+		},
+	}
+}
+
+var talkbackCmd = buttonCommand("talkback", "Get or set talkback", "talkback",
+	(*scarlettctl.Card).GetTalkback, (*scarlettctl.Card).SetTalkback)
+
+var dimCmd = buttonCommand("dim", "Get or set the monitor dim", "dim",
+	(*scarlettctl.Card).GetDim, (*scarlettctl.Card).SetDim)
+
+var muteMasterCmd = buttonCommand("mute-master", "Get or set the monitor mute (not per-channel mute)", "master mute",
+	(*scarlettctl.Card).GetMasterMute, (*scarlettctl.Card).SetMasterMute)
+
+// maxChannel bounds channel specs well above any interface's channel count, so
+// a mistyped range can't expand into millions of channels
+const maxChannel = 64
+
+// parseChannelSpec parses a channel number, range or list, e.g. "2", "1-4" or
+// "1,3,5-6", into channel numbers in the order given, without duplicates
+func parseChannelSpec(spec string) ([]int, error) {
+	var channels []int
+	seen := make(map[int]bool)
+	add := func(channel int) {
+		if !seen[channel] {
+			seen[channel] = true
+			channels = append(channels, channel)
+		}
+	}
+
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		first, last, isRange := strings.Cut(part, "-")
+
+		start, err := strconv.Atoi(strings.TrimSpace(first))
+		if err != nil || start < 1 {
+			return nil, fmt.Errorf("invalid channel '%s' in '%s' (use e.g. 2, 1-4 or 1,3,5)", part, spec)
+		}
+		end := start
+		if isRange {
+			end, err = strconv.Atoi(strings.TrimSpace(last))
+			if err != nil || end < start {
+				return nil, fmt.Errorf("invalid channel range '%s' in '%s'", part, spec)
+			}
+		}
+		if end > maxChannel {
+			return nil, fmt.Errorf("channel %d in '%s' is out of range (1-%d)", end, spec, maxChannel)
+		}
+
+		for channel := start; channel <= end; channel++ {
+			add(channel)
+		}
+	}
+
+	return channels, nil
+}
+
+// forChannels runs fn for each channel, continuing past failures, which are
+// reported as they happen and summarized in the returned error. A single
+// channel's error is returned as is.
+func forChannels(channels []int, fn func(channel int) error) error {
+	if len(channels) == 1 {
+		return fn(channels[0])
+	}
+
+	var failed []int
+	for _, channel := range channels {
+		if err := fn(channel); err != nil {
+			fmt.Fprintf(os.Stderr, "channel %d: %v\n", channel, err)
+			failed = append(failed, channel)
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("failed on channel(s) %s", joinInts(failed))
+	}
+	return nil
+}
+
+// parseOnOff parses an on/off argument
+func parseOnOff(s string) (bool, error) {
+	switch strings.ToLower(s) {
+	case "on", "true", "1", "yes":
+		return true, nil
+	case "off", "false", "0", "no":
+		return false, nil
+	default:
+		return false, fmt.Errorf("invalid value: %s (use on/off)", s)
+	}
+}
+
+var exportScriptCmd = &cobra.Command{
+	Use:   "export-script <card>",
+	Short: "Print a shell script of commands that recreate the current settings",
+	Long: `Print a shell script of scarlettctl commands (gain, phantom, set) that
+recreate the card's current writable settings, grouped by category. Run the
+script with a card argument to apply the settings to another interface:
+
+  scarlettctl export-script 0 > studio.sh
+  sh studio.sh 1`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		card, err := findCard(cmd, args[0])
+		if err != nil {
+			return err
+		}
+		defer card.Close()
+
+		return card.ExportScript(os.Stdout)
+	},
+}
+
+var loopbackCmd = &cobra.Command{
+	Use:   "loopback <card> <mix>",
+	Short: "Route a mix to the loopback capture channels",
+	Long: `Route a mix to the loopback PCM capture channels, so the computer can
+record it (e.g. desktop audio plus mic for streaming). Mixes are treated as
+stereo pairs: "A" routes Mix A and Mix B to the left and right loopback channels.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		card, err := findCard(cmd, args[0])
+		if err != nil {
+			return err
+		}
+		defer card.Close()
+
+		if err := card.SetupLoopback(args[1]); err != nil {
+			return err
+		}
+
+		sinks, err := card.GetLoopbackSinks()
+		if err != nil {
+			return err
+		}
+		for _, sink := range sinks {
+			value, _ := sink.Control.GetValueString()
+			fmt.Printf("%s <- %s\n", sink.Name, value)
+		}
+		return nil
+	},
+}
+
+var pcmCmd = &cobra.Command{
+	Use:   "pcm <card>",
+	Short: "Show which hardware ports the computer's PCM channels are routed to",
+	Long: `Show the routing behind each PCM channel, numbered as the DAW, PipeWire
+or JACK sees them, e.g. "DAW capture 1 <- Analogue 1" for what the computer
+records on its first input channel.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		card, err := findCard(cmd, args[0])
+		if err != nil {
+			return err
+		}
+		defer card.Close()
+
+		mapping, err := card.GetPCMMapping()
+		if err != nil {
+			return err
+		}
+
+		if structured(cmd) {
+			return writeResult(cmd, mapping)
+		}
+
+		for _, ch := range mapping {
+			routes := "(off)"
+			if len(ch.Routes) > 0 {
+				routes = strings.Join(ch.Routes, ", ")
+			}
+			if ch.Direction == scarlettctl.PCMCapture {
+				fmt.Printf("DAW capture %d <- %s\n", ch.Channel, routes)
+			} else {
+				fmt.Printf("DAW playback %d -> %s\n", ch.Channel, routes)
+			}
+		}
+		return nil
+	},
+}
+
+var spdifCmd = &cobra.Command{
+	Use:   "spdif <card>",
+	Short: "Show the channel status of the S/PDIF stream",
+	Long: `Decode the IEC958 channel status bits of the card's S/PDIF stream:
+consumer or professional format, audio or data, sample rate, emphasis and
+copy protection. The capture side, describing the digital input, is preferred.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		card, err := findCard(cmd, args[0])
+		if err != nil {
+			return err
+		}
+		defer card.Close()
+
+		status, err := card.GetSPDIFStatus()
+		if err != nil {
+			return err
+		}
+
+		if structured(cmd) {
+			return writeResult(cmd, status)
+		}
+
+		format := "consumer (S/PDIF)"
+		if status.Professional {
+			format = "professional (AES3)"
+		}
+		content := "audio"
+		if !status.Audio {
+			content = "non-audio data"
+		}
+		rate := "not indicated"
+		if status.SampleRate > 0 {
+			rate = fmt.Sprintf("%d Hz", status.SampleRate)
+		}
+
+		fmt.Printf("Format:       %s\n", format)
+		fmt.Printf("Content:      %s\n", content)
+		fmt.Printf("Sample rate:  %s\n", rate)
+		fmt.Printf("Emphasis:     %s\n", status.Emphasis)
+		if !status.Professional {
+			copying := "protected"
+			if status.CopyPermitted {
+				copying = "permitted"
+			}
+			fmt.Printf("Copying:      %s\n", copying)
+			fmt.Printf("Category:     0x%02x\n", status.Category)
+		}
+		fmt.Printf("Status bytes: % x\n", status.Raw)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.PersistentFlags().Duration("timeout", 0, "Abort hardware calls that take longer than this (e.g. 5s); a timed-out write may still take effect")
+	rootCmd.PersistentFlags().Bool("quiet", false, "Only log errors")
+	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "Log debug detail, including each ALSA call and resolved control IDs")
+	rootCmd.PersistentFlags().Bool("dry-run", false, "Show what writes would do without changing the device")
+	rootCmd.PersistentFlags().StringP("output", "o", outputText, "Output format: text, json or yaml (also --format)")
+	rootCmd.SetGlobalNormalizationFunc(formatAlias)
+	rootCmd.PersistentFlags().Bool("no-color", false, "Disable colored output (also honors NO_COLOR)")
+	rootCmd.PersistentFlags().Int("width", 0, "Output width in columns (default: terminal width)")
+	rootCmd.PersistentFlags().Bool("discovery-cache", false, "Cache control metadata in ~/.cache/scarlettctl to skip enumerating the card on later runs")
+
+	rootCmd.AddCommand(listCmd)
+	rootCmd.AddCommand(controlsCmd)
 	rootCmd.AddCommand(getCmd)
+	rootCmd.AddCommand(findCmd)
 	rootCmd.AddCommand(setCmd)
 	rootCmd.AddCommand(routingCmd)
 	rootCmd.AddCommand(routeCmd)
+	rootCmd.AddCommand(loopbackCmd)
+	rootCmd.AddCommand(pcmCmd)
+	rootCmd.AddCommand(exportScriptCmd)
+	rootCmd.AddCommand(spdifCmd)
 	rootCmd.AddCommand(mixerCmd)
 	rootCmd.AddCommand(preampCmd)
 	rootCmd.AddCommand(watchCmd)
 	rootCmd.AddCommand(gainCmd)
 	rootCmd.AddCommand(phantomCmd)
+	rootCmd.AddCommand(airCmd)
+	rootCmd.AddCommand(padCmd)
+	rootCmd.AddCommand(monitorCmd)
+	rootCmd.AddCommand(talkbackCmd)
+	rootCmd.AddCommand(dimCmd)
+	rootCmd.AddCommand(muteMasterCmd)
+	rootCmd.AddCommand(sceneCmd)
+	rootCmd.AddCommand(snapshotCmd)
+	rootCmd.AddCommand(restoreCmd)
+	rootCmd.AddCommand(recordCmd)
+	rootCmd.AddCommand(replayCmd)
+	rootCmd.AddCommand(autogainCmd)
+	rootCmd.AddCommand(mixSetCmd)
 
 	controlsCmd.Flags().BoolP("verbose", "v", false, "Show control values")
+	watchCmd.Flags().Duration("interval", scarlettctl.DefaultPollInterval, "Re-read interval used when the device can't deliver change events (with --snapshot, the refresh interval, default 1s)")
+	watchCmd.Flags().Duration("poll-timeout", scarlettctl.DefaultPollTimeout, "How long each wait for events lasts; shorter stops sooner on ctrl+c at the cost of more wakeups")
+	watchCmd.Flags().String("match", "", "Only watch controls whose name matches this regular expression")
+	watchCmd.Flags().String("exclude", "", "Don't watch controls whose name matches this regular expression (e.g. Meter)")
+	watchCmd.Flags().Duration("min-interval", 0, "Print each control at most once per interval, showing only the latest of changes within it")
+	watchCmd.Flags().Duration("since", 0, "Ignore changes for this long after starting, to let the device settle (e.g. 5s)")
+	watchCmd.Flags().Bool("snapshot", false, "Show a full-screen view of --group, redrawn every --interval, instead of each change")
+	watchCmd.Flags().String("group", "mixer", "Group shown by --snapshot: mixer, preamp or routing")
+	watchCmd.Flags().Bool("stats", false, "Count changes per control and print a periodic summary instead of each change")
+	watchCmd.Flags().Duration("every", 10*time.Second, "How often to print the --stats summary")
+	watchCmd.Flags().Int("top", 10, "Number of controls in the --stats summary (0 for all)")
+	replayCmd.Flags().Float64("speed", 1, "Playback speed multiplier (e.g. 2 for double speed)")
+	for _, cmd := range []*cobra.Command{setCmd, routeCmd, gainCmd} {
+		cmd.Flags().Bool("all-cards", false, "Run against every detected card instead of a single <card>")
+	}
+	for _, cmd := range []*cobra.Command{setCmd, routeCmd, gainCmd, phantomCmd, mixSetCmd} {
+		cmd.Flags().Bool("verify", false, "Read each write back and fail if the device didn't apply it")
+	}
+	controlsCmd.Flags().Bool("by-channel", false, "Group the listing by channel, e.g. every Line In 1 control together")
+	controlsCmd.Flags().String("has-item", "", "List only enumerated controls offering this item, e.g. \"ADAT 1\"")
+	controlsCmd.Flags().BoolP("ignore-case", "i", false, "Match --has-item case-insensitively")
+	controlsCmd.Flags().Bool("tlv", false, "Dump and decode the raw TLV data of the named control")
+	controlsCmd.Flags().Bool("show-errors", false, "Report controls the driver failed to describe, which are otherwise skipped")
+	controlsCmd.Flags().Bool("json", false, "Output controls as JSON (same as --output json)")
+	controlsCmd.Flags().Bool("collapse", false, "With structured output, emit one entry per element with a values array instead of one per index")
+	routingCmd.Flags().Bool("grid", false, "Show the matrix as a grid of sinks by sources, with an X where connected")
+	routingCmd.Flags().Bool("sinks", false, "List only the routing sinks")
+	routingCmd.Flags().Bool("sources", false, "List only the routing sources (with ids)")
+	routingCmd.Flags().Bool("paired", false, "Collapse stereo sink pairs routed to a stereo source into one line")
+	routeCmd.Flags().String("who-uses", "", "List the sinks fed by this source instead of routing")
+	preampCmd.Flags().Bool("json", false, "Output preamp state as JSON (same as --output json)")
+	pcmCmd.Flags().Bool("json", false, "Output the mapping as JSON (same as --output json)")
+	spdifCmd.Flags().Bool("json", false, "Output the decoded status as JSON (same as --output json)")
+	gainCmd.Flags().BoolP("force", "f", false, "Set the gain even above the channel's configured gain cap")
+	gainCmd.Flags().Bool("normalize", false, "Set both channels of each linked pair to one gain")
+	gainCmd.Flags().Bool("first", false, "With --normalize, use the first channel's gain instead of the average")
+	phantomCmd.Flags().BoolP("force", "f", false, "Enable phantom power without confirmation")
+	autogainCmd.Flags().Bool("no-wait", false, "Start autogain without waiting for it to finish")
+	autogainCmd.Flags().Duration("wait", 30*time.Second, "How long to wait for autogain to finish")
+	sceneCmd.Flags().String("save", "", "Save the current state as the named scene")
+	sceneCmd.Flags().String("file", "", "Scenes file (default ~/.config/scarlettctl/scenes.json)")
+	snapshotCmd.Flags().StringSlice("group", nil, "Only save these groups: preamp, routing, mixer, other")
+	restoreCmd.Flags().StringSlice("group", nil, "Only restore these groups: preamp, routing, mixer, other")
+	restoreCmd.Flags().Bool("atomic", false, "Restore all entries or none, rolling back on a failed write")
 }
 
 func main() {
-	if err := rootCmd.Execute(); err != nil {
+	if err := setupConfig(); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+
+	err := rootCmd.Execute()
+	// writes made before a failure are recorded too, so they can be undone
+	if undoErr := saveUndo(); undoErr != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to record writes for undo: %v\n", undoErr)
+	}
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "error: %v\n", err)
 		os.Exit(1)
 	}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/michaelquigley/scarlettctl/metrics"
+	"github.com/spf13/cobra"
+)
+
+var metricsCmd = &cobra.Command{
+	Use:   "metrics <card>",
+	Short: "Serve control values as Prometheus metrics",
+	Long: `Serve card control values on a Prometheus /metrics endpoint.
+
+Integer, boolean and enumerated controls (level meters included) are exported
+as scarlett_control_value{card,name,numid,index}; enumerated controls also get
+scarlett_control_state with the current item as the state label. Values are
+re-read every --interval rather than on each scrape. The endpoint listens on
+127.0.0.1 unless --addr says otherwise, e.g. --addr :9100 for every interface.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		card, err := findCard(cmd, args[0])
+		if err != nil {
+			return err
+		}
+		defer card.Close()
+
+		addr, _ := cmd.Flags().GetString("addr")
+		interval, _ := cmd.Flags().GetDuration("interval")
+		fmt.Printf("serving metrics for %s on %s/metrics\n", card, addr)
+		return metrics.Serve(card, addr, interval)
+	},
+}
+
+func init() {
+	metricsCmd.Flags().String("addr", "127.0.0.1:9100", "Address to serve /metrics on")
+	metricsCmd.Flags().Duration("interval", metrics.DefaultInterval, "How often to re-read control values")
+
+	rootCmd.AddCommand(metricsCmd)
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/michaelquigley/scarlettctl"
+	"github.com/spf13/cobra"
+)
+
+var mqttCmd = &cobra.Command{
+	Use:   "mqtt <card>",
+	Short: "Bridge controls to an MQTT broker",
+	Long: `Bridge card controls to an MQTT broker for home-automation integration.
+
+Each control's value is published (retained) to <topic>/<control>/state on
+connect and whenever it changes. Values published to <topic>/<control>/set are
+written to the control, using the same value syntax as 'set'.
+
+Control names are made topic-safe by replacing spaces with '_' and '/' with
+'-'; elements of multi-value controls get an '_<index>' suffix.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		card, err := findCard(cmd, args[0])
+		if err != nil {
+			return err
+		}
+		defer card.Close()
+
+		broker, _ := cmd.Flags().GetString("broker")
+		topic, _ := cmd.Flags().GetString("topic")
+		clientID, _ := cmd.Flags().GetString("client-id")
+		topic = strings.TrimSuffix(topic, "/")
+
+		controls, err := card.GetControls()
+		if err != nil {
+			return err
+		}
+
+		byTopic := make(map[string]*scarlettctl.Control, len(controls))
+		for _, ctl := range controls {
+			byTopic[mqttControlName(ctl)] = ctl
+		}
+
+		publish := func(client mqtt.Client, ctl *scarlettctl.Control) {
+			value, err := ctl.GetValueString()
+			if err != nil {
+				return
+			}
+			client.Publish(fmt.Sprintf("%s/%s/state", topic, mqttControlName(ctl)), 0, true, value)
+		}
+
+		onSet := func(client mqtt.Client, msg mqtt.Message) {
+			name := strings.TrimSuffix(strings.TrimPrefix(msg.Topic(), topic+"/"), "/set")
+			ctl, ok := byTopic[name]
+			if !ok {
+				slog.Warn("mqtt: unknown control", "name", name)
+				return
+			}
+			if err := ctl.SetValueByString(strings.TrimSpace(string(msg.Payload()))); err != nil {
+				slog.Warn("mqtt: failed to set control", "name", ctl.Name, "error", err)
+			}
+			publish(client, ctl)
+		}
+
+		opts := mqtt.NewClientOptions().
+			AddBroker(broker).
+			SetClientID(clientID).
+			SetAutoReconnect(true).
+			SetOnConnectHandler(func(client mqtt.Client) {
+				// publish retained state so subscribers get current values immediately
+				for _, ctl := range controls {
+					publish(client, ctl)
+				}
+				client.Subscribe(topic+"/+/set", 0, onSet)
+			})
+
+		client := mqtt.NewClient(opts)
+		token := client.Connect()
+		if !token.WaitTimeout(10 * time.Second) {
+			return fmt.Errorf("timed out connecting to %s", broker)
+		}
+		if err := token.Error(); err != nil {
+			return fmt.Errorf("failed to connect to %s: %v", broker, err)
+		}
+		defer client.Disconnect(250)
+
+		monitor := card.NewEventMonitor()
+
+		sigChan := make(chan os.Signal, 1)
+		signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+		go func() {
+			<-sigChan
+			monitor.Stop()
+		}()
+
+		fmt.Printf("bridging %s to %s under '%s' (press ctrl+c to stop)\n", card, broker, topic)
+
+		last := make(map[scarlettctl.ControlKey]int64)
+		return monitor.WatchControls(func(ctl *scarlettctl.Control, value int64) error {
+			if strings.Contains(ctl.Name, "Meter") {
+				return nil // meters change constantly
+			}
+			key := ctl.Key()
+			if lastValue, exists := last[key]; exists && lastValue == value {
+				return nil
+			}
+			last[key] = value
+			publish(client, ctl)
+			return nil
+		})
+	},
+}
+
+// mqttControlName returns a topic-safe name for a control
+func mqttControlName(ctl *scarlettctl.Control) string {
+	name := ctl.Name
+	if ctl.IsAmbiguous() {
+		name = fmt.Sprintf("%s %d.%d %s", ctl.Interface, ctl.Device, ctl.Subdevice, ctl.Name)
+	}
+	name = strings.NewReplacer(" ", "_", "/", "-", "+", "_", "#", "_").Replace(name)
+	if ctl.Count > 1 {
+		name = fmt.Sprintf("%s_%d", name, ctl.Index)
+	}
+	return name
+}
+
+func init() {
+	mqttCmd.Flags().String("broker", "tcp://localhost:1883", "MQTT broker URL")
+	mqttCmd.Flags().String("topic", "scarlett", "Base topic")
+	mqttCmd.Flags().String("client-id", "scarlettctl", "MQTT client ID")
+
+	rootCmd.AddCommand(mqttCmd)
+}
@@ -0,0 +1,490 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"time"
+
+	"github.com/michaelquigley/scarlettctl"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+// output formats selected by the global --output flag
+const (
+	outputText = "text"
+	outputJSON = "json"
+	outputYAML = "yaml"
+)
+
+// cardResult describes a card in structured output
+type cardResult struct {
+	Number int    `json:"number"`
+	Name   string `json:"name"`
+	Device string `json:"device"`
+}
+
+// controlResult describes a control, and its value when read, in structured output
+type controlResult struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	NumID       uint     `json:"numid"`
+	Index       int      `json:"index"`
+	Type        string   `json:"type"`
+	Writable    bool     `json:"writable"`
+	Min         int64    `json:"min"`
+	Max         int64    `json:"max"`
+	Step        int64    `json:"step,omitempty"`
+	Unit        string   `json:"unit,omitempty"`
+	Items       []string `json:"items,omitempty"`
+	Value       *int64   `json:"value,omitempty"`
+	ValueString string   `json:"value_string,omitempty"`
+}
+
+// elementResult describes a control element with all of its values, for
+// multi-value controls such as level meters and stereo pairs
+type elementResult struct {
+	ID           string   `json:"id"`
+	Name         string   `json:"name"`
+	NumID        uint     `json:"numid"`
+	Count        int      `json:"count"`
+	Type         string   `json:"type"`
+	Writable     bool     `json:"writable"`
+	Min          int64    `json:"min"`
+	Max          int64    `json:"max"`
+	Step         int64    `json:"step,omitempty"`
+	Unit         string   `json:"unit,omitempty"`
+	Items        []string `json:"items,omitempty"`
+	Values       []int64  `json:"values,omitempty"`
+	ValueStrings []string `json:"value_strings,omitempty"`
+}
+
+// valueResult is a control value read or written by get and set
+type valueResult struct {
+	Card        string `json:"card"`
+	Control     string `json:"control"`
+	Value       int64  `json:"value"`
+	ValueString string `json:"value_string"`
+	Unit        string `json:"unit,omitempty"`
+}
+
+// routeResult is the source feeding a routing sink
+type routeResult struct {
+	Card     string `json:"card,omitempty"`
+	Sink     string `json:"sink"`
+	SourceID int64  `json:"source_id"`
+	Source   string `json:"source"`
+}
+
+// sourceResult describes a routing source
+type sourceResult struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	Category string `json:"category"`
+	Hardware string `json:"hardware,omitempty"`
+}
+
+// sinkResult describes a routing sink
+type sinkResult struct {
+	Index    int    `json:"index"`
+	Name     string `json:"name"`
+	Category string `json:"category"`
+	Port     int    `json:"port"`
+}
+
+// pairResult is a stereo pair of routing sinks, with the stereo source
+// feeding it when both channels route one
+type pairResult struct {
+	Sink   string        `json:"sink"`
+	Source string        `json:"source,omitempty"`
+	Routes []routeResult `json:"routes"`
+}
+
+// routingResult is the routing matrix
+type routingResult struct {
+	Sources []sourceResult `json:"sources"`
+	Routes  []routeResult  `json:"routes"`
+	Pairs   []pairResult   `json:"pairs,omitempty"` // with --paired
+}
+
+// mixerResult is the level of one mixer input
+type mixerResult struct {
+	Mix    string `json:"mix"`
+	Input  int    `json:"input"`
+	Source string `json:"source,omitempty"` // source routed to the input
+	Value  int64  `json:"value"`
+	Min    int64  `json:"min"`
+	Max    int64  `json:"max"`
+}
+
+// signalResult is the signal presence on one input
+type signalResult struct {
+	Input   int      `json:"input"`
+	Source  string   `json:"source"`
+	State   string   `json:"state"`
+	Level   int64    `json:"level"`
+	Max     int64    `json:"max"`
+	LevelDB *float64 `json:"level_db"` // null for silence
+}
+
+// settingResult is a single named device setting, e.g. the direct monitor mode
+type settingResult struct {
+	Card    string `json:"card"`
+	Setting string `json:"setting"`
+	Value   string `json:"value"`
+}
+
+// inputResult is the input mode of a preamp channel
+type inputResult struct {
+	Card    string `json:"card"`
+	Channel int    `json:"channel"`
+	Mode    string `json:"mode"`
+}
+
+// undoResult is a recorded write that undo can revert
+type undoResult struct {
+	Time           time.Time `json:"time"`
+	Control        string    `json:"control"`
+	Found          bool      `json:"found"` // false when the control no longer exists
+	Previous       int64     `json:"previous"`
+	PreviousString string    `json:"previous_string,omitempty"`
+	Value          int64     `json:"value"`
+	ValueString    string    `json:"value_string,omitempty"`
+}
+
+// versionResult is the scarlettctl, driver and firmware versions
+type versionResult struct {
+	Version  string `json:"version"`
+	Driver   string `json:"driver,omitempty"`
+	Card     string `json:"card,omitempty"`
+	Firmware string `json:"firmware,omitempty"`
+}
+
+// digitalResult is the S/PDIF and ADAT status of a card
+type digitalResult struct {
+	Mode         string   `json:"mode,omitempty"`
+	Modes        []string `json:"modes,omitempty"`
+	SPDIFInputs  int      `json:"spdif_inputs"`
+	SPDIFOutputs int      `json:"spdif_outputs"`
+	ADATInputs   int      `json:"adat_inputs"`
+	ADATOutputs  int      `json:"adat_outputs"`
+	ADATLive     int      `json:"adat_live"` // ADAT inputs live at the current sample rate
+}
+
+// validateOutput checks the --output flag
+func validateOutput(cmd *cobra.Command) error {
+	switch format, _ := cmd.Flags().GetString("output"); format {
+	case outputText, outputJSON, outputYAML:
+		return nil
+	default:
+		return fmt.Errorf("invalid output format '%s' (use text, json or yaml)", format)
+	}
+}
+
+// formatAlias makes --format another name for --output
+func formatAlias(f *pflag.FlagSet, name string) pflag.NormalizedName {
+	if name == "format" {
+		name = "output"
+	}
+	return pflag.NormalizedName(name)
+}
+
+// outputFormat returns the selected output format. A command's own --json flag
+// is shorthand for --output json.
+func outputFormat(cmd *cobra.Command) string {
+	if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+		return outputJSON
+	}
+	format, _ := cmd.Flags().GetString("output")
+	return format
+}
+
+// structured reports whether the command should write a result object
+// rather than text
+func structured(cmd *cobra.Command) bool {
+	return outputFormat(cmd) != outputText
+}
+
+// yamlDocuments counts the YAML documents written, to separate them
+var yamlDocuments int
+
+// writeResult writes a result object to stdout in the selected format
+func writeResult(cmd *cobra.Command, result any) error {
+	switch outputFormat(cmd) {
+	case outputYAML:
+		// go through JSON so YAML keys match the json tags used everywhere
+		data, err := json.Marshal(result)
+		if err != nil {
+			return err
+		}
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		var generic any
+		if err := dec.Decode(&generic); err != nil {
+			return err
+		}
+
+		if yamlDocuments > 0 {
+			fmt.Println("---")
+		}
+		yamlDocuments++
+
+		enc := yaml.NewEncoder(os.Stdout)
+		enc.SetIndent(2)
+		if err := enc.Encode(yamlValue(generic)); err != nil {
+			return err
+		}
+		return enc.Close()
+
+	default:
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(result)
+	}
+}
+
+// yamlValue converts decoded JSON numbers to integers where possible, so YAML
+// shows 1000000 rather than 1e+06
+func yamlValue(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, value := range v {
+			v[key] = yamlValue(value)
+		}
+	case []any:
+		for i, value := range v {
+			v[i] = yamlValue(value)
+		}
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	}
+	return v
+}
+
+// newControlResult describes a control, with a value if one was read
+func newControlResult(ctl *scarlettctl.Control, values map[scarlettctl.ControlKey]int64) controlResult {
+	result := controlResult{
+		ID:       ctl.FullID(),
+		Name:     ctl.Name,
+		NumID:    ctl.NumID,
+		Index:    ctl.Index,
+		Type:     ctl.Type.String(),
+		Writable: ctl.Writable,
+		Min:      ctl.Min,
+		Max:      ctl.Max,
+		Step:     ctl.Step,
+		Unit:     ctl.Unit(),
+		Items:    ctl.Items,
+	}
+	if value, ok := values[ctl.Key()]; ok {
+		result.Value = &value
+		result.ValueString = ctl.FormatValue(value)
+	}
+	return result
+}
+
+// newElementResult describes an element from its per-index controls, with
+// values when every index was read
+func newElementResult(element []*scarlettctl.Control, values map[scarlettctl.ControlKey]int64) elementResult {
+	ctl := element[0]
+	result := elementResult{
+		ID:       ctl.ElementID(),
+		Name:     ctl.Name,
+		NumID:    ctl.NumID,
+		Count:    ctl.Count,
+		Type:     ctl.Type.String(),
+		Writable: ctl.Writable,
+		Min:      ctl.Min,
+		Max:      ctl.Max,
+		Step:     ctl.Step,
+		Unit:     ctl.Unit(),
+		Items:    ctl.Items,
+	}
+	for _, c := range element {
+		value, ok := values[c.Key()]
+		if !ok {
+			result.Values, result.ValueStrings = nil, nil
+			break
+		}
+		result.Values = append(result.Values, value)
+		result.ValueStrings = append(result.ValueStrings, c.FormatValue(value))
+	}
+	return result
+}
+
+// newValueResult reads a control's value for get and set
+func newValueResult(card *scarlettctl.Card, ctl *scarlettctl.Control) (valueResult, error) {
+	value, err := ctl.GetValue()
+	if err != nil {
+		return valueResult{}, err
+	}
+	valueString, err := ctl.GetValueString()
+	if err != nil {
+		return valueResult{}, err
+	}
+
+	name := ctl.Name
+	if ctl.IsAmbiguous() {
+		name = ctl.FullID()
+	}
+	return valueResult{Card: card.Name, Control: name, Value: value, ValueString: valueString, Unit: ctl.Unit()}, nil
+}
+
+// newRoutingResult reads the routing matrix
+func newRoutingResult(card *scarlettctl.Card) (*routingResult, error) {
+	sources, err := card.GetRoutingSources()
+	if err != nil {
+		return nil, err
+	}
+	sinks, err := card.GetRoutingSinks()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &routingResult{
+		Sources: newSourceResults(sources),
+		Routes:  make([]routeResult, 0, len(sinks)),
+	}
+	for _, sink := range sinks {
+		value, err := sink.Control.GetValue()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read routing for %s: %v", sink.Name, err)
+		}
+		result.Routes = append(result.Routes, routeResult{
+			Sink:     sink.Name,
+			SourceID: value,
+			Source:   sink.Control.FormatValue(value),
+		})
+	}
+
+	return result, nil
+}
+
+// newSourceResults describes routing sources
+func newSourceResults(sources []scarlettctl.RoutingSource) []sourceResult {
+	result := make([]sourceResult, 0, len(sources))
+	for _, src := range sources {
+		result = append(result, sourceResult{
+			ID:       src.ID,
+			Name:     src.Name,
+			Category: src.Category.String(),
+			Hardware: src.HardwareType,
+		})
+	}
+	return result
+}
+
+// newSinkResults describes routing sinks
+func newSinkResults(sinks []scarlettctl.RoutingSink) []sinkResult {
+	result := make([]sinkResult, 0, len(sinks))
+	for _, sink := range sinks {
+		result = append(result, sinkResult{
+			Index:    sink.Index,
+			Name:     sink.Name,
+			Category: sink.Category.String(),
+			Port:     sink.PortNum,
+		})
+	}
+	return result
+}
+
+// newPairResults reads the routing matrix grouped into stereo pairs
+func newPairResults(card *scarlettctl.Card) ([]pairResult, error) {
+	pairs, err := card.GetRoutingPairs()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]pairResult, 0, len(pairs))
+	for _, pair := range pairs {
+		p := pairResult{
+			Sink:   pair.Name,
+			Source: pair.SourceName,
+			Routes: []routeResult{{
+				Sink:     pair.Left.Name,
+				SourceID: pair.LeftSource,
+				Source:   pair.Left.Control.FormatValue(pair.LeftSource),
+			}},
+		}
+		if pair.Right != nil {
+			p.Routes = append(p.Routes, routeResult{
+				Sink:     pair.Right.Name,
+				SourceID: pair.RightSource,
+				Source:   pair.Right.Control.FormatValue(pair.RightSource),
+			})
+		}
+		result = append(result, p)
+	}
+	return result, nil
+}
+
+// writeRouteResult reads the source now feeding a sink and writes it
+func writeRouteResult(cmd *cobra.Command, card *scarlettctl.Card, sink scarlettctl.RoutingSink) error {
+	value, err := sink.Control.GetValue()
+	if err != nil {
+		return err
+	}
+	return writeResult(cmd, routeResult{
+		Card:     card.Name,
+		Sink:     sink.Name,
+		SourceID: value,
+		Source:   sink.Control.FormatValue(value),
+	})
+}
+
+// newMixerResult reads every mixer input level
+func newMixerResult(card *scarlettctl.Card) ([]mixerResult, error) {
+	inputs, err := card.GetMixerInputs()
+	if err != nil {
+		return nil, err
+	}
+
+	labels, err := card.GetMixerInputLabels()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]mixerResult, 0, len(inputs))
+	for _, input := range inputs {
+		value, err := input.Control.GetValue()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, mixerResult{
+			Mix:    input.MixName,
+			Input:  input.InputNum,
+			Source: labels[input.InputNum],
+			Value:  value,
+			Min:    input.Control.Min,
+			Max:    input.Control.Max,
+		})
+	}
+	return result, nil
+}
+
+// newSignalResults converts input signal statuses
+func newSignalResults(statuses []scarlettctl.SignalStatus) []signalResult {
+	result := make([]signalResult, 0, len(statuses))
+	for _, status := range statuses {
+		r := signalResult{
+			Input:  status.Channel,
+			Source: status.Name,
+			State:  status.State.String(),
+			Level:  status.Level,
+			Max:    status.Max,
+		}
+		if db := status.LevelDB(); !math.IsInf(db, -1) {
+			r.LevelDB = &db
+		}
+		result = append(result, r)
+	}
+	return result
+}
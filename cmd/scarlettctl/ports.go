@@ -0,0 +1,61 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var sourcesCmd = &cobra.Command{
+	Use:   "sources <card>",
+	Short: "List the routing sources with their ids",
+	Long: `List the routing sources with the ids and names accepted by 'route',
+and their categories. The same as 'routing <card> --sources'.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		card, err := findCard(cmd, args[0])
+		if err != nil {
+			return err
+		}
+		defer card.Close()
+
+		if structured(cmd) {
+			sources, err := card.GetRoutingSources()
+			if err != nil {
+				return err
+			}
+			return writeResult(cmd, newSourceResults(sources))
+		}
+		return card.RenderRoutingSources(newRenderer(cmd))
+	},
+}
+
+var sinksCmd = &cobra.Command{
+	Use:   "sinks <card>",
+	Short: "List the routing sinks with their indices",
+	Long: `List the routing sinks with their indices and categories. The same as
+'routing <card> --sinks'.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		card, err := findCard(cmd, args[0])
+		if err != nil {
+			return err
+		}
+		defer card.Close()
+
+		if structured(cmd) {
+			sinks, err := card.GetRoutingSinks()
+			if err != nil {
+				return err
+			}
+			return writeResult(cmd, newSinkResults(sinks))
+		}
+		return card.RenderRoutingSinks(newRenderer(cmd))
+	},
+}
+
+func init() {
+	sourcesCmd.Flags().Bool("json", false, "Output the sources as JSON (same as --output json)")
+	sinksCmd.Flags().Bool("json", false, "Output the sinks as JSON (same as --output json)")
+
+	rootCmd.AddCommand(sourcesCmd)
+	rootCmd.AddCommand(sinksCmd)
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/michaelquigley/scarlettctl"
+	"github.com/spf13/cobra"
+)
+
+var presetCmd = &cobra.Command{
+	Use:   "preset <card> <name>",
+	Short: "Apply a built-in preset for a common setup",
+	Long: `Apply a built-in preset, such as a solo podcast or guitar and vocal setup.
+
+Presets set input levels, air and pad, route inputs to the DAW and pick the
+direct monitor mode. They adapt to the connected interface: steps for controls
+the interface doesn't have are skipped and listed. List the presets with
+'scarlettctl preset --list'.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if list, _ := cmd.Flags().GetBool("list"); list {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.ExactArgs(2)(cmd, args)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if list, _ := cmd.Flags().GetBool("list"); list {
+			presets, err := scarlettctl.Presets()
+			if err != nil {
+				return err
+			}
+			for _, preset := range presets {
+				fmt.Printf("%-20s %s\n", preset.Name, preset.Description)
+			}
+			return nil
+		}
+
+		preset, err := scarlettctl.GetPreset(args[1])
+		if err != nil {
+			return err
+		}
+
+		card, err := findCard(cmd, args[0])
+		if err != nil {
+			return err
+		}
+		defer card.Close()
+
+		result, err := card.ApplyPreset(preset)
+		if result != nil {
+			for _, step := range result.Applied {
+				fmt.Printf("  %s\n", step)
+			}
+			for _, step := range result.Skipped {
+				fmt.Printf("  %s (skipped: not on this interface)\n", step)
+			}
+		}
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("applied preset '%s' to %s (%d steps, %d skipped)\n",
+			preset.Name, card, len(result.Applied), len(result.Skipped))
+		return nil
+	},
+}
+
+func init() {
+	presetCmd.Flags().Bool("list", false, "List the built-in presets")
+
+	rootCmd.AddCommand(presetCmd)
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/michaelquigley/scarlettctl"
+	"github.com/spf13/cobra"
+)
+
+var quietCmd = &cobra.Command{
+	Use:   "quiet <card>",
+	Short: "Mute every hardware output, e.g. at the end of a session",
+	Long: `Park the hardware outputs so nothing can blast through the monitors:
+each line output is muted, or turned to its minimum volume where it has no mute
+switch, and outputs without either are routed to Off.
+
+With --save, the values replaced are written to a snapshot file in the temp
+directory, so 'scarlettctl restore <card> <file>' puts them back. The file is
+written even if muting fails part way.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		card, err := findCard(cmd, args[0])
+		if err != nil {
+			return err
+		}
+		defer card.Close()
+
+		result, err := card.SafeShutdown()
+		if result != nil {
+			for _, name := range result.Muted {
+				fmt.Printf("muted %s\n", name)
+			}
+
+			// save even when a write failed, so what was muted can be restored
+			if save, _ := cmd.Flags().GetBool("save"); save && len(result.Muted) > 0 {
+				path, saveErr := saveQuietSnapshot(result.Previous)
+				if saveErr != nil {
+					if err != nil {
+						return fmt.Errorf("%v; failed to save previous state: %v", err, saveErr)
+					}
+					return saveErr
+				}
+				fmt.Printf("saved previous state to %s; restore with 'scarlettctl restore %s %s'\n", path, args[0], path)
+			}
+		}
+		if err != nil {
+			return err
+		}
+		if len(result.Muted) == 0 {
+			fmt.Println("hardware outputs already silent")
+		}
+		return nil
+	},
+}
+
+// saveQuietSnapshot writes the values quiet replaced to a new temp file
+func saveQuietSnapshot(snapshot *scarlettctl.Snapshot) (string, error) {
+	f, err := os.CreateTemp("", "scarlettctl-quiet-*.json")
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	if err := scarlettctl.WriteSnapshot(f, snapshot); err != nil {
+		return "", err
+	}
+	return f.Name(), f.Close()
+}
+
+func init() {
+	quietCmd.Flags().Bool("save", false, "Write the values replaced to a snapshot file in the temp directory")
+	rootCmd.AddCommand(quietCmd)
+}
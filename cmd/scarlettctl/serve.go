@@ -0,0 +1,38 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/michaelquigley/scarlettctl/grpc"
+	"github.com/spf13/cobra"
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve <card>",
+	Short: "Serve card controls over gRPC",
+	Long: `Serve card controls over gRPC for clients in any language.
+
+The service (see grpc/scarlettctl.proto) lists, reads and writes controls,
+reads and changes routing, and streams control changes. Server reflection is
+enabled, so tools like grpcurl can be used without the proto file:
+
+  grpcurl -plaintext localhost:50051 list`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		card, err := findCard(cmd, args[0])
+		if err != nil {
+			return err
+		}
+		defer card.Close()
+
+		listen, _ := cmd.Flags().GetString("listen")
+		fmt.Printf("serving %s on %s\n", card, listen)
+		return grpc.Serve(card, listen)
+	},
+}
+
+func init() {
+	serveCmd.Flags().String("listen", "localhost:50051", "Address to listen on")
+
+	rootCmd.AddCommand(serveCmd)
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/spf13/cobra"
+)
+
+var signalCmd = &cobra.Command{
+	Use:   "signal <card>",
+	Short: "Show whether each input has signal, is silent or is clipping",
+	Long: `Read the level meters and show, for each analogue input, whether a
+signal is present, the input is silent (below -60 dBFS) or it is clipping
+(the meter at full scale). Useful to confirm mics are plugged in and gain is
+set sensibly.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		card, err := findCard(cmd, args[0])
+		if err != nil {
+			return err
+		}
+		defer card.Close()
+
+		statuses, err := card.InputSignalStatus()
+		if err != nil {
+			return err
+		}
+
+		if structured(cmd) {
+			return writeResult(cmd, newSignalResults(statuses))
+		}
+
+		for _, status := range statuses {
+			level := "-inf"
+			if db := status.LevelDB(); !math.IsInf(db, -1) {
+				level = fmt.Sprintf("%.1f", db)
+			}
+			fmt.Printf("  input %-2d  %-8s  %6s dBFS\n", status.Channel, status.State, level)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(signalCmd)
+}
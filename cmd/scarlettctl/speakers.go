@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var speakersCmd = &cobra.Command{
+	Use:   "speakers <card> [main|alt]",
+	Short: "Get or switch between the main and alt monitor speakers",
+	Long: `Get or set speaker switching on interfaces with main and alt monitor
+outputs. The targets come from the device (usually off, main and alt).`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		card, err := findCard(cmd, args[0])
+		if err != nil {
+			return err
+		}
+		defer card.Close()
+
+		if len(args) == 2 {
+			if err := card.SetSpeakerSwitch(args[1]); err != nil {
+				return err
+			}
+		}
+
+		target, err := card.GetSpeakerSwitch()
+		if err != nil {
+			return err
+		}
+
+		if structured(cmd) {
+			return writeResult(cmd, settingResult{Card: card.Name, Setting: "speakers", Value: target})
+		}
+		fmt.Printf("speakers = %s\n", target)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(speakersCmd)
+}
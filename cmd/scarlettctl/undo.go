@@ -0,0 +1,187 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/michaelquigley/scarlettctl"
+	"github.com/spf13/cobra"
+)
+
+// defaultUndoDepth is how many writes per card 'scarlettctl undo' can revert,
+// unless the config sets undo_depth
+const defaultUndoDepth = 10
+
+// undoDepth is the undo stack depth, from the config
+var undoDepth = defaultUndoDepth
+
+// undoCommands are the commands whose writes are recorded for undo
+var undoCommands = map[string]bool{"set": true, "gain": true, "phantom": true, "route": true}
+
+// undoCards are the cards opened with undo recording during this run
+var undoCards []*scarlettctl.Card
+
+var undoCmd = &cobra.Command{
+	Use:   "undo <card> [count]",
+	Short: "Revert the last writes made by set, gain, phantom or route",
+	Long: `Revert the most recent writes made by set, gain, phantom and route.
+
+Before each of those commands writes a control, the value it replaces is
+recorded in the state file. undo restores the newest recorded value, or the
+newest count values, newest first. Each card keeps the last 10 writes, or
+undo_depth from the config file. With --list, show the recorded writes
+instead.`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		count := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n < 1 {
+				return fmt.Errorf("invalid count '%s'", args[1])
+			}
+			count = n
+		}
+
+		card, err := findCard(cmd, args[0])
+		if err != nil {
+			return err
+		}
+		defer card.Close()
+
+		path, err := scarlettctl.DefaultStatePath()
+		if err != nil {
+			return err
+		}
+		state, err := scarlettctl.LoadState(path)
+		if err != nil {
+			return err
+		}
+
+		key := card.StateKey()
+		if list, _ := cmd.Flags().GetBool("list"); list {
+			return listUndo(cmd, card, state.Undo[key])
+		}
+
+		if len(state.Undo[key]) == 0 {
+			return fmt.Errorf("nothing to undo on %s", card)
+		}
+
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		var failure error
+		for i := 0; i < count; i++ {
+			record, ok := state.PopUndo(key)
+			if !ok {
+				break
+			}
+			if err := card.Revert(record); err != nil {
+				state.PushUndo(key, []scarlettctl.UndoRecord{record}, 0)
+				failure = err
+				break
+			}
+			if !dryRun {
+				printReverted(card, record)
+			}
+		}
+
+		if !dryRun {
+			if err := state.Save(path); err != nil {
+				return err
+			}
+		}
+		return failure
+	},
+}
+
+// listUndo prints a card's recorded writes, newest first
+func listUndo(cmd *cobra.Command, card *scarlettctl.Card, records []scarlettctl.UndoRecord) error {
+	if structured(cmd) {
+		results := make([]undoResult, 0, len(records))
+		for i := len(records) - 1; i >= 0; i-- {
+			results = append(results, newUndoResult(card, records[i]))
+		}
+		return writeResult(cmd, results)
+	}
+
+	if len(records) == 0 {
+		fmt.Printf("nothing to undo on %s\n", card)
+		return nil
+	}
+
+	for i := len(records) - 1; i >= 0; i-- {
+		record := records[i]
+		ctl, err := card.FindControlByID(record.Control)
+		if err != nil {
+			fmt.Printf("%s  %s (not found)\n", record.Time.Format("15:04:05"), record.Control)
+			continue
+		}
+		fmt.Printf("%s  %s: %s -> %s\n", record.Time.Format("15:04:05"), ctl.Name, ctl.FormatValue(record.Previous), ctl.FormatValue(record.Value))
+	}
+	return nil
+}
+
+// newUndoResult describes a recorded write, with its values rendered when the
+// control still exists
+func newUndoResult(card *scarlettctl.Card, record scarlettctl.UndoRecord) undoResult {
+	result := undoResult{Time: record.Time, Control: record.Control, Previous: record.Previous, Value: record.Value}
+	if ctl, err := card.FindControlByID(record.Control); err == nil {
+		result.Found = true
+		result.PreviousString = ctl.FormatValue(record.Previous)
+		result.ValueString = ctl.FormatValue(record.Value)
+	}
+	return result
+}
+
+// printReverted reports a restored value
+func printReverted(card *scarlettctl.Card, record scarlettctl.UndoRecord) {
+	ctl, err := card.FindControlByID(record.Control)
+	if err != nil {
+		return
+	}
+	fmt.Printf("%s = %s (was %s)\n", ctl.Name, ctl.FormatValue(record.Previous), ctl.FormatValue(record.Value))
+}
+
+// recordUndo turns on undo recording for a card opened by a writing command
+func recordUndo(cmd *cobra.Command, card *scarlettctl.Card) {
+	if !undoCommands[cmd.Name()] {
+		return
+	}
+	card.EnableUndo(undoDepth)
+	undoCards = append(undoCards, card)
+}
+
+// saveUndo adds the writes recorded during this run to the state file
+func saveUndo() error {
+	var recorded bool
+	for _, card := range undoCards {
+		if len(card.UndoHistory()) > 0 {
+			recorded = true
+			break
+		}
+	}
+	if !recorded {
+		return nil
+	}
+
+	path, err := scarlettctl.DefaultStatePath()
+	if err != nil {
+		return err
+	}
+	state, err := scarlettctl.LoadState(path)
+	if err != nil {
+		return err
+	}
+
+	for _, card := range undoCards {
+		var records []scarlettctl.UndoRecord
+		for _, entry := range card.UndoHistory() {
+			records = append(records, entry.Record())
+		}
+		state.PushUndo(card.StateKey(), records, undoDepth)
+	}
+	return state.Save(path)
+}
+
+func init() {
+	undoCmd.Flags().Bool("list", false, "List the recorded writes instead of reverting")
+	rootCmd.AddCommand(undoCmd)
+}
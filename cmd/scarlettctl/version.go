@@ -0,0 +1,82 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"runtime/debug"
+
+	"github.com/michaelquigley/scarlettctl"
+	"github.com/spf13/cobra"
+)
+
+// version is the scarlettctl build version, set at build time with
+// -ldflags "-X main.version=..."; otherwise the module version is used
+var version = ""
+
+var versionCmd = &cobra.Command{
+	Use:   "version [card]",
+	Short: "Show the scarlettctl, ALSA driver and device firmware versions",
+	Long: `Show the scarlettctl build version and the kernel's ALSA driver version.
+Given a card, also show its firmware version where the driver exposes it.
+Include this output when reporting a bug.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		result := versionResult{Version: buildVersion()}
+
+		driverText, err := scarlettctl.DriverVersion()
+		if err != nil {
+			driverText = fmt.Sprintf("unknown (%v)", err)
+		} else {
+			result.Driver = driverText
+		}
+
+		var card *scarlettctl.Card
+		if len(args) == 1 {
+			if card, err = findCard(cmd, args[0]); err != nil {
+				return err
+			}
+			defer card.Close()
+
+			result.Card = card.Name
+			firmware, err := card.FirmwareVersion()
+			switch {
+			case errors.Is(err, scarlettctl.ErrNotSupported):
+			case err != nil:
+				return err
+			default:
+				result.Firmware = firmware
+			}
+		}
+
+		if structured(cmd) {
+			return writeResult(cmd, result)
+		}
+
+		fmt.Printf("scarlettctl %s\n", result.Version)
+		fmt.Printf("alsa driver %s\n", driverText)
+		if card != nil {
+			firmware := result.Firmware
+			if firmware == "" {
+				firmware = "not reported by the driver"
+			}
+			fmt.Printf("%s firmware %s\n", card, firmware)
+		}
+		return nil
+	},
+}
+
+// buildVersion returns the version set at build time, or the module version
+// recorded by 'go install'
+func buildVersion() string {
+	if version != "" {
+		return version
+	}
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" {
+		return info.Main.Version
+	}
+	return "(devel)"
+}
+
+func init() {
+	rootCmd.AddCommand(versionCmd)
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/michaelquigley/scarlettctl"
+	"github.com/spf13/cobra"
+)
+
+// watchCards watches several cards at once, one event monitor per card in its
+// own goroutine, printing each change prefixed with its card. A card that
+// can't be opened or whose monitor fails is reported and the rest carry on;
+// ctrl+c stops every monitor and waits for them before the cards are closed.
+func watchCards(cmd *cobra.Command, identifiers []string) error {
+	for _, flag := range []string{"snapshot", "stats", "min-interval", "since"} {
+		if cmd.Flags().Changed(flag) {
+			return fmt.Errorf("--%s watches a single card", flag)
+		}
+	}
+
+	filter, err := watchFilter(cmd)
+	if err != nil {
+		return err
+	}
+	interval, _ := cmd.Flags().GetDuration("interval")
+	timeout, _ := cmd.Flags().GetDuration("poll-timeout")
+
+	var cards []*scarlettctl.Card
+	for _, identifier := range identifiers {
+		card, err := findCard(cmd, identifier)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "card %s: %v\n", identifier, err)
+			continue
+		}
+		defer card.Close()
+		cards = append(cards, card)
+	}
+	if len(cards) == 0 {
+		return fmt.Errorf("no cards could be opened")
+	}
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
+
+	// changes from every card go through one lock so lines don't interleave
+	var printMu sync.Mutex
+	var wg sync.WaitGroup
+	done := make(chan struct{}, len(cards))
+	monitors := make([]*scarlettctl.EventMonitor, len(cards))
+
+	for i, card := range cards {
+		monitor := card.NewEventMonitor()
+		if interval > 0 {
+			monitor.SetPollInterval(interval)
+		}
+		if timeout > 0 {
+			monitor.SetPollTimeout(timeout)
+		}
+		monitor.SetFilter(filter)
+		monitors[i] = monitor
+
+		printMu.Lock()
+		fmt.Printf("monitoring controls for %s\n", card)
+		printMu.Unlock()
+
+		wg.Add(1)
+		go func(card *scarlettctl.Card) {
+			defer wg.Done()
+			defer func() { done <- struct{}{} }()
+
+			prefix := fmt.Sprintf("[%d:%s]", card.Number, card.Name)
+			lastValue := make(map[scarlettctl.ControlKey]int64)
+			err := monitor.WatchControls(func(ctl *scarlettctl.Control, value int64) error {
+				key := ctl.Key()
+				if last, ok := lastValue[key]; ok && last == value {
+					return nil
+				}
+				lastValue[key] = value
+
+				name := ctl.Name
+				if ctl.Count > 1 {
+					name = fmt.Sprintf("%s[%d]", ctl.Name, ctl.Index)
+				}
+				printMu.Lock()
+				fmt.Printf("[%s] %s %-50s = %s\n", time.Now().Format("15:04:05"), prefix, name, ctl.FormatValue(value))
+				printMu.Unlock()
+				return nil
+			})
+			if err != nil {
+				printMu.Lock()
+				fmt.Fprintf(os.Stderr, "%s: monitoring stopped: %v\n", card, err)
+				printMu.Unlock()
+			}
+		}(card)
+	}
+
+	stopAll := func() {
+		for _, monitor := range monitors {
+			monitor.Stop()
+		}
+		wg.Wait()
+	}
+
+	for running := len(cards); running > 0; running-- {
+		select {
+		case <-sigChan:
+			fmt.Println("\nstopping monitors...")
+			stopAll()
+			return nil
+		case <-done:
+		}
+	}
+
+	stopAll()
+	return fmt.Errorf("monitoring stopped on every card")
+}
@@ -0,0 +1,267 @@
+package scarlettctl
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DeviceConfig is a desired device state, e.g. to restore after every reboot
+// or replug. Only the settings it declares are checked and applied.
+//
+//	routing:
+//	  PCM 01: Analogue 1        # sink pattern: source name
+//	preamps:
+//	  - channel: 1
+//	    gain: 20
+//	    phantom: true
+//	mixer:
+//	  - mix: A
+//	    input: 1
+//	    level: unity            # raw value, percentage or unity
+//	controls:
+//	  Direct Monitor Playback Enum: Mono   # any control, as for 'set'
+type DeviceConfig struct {
+	Routing  map[string]string `yaml:"routing,omitempty"`
+	Preamps  []PreampConfig    `yaml:"preamps,omitempty"`
+	Mixer    []MixerConfig     `yaml:"mixer,omitempty"`
+	Controls map[string]string `yaml:"controls,omitempty"`
+}
+
+// PreampConfig is the desired state of a preamp channel; unset fields are left alone
+type PreampConfig struct {
+	Channel int    `yaml:"channel"`
+	Gain    *int64 `yaml:"gain,omitempty"`
+	Phantom *bool  `yaml:"phantom,omitempty"`
+	Air     *bool  `yaml:"air,omitempty"`
+	Pad     *bool  `yaml:"pad,omitempty"`
+}
+
+// MixerConfig is the desired level of a mixer input
+type MixerConfig struct {
+	Mix   string `yaml:"mix"`
+	Input int    `yaml:"input"`
+	Level string `yaml:"level"`
+}
+
+// ConfigDrift is a declared setting whose current value differs from the config
+type ConfigDrift struct {
+	Setting string
+	Current string
+	Desired string
+}
+
+func (d ConfigDrift) String() string {
+	return fmt.Sprintf("%s: %s -> %s", d.Setting, d.Current, d.Desired)
+}
+
+// configSetting is a declared setting resolved to its control
+type configSetting struct {
+	name    string
+	ctl     *Control
+	value   int64
+	matches func(current int64) bool
+}
+
+// DefaultConfigPath returns the default startup config location (~/.config/scarlettctl/startup.yaml)
+func DefaultConfigPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "scarlettctl", "startup.yaml"), nil
+}
+
+// LoadDeviceConfig reads a device config file
+func LoadDeviceConfig(path string) (*DeviceConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &DeviceConfig{}
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config file '%s': %v", path, err)
+	}
+	return cfg, nil
+}
+
+// CheckConfig compares the card with a config, returning the declared settings
+// that differ
+func (c *Card) CheckConfig(cfg DeviceConfig) ([]ConfigDrift, error) {
+	settings, err := c.resolveConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	var drift []ConfigDrift
+	for _, s := range settings {
+		current, err := s.ctl.GetValue()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", s.name, err)
+		}
+		if s.matches(current) {
+			continue
+		}
+		drift = append(drift, ConfigDrift{
+			Setting: s.name,
+			Current: s.ctl.FormatValue(current),
+			Desired: s.ctl.FormatValue(s.value),
+		})
+	}
+	return drift, nil
+}
+
+// ApplyConfig brings the card to the state a config declares, writing only
+// the settings that differ. Every setting is resolved before anything is
+// written, so a config naming a missing control changes nothing. Phantom power
+// is switched without the PhantomPowerWarning hook: the config is the
+// confirmation.
+func (c *Card) ApplyConfig(cfg DeviceConfig) error {
+	settings, err := c.resolveConfig(cfg)
+	if err != nil {
+		return err
+	}
+
+	for _, s := range settings {
+		current, err := s.ctl.GetValue()
+		if err != nil {
+			return fmt.Errorf("%s: %v", s.name, err)
+		}
+		if s.matches(current) {
+			continue
+		}
+		if err := s.ctl.setValue(s.value, true); err != nil {
+			return fmt.Errorf("%s: %v", s.name, err)
+		}
+	}
+	return nil
+}
+
+// resolveConfig resolves every declared setting to a control and value, in a
+// stable order: preamps, routing, mixer, then other controls
+func (c *Card) resolveConfig(cfg DeviceConfig) ([]configSetting, error) {
+	var settings []configSetting
+	add := func(name string, ctl *Control, value int64) {
+		settings = append(settings, configSetting{name: name, ctl: ctl, value: value,
+			matches: func(current int64) bool { return current == value }})
+	}
+
+	for _, p := range cfg.Preamps {
+		ch, err := c.GetPreampChannel(p.Channel)
+		if err != nil {
+			return nil, err
+		}
+
+		preampControl := func(field string, ctl *Control) (*Control, error) {
+			if ctl == nil {
+				return nil, fmt.Errorf("channel %d has no %s control", p.Channel, field)
+			}
+			return ctl, nil
+		}
+
+		if p.Gain != nil {
+			ctl, err := preampControl("gain", ch.Gain)
+			if err != nil {
+				return nil, err
+			}
+			add(fmt.Sprintf("input %d gain", p.Channel), ctl, *p.Gain)
+		}
+		if p.Phantom != nil {
+			ctl, err := preampControl("phantom", ch.Phantom)
+			if err != nil {
+				return nil, err
+			}
+			add(fmt.Sprintf("input %d phantom", p.Channel), ctl, boolValue(*p.Phantom))
+		}
+		if p.Air != nil {
+			ctl, err := preampControl("air", ch.Air)
+			if err != nil {
+				return nil, err
+			}
+			// any air mode satisfies "on"
+			on, off := *p.Air, switchOffValue(ctl)
+			value := off
+			if on {
+				value = switchOnValue(ctl)
+			}
+			settings = append(settings, configSetting{name: fmt.Sprintf("input %d air", p.Channel), ctl: ctl, value: value,
+				matches: func(current int64) bool { return (current != off) == on }})
+		}
+		if p.Pad != nil {
+			ctl, err := preampControl("pad", ch.Pad)
+			if err != nil {
+				return nil, err
+			}
+			add(fmt.Sprintf("input %d pad", p.Channel), ctl, boolValue(*p.Pad))
+		}
+	}
+
+	if len(cfg.Routing) > 0 {
+		sources, err := c.GetRoutingSources()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, pattern := range sortedKeys(cfg.Routing) {
+			sink, err := c.FindRoutingSink(pattern)
+			if err != nil {
+				return nil, err
+			}
+
+			source := cfg.Routing[pattern]
+			id := -1
+			for _, src := range sources {
+				if strings.EqualFold(src.Name, source) {
+					id = src.ID
+					break
+				}
+			}
+			if id < 0 {
+				return nil, fmt.Errorf("routing source '%s' not found", source)
+			}
+			add("route "+shortSinkName(sink.Name), sink.Control, int64(id))
+		}
+	}
+
+	for _, m := range cfg.Mixer {
+		mix := NormalizeMixName(m.Mix)
+		ctl, err := c.GetMixerInput(mix, m.Input)
+		if err != nil {
+			return nil, err
+		}
+		level, err := ParseMixerLevel(ctl, m.Level)
+		if err != nil {
+			return nil, fmt.Errorf("%s input %d: %v", mix, m.Input, err)
+		}
+		add(fmt.Sprintf("%s input %d", mix, m.Input), ctl, level)
+	}
+
+	for _, name := range sortedKeys(cfg.Controls) {
+		ctl, err := c.FindControl(name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := ctl.ParseValue(cfg.Controls[name])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", name, err)
+		}
+		add(ctl.Name, ctl, value)
+	}
+
+	return settings, nil
+}
+
+// sortedKeys returns a map's keys in order, so config maps apply deterministically
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -1,19 +1,54 @@
 package scarlettctl
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // GetControls returns all controls for this card
+// Elements the driver fails to describe are skipped; use GetControlsWithErrors
+// to find out which.
 func (c *Card) GetControls() ([]*Control, error) {
-	if c.handle == nil {
-		return nil, fmt.Errorf("card not open")
+	controls, failed, err := c.GetControlsWithErrors()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, failure := range failed {
+		logger.Debug("skipping unqueryable control", "numid", failure.NumID, "name", failure.Name, "error", failure.Err)
+	}
+
+	return controls, nil
+}
+
+// GetControlsWithErrors returns all controls for this card, together with an
+// error for each element that couldn't be queried and so is missing from the
+// list. The returned error is only set when enumeration fails as a whole.
+// With the discovery cache enabled, a card whose metadata is cached isn't
+// enumerated at all; only cards where every element could be queried are
+// cached.
+func (c *Card) GetControlsWithErrors() ([]*Control, []ControlError, error) {
+	if err := c.checkOpen(); err != nil {
+		return nil, nil, err
 	}
 
-	controls, err := enumerateControls(c.handle)
+	if controls, ok := c.cachedControls(); ok {
+		return controls, nil, nil
+	}
+
+	var controls []*Control
+	var failed []ControlError
+	err := c.call(func(h *alsaHandle) (err error) {
+		controls, failed, err = hardware.enumerate(h)
+		return err
+	})
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// link controls back to their card
@@ -21,12 +56,36 @@ func (c *Card) GetControls() ([]*Control, error) {
 		ctl.card = c
 	}
 
-	return controls, nil
+	markAmbiguous(controls)
+	logger.Debug("enumerated controls", "card", c.Name, "count", len(controls), "failed", len(failed))
+
+	if len(failed) == 0 {
+		c.storeDiscovery(controls)
+	}
+
+	return controls, failed, nil
+}
+
+// markAmbiguous flags controls whose name is shared by more than one element
+// (e.g. the same name on different interfaces or devices)
+func markAmbiguous(controls []*Control) {
+	numids := make(map[string]map[uint]bool)
+	for _, ctl := range controls {
+		if numids[ctl.Name] == nil {
+			numids[ctl.Name] = make(map[uint]bool)
+		}
+		numids[ctl.Name][ctl.NumID] = true
+	}
+
+	for _, ctl := range controls {
+		ctl.ambiguous = len(numids[ctl.Name]) > 1
+	}
 }
 
 // FindControl finds a control by exact name or full ID
 // If the input contains ':' and '/', it is treated as a full ID (e.g., "mixer:0.0/Level Meter[0]")
-// Otherwise it is treated as a control name
+// Otherwise it is treated as a control name; an exact match is preferred, then a
+// unique case-insensitive one, then preamp shorthand such as "gain1" or "phantom2"
 func (c *Card) FindControl(name string) (*Control, error) {
 	// try full ID lookup if input looks like an ID
 	if strings.Contains(name, ":") && strings.Contains(name, "/") {
@@ -40,11 +99,65 @@ func (c *Card) FindControl(name string) (*Control, error) {
 
 	for _, ctl := range controls {
 		if ctl.Name == name {
+			if ctl.ambiguous {
+				return nil, ambiguousError(name, controls)
+			}
+			logger.Debug("resolved control", "name", name, "id", ctl.FullID())
 			return ctl, nil
 		}
 	}
 
-	return nil, fmt.Errorf("control '%s' not found", name)
+	// fall back to a case-insensitive match, which must be unique
+	var matched []*Control
+	numids := make(map[uint]bool)
+	for _, ctl := range controls {
+		if strings.EqualFold(ctl.Name, name) && !numids[ctl.NumID] {
+			numids[ctl.NumID] = true
+			matched = append(matched, ctl)
+		}
+	}
+	switch len(matched) {
+	case 0:
+		// preamp shorthand such as "gain1" or "phantom2"
+		if ctl, ok, err := c.findPreampShorthand(name); ok {
+			return ctl, err
+		}
+		logControlNames(name, controls)
+		return nil, fmt.Errorf("control '%s' not found", name)
+	case 1:
+		logger.Debug("resolved control ignoring case", "name", name, "id", matched[0].FullID())
+		return matched[0], nil
+	}
+
+	var ids []string
+	for _, ctl := range matched {
+		ids = append(ids, ctl.FullID())
+	}
+	return nil, fmt.Errorf("control '%s' is %w, matching:\n  %s", name, ErrAmbiguous, strings.Join(ids, "\n  "))
+}
+
+// logControlNames logs the enumerated names when a lookup fails, to show what
+// was available
+func logControlNames(name string, controls []*Control) {
+	if !logger.Enabled(context.Background(), slog.LevelDebug) {
+		return
+	}
+	for _, ctl := range controls {
+		if ctl.Index == 0 {
+			logger.Debug("no match for control", "name", name, "candidate", ctl.FullID())
+		}
+	}
+}
+
+// ambiguousError lists the full IDs of every element named name
+func ambiguousError(name string, controls []*Control) error {
+	var ids []string
+	for _, ctl := range controls {
+		if ctl.Name == name && ctl.Index == 0 {
+			ids = append(ids, ctl.FullID())
+		}
+	}
+	return fmt.Errorf("control '%s' is %w, use a full ID:\n  %s", name, ErrAmbiguous, strings.Join(ids, "\n  "))
 }
 
 // FindControlByID finds a control by its full identifier
@@ -57,9 +170,11 @@ func (c *Card) FindControlByID(id string) (*Control, error) {
 
 	for _, ctl := range controls {
 		if ctl.FullID() == id {
+			logger.Debug("resolved control", "name", id, "id", ctl.FullID())
 			return ctl, nil
 		}
 	}
+	logControlNames(id, controls)
 
 	return nil, fmt.Errorf("control with id '%s' not found", id)
 }
@@ -73,6 +188,7 @@ func (c *Card) FindControlByPrefix(prefix string) (*Control, error) {
 
 	for _, ctl := range controls {
 		if strings.HasPrefix(ctl.Name, prefix) {
+			logger.Debug("resolved control by prefix", "prefix", prefix, "id", ctl.FullID())
 			return ctl, nil
 		}
 	}
@@ -103,21 +219,197 @@ func (c *Card) FindControlsMatching(pattern string) ([]*Control, error) {
 	return matched, nil
 }
 
+// FindControlsWithItem finds the enumerated controls offering an item, e.g.
+// every routing sink that can select "ADAT 1". It searches values rather than
+// names; the item must match exactly (see FindControlsWithItemFold).
+func (c *Card) FindControlsWithItem(item string) ([]*Control, error) {
+	return c.findControlsWithItem(item, func(a, b string) bool { return a == b })
+}
+
+// FindControlsWithItemFold finds the enumerated controls offering an item,
+// matched case-insensitively
+func (c *Card) FindControlsWithItemFold(item string) ([]*Control, error) {
+	return c.findControlsWithItem(item, strings.EqualFold)
+}
+
+// findControlsWithItem finds the enumerated controls with an item equal to item
+func (c *Card) findControlsWithItem(item string, equal func(a, b string) bool) ([]*Control, error) {
+	controls, err := c.GetControls()
+	if err != nil {
+		return nil, err
+	}
+
+	var matched []*Control
+	for _, ctl := range controls {
+		if ctl.Type != ControlTypeEnumerated {
+			continue
+		}
+		for _, candidate := range ctl.Items {
+			if equal(candidate, item) {
+				matched = append(matched, ctl)
+				break
+			}
+		}
+	}
+
+	if len(matched) == 0 {
+		return nil, fmt.Errorf("no controls with item '%s' found", item)
+	}
+
+	return matched, nil
+}
+
+// FindControlsByRegex finds all controls whose name matches a regular expression
+func (c *Card) FindControlsByRegex(pattern string) ([]*Control, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern '%s': %v", pattern, err)
+	}
+
+	controls, err := c.GetControls()
+	if err != nil {
+		return nil, err
+	}
+
+	var matched []*Control
+	for _, ctl := range controls {
+		if re.MatchString(ctl.Name) {
+			matched = append(matched, ctl)
+		}
+	}
+
+	if len(matched) == 0 {
+		return nil, fmt.Errorf("no controls matching '%s' found", pattern)
+	}
+
+	return matched, nil
+}
+
+// GetControlsByType returns all controls of the given type
+func (c *Card) GetControlsByType(t ControlType) ([]*Control, error) {
+	controls, err := c.GetControls()
+	if err != nil {
+		return nil, err
+	}
+
+	var matched []*Control
+	for _, ctl := range controls {
+		if ctl.Type == t {
+			matched = append(matched, ctl)
+		}
+	}
+
+	return matched, nil
+}
+
 // GetValue reads the current value of the control
 func (ctl *Control) GetValue() (int64, error) {
-	if ctl.card == nil || ctl.card.handle == nil {
-		return 0, fmt.Errorf("control not associated with open card")
+	if err := ctl.checkCard(); err != nil {
+		return 0, err
+	}
+
+	if value, ok := ctl.card.cachedValue(ctl.Key()); ok {
+		return value, nil
+	}
+	return ctl.readHardware()
+}
+
+// readHardware reads the control's value from the hardware, bypassing the
+// cache, and refreshes the cache with it
+func (ctl *Control) readHardware() (int64, error) {
+	var value int64
+	err := ctl.card.call(func(h *alsaHandle) (err error) {
+		value, err = hardware.read(h, ctl)
+		return err
+	})
+	if err != nil {
+		logger.Debug("read failed", "id", ctl.FullID(), "error", err)
+		return 0, err
+	}
+	logger.Debug("read", "id", ctl.FullID(), "value", value)
+
+	ctl.card.storeValue(ctl.Key(), value)
+	return value, nil
+}
+
+// ReadAllValues reads the current value of every control on the card
+// Each element is read once regardless of its value count, so multi-value
+// controls such as level meters cost a single ALSA read rather than one per
+// index. Values are keyed by numid and index, as the values of a multi-value
+// element share one numid. Controls whose type can't be read as an integer are
+// omitted.
+func (c *Card) ReadAllValues() (map[ControlKey]int64, error) {
+	controls, err := c.GetControls()
+	if err != nil {
+		return nil, err
+	}
+
+	return c.readValues(controls), nil
+}
+
+// readValues reads the given controls, issuing one read per element
+// It always reads the hardware, refreshing the cache with what it finds.
+func (c *Card) readValues(controls []*Control) map[ControlKey]int64 {
+	values := make(map[ControlKey]int64, len(controls))
+	read := make(map[uint]bool)
+
+	for _, ctl := range controls {
+		if read[ctl.NumID] {
+			continue
+		}
+		read[ctl.NumID] = true
+
+		var elemValues []int64
+		err := c.call(func(h *alsaHandle) (err error) {
+			elemValues, err = hardware.readElement(h, ctl.NumID, ctl.Type, ctl.Count)
+			return err
+		})
+		if err != nil {
+			logger.Debug("skipping unreadable control", "id", ctl.FullID(), "error", err)
+			continue // skip controls we can't read
+		}
+		logger.Debug("read element", "numid", ctl.NumID, "name", ctl.Name, "values", elemValues)
+
+		for idx, value := range elemValues {
+			key := ControlKey{NumID: ctl.NumID, Index: idx}
+			values[key] = value
+			c.storeValue(key, value)
+		}
 	}
 
-	return readControl(ctl.card.handle, ctl)
+	return values
 }
 
-// SetValue writes a value to the control
+// SetValue writes a value to the control. Switching phantom power on runs the
+// card's PhantomPowerWarning hook first.
 func (ctl *Control) SetValue(value int64) error {
-	if ctl.card == nil || ctl.card.handle == nil {
-		return fmt.Errorf("control not associated with open card")
+	return ctl.setValue(value, false)
+}
+
+// setValue writes a value to the control, reading it back in verify mode;
+// warned skips the PhantomPowerWarning hook, for callers that have already run
+// it
+func (ctl *Control) setValue(value int64, warned bool) error {
+	if err := ctl.checkCard(); err != nil {
+		return err
+	}
+	if err := ctl.checkValue(value); err != nil {
+		return err
 	}
 
+	c := ctl.card
+	if err := c.write(ctl, value, warned); err != nil {
+		return err
+	}
+	if c.verify && c.dryRun == nil {
+		return c.verifyWrite(ctl, value)
+	}
+	return nil
+}
+
+// checkValue validates a value against the control's range or enum items, and
+// the card's gain caps
+func (ctl *Control) checkValue(value int64) error {
 	// validate value range for integer types
 	if ctl.Type == ControlTypeInteger || ctl.Type == ControlTypeInteger64 {
 		if value < ctl.Min || value > ctl.Max {
@@ -132,74 +424,398 @@ func (ctl *Control) SetValue(value int64) error {
 		}
 	}
 
-	return writeControl(ctl.card.handle, ctl, value)
+	return ctl.card.checkGainCap(ctl, value)
+}
+
+// write performs a validated write, recording undo history when enabled and
+// running the PhantomPowerWarning hook unless warned
+func (c *Card) write(ctl *Control, value int64, warned bool) error {
+	if c.dryRun != nil {
+		previous, err := ctl.GetValue()
+		if err != nil {
+			return err
+		}
+		c.dryRun(ctl, previous, value)
+		return nil
+	}
+
+	if !warned {
+		if err := c.warnPhantomWrite(ctl, value); err != nil {
+			return err
+		}
+	}
+
+	var previous int64
+	recordUndo := c.undoEnabled()
+	if recordUndo {
+		prev, err := ctl.GetValue()
+		if err != nil {
+			recordUndo = false // nothing to restore to
+		}
+		previous = prev
+	}
+
+	if err := c.writeRaw(ctl, value); err != nil {
+		return err
+	}
+
+	if recordUndo {
+		c.pushUndo(UndoEntry{
+			Control:  ctl,
+			Previous: previous,
+			Value:    value,
+			Time:     time.Now(),
+		})
+	}
+
+	return nil
+}
+
+// SetValueVerified writes a value like SetValue, then reads it back from the
+// hardware and returns an error wrapping ErrVerifyFailed if the device reports a
+// different value than was written. Unless exact, the control's step rounding
+// and range clamping are allowed for; exact catches writes the driver silently
+// adjusted or ignored, such as a rejected enum item. The value is read back
+// once, even in verify mode.
+func (ctl *Control) SetValueVerified(value int64, exact bool) error {
+	if err := ctl.checkCard(); err != nil {
+		return err
+	}
+	if err := ctl.checkValue(value); err != nil {
+		return err
+	}
+
+	c := ctl.card
+	if err := c.write(ctl, value, false); err != nil {
+		return err
+	}
+
+	// nothing was written in dry-run mode
+	if c.dryRun != nil {
+		return nil
+	}
+	if exact {
+		return c.checkWrite(ctl, value, 1)
+	}
+	return c.verifyWrite(ctl, value)
+}
+
+// verifyWrite reads a control back from the hardware, bypassing the cache, and
+// checks it holds the value just written, allowing for step rounding
+func (c *Card) verifyWrite(ctl *Control, value int64) error {
+	tolerance := ctl.Step
+	if tolerance < 1 {
+		tolerance = 1
+	}
+	return c.checkWrite(ctl, ctl.clamp(value), tolerance)
+}
+
+// checkWrite reads a control back from the hardware, bypassing the cache, and
+// checks it is within tolerance of the expected value (1 for an exact match)
+func (c *Card) checkWrite(ctl *Control, value, tolerance int64) error {
+	actual, err := ctl.readHardware()
+	if err != nil {
+		return fmt.Errorf("failed to verify %s: %w", ctl.Name, err)
+	}
+
+	diff := actual - value
+	if diff <= -tolerance || diff >= tolerance {
+		return fmt.Errorf("%s: wrote %s but device reports %s: %w",
+			ctl.Name, ctl.FormatValue(value), ctl.FormatValue(actual), ErrVerifyFailed)
+	}
+
+	logger.Debug("verified write", "id", ctl.FullID(), "value", actual)
+	return nil
+}
+
+// writeRaw writes a value to the hardware, keeping the cache in step
+func (c *Card) writeRaw(ctl *Control, value int64) error {
+	if c.locked {
+		return fmt.Errorf("cannot write %s: %w", ctl.Name, ErrLocked)
+	}
+
+	err := c.call(func(h *alsaHandle) error {
+		return hardware.write(h, ctl, value)
+	})
+	if err != nil {
+		logger.Debug("write failed", "id", ctl.FullID(), "value", value, "error", err)
+		return err
+	}
+	logger.Debug("write", "id", ctl.FullID(), "value", value)
+
+	c.storeValue(ctl.Key(), value)
+	return nil
 }
 
 // GetValueString returns the control value as a human-readable string
 func (ctl *Control) GetValueString() (string, error) {
+	if ctl.Type == ControlTypeIEC958 {
+		status, err := ctl.GetIEC958Status()
+		if err != nil {
+			return "", err
+		}
+		return status.String(), nil
+	}
+
 	value, err := ctl.GetValue()
 	if err != nil {
 		return "", err
 	}
 
+	return ctl.FormatValue(value), nil
+}
+
+// FormatValue returns a human-readable string for a value of this control
+func (ctl *Control) FormatValue(value int64) string {
 	switch ctl.Type {
 	case ControlTypeBoolean:
 		if value == 0 {
-			return "Off", nil
+			return "Off"
 		}
-		return "On", nil
+		return "On"
 
 	case ControlTypeEnumerated:
-		if value >= 0 && value < int64(len(ctl.Items)) {
-			return ctl.Items[value], nil
+		if ctl.valueInRange(value) {
+			return ctl.Items[value]
 		}
-		return fmt.Sprintf("Unknown(%d)", value), nil
+		return unknownValue(value)
 
 	case ControlTypeInteger, ControlTypeInteger64:
-		return fmt.Sprintf("%d", value), nil
+		if ctl.IsBooleanLike() && (value == 0 || value == 1) {
+			return [...]string{"Off", "On"}[value]
+		}
+		return fmt.Sprintf("%d", value)
 
 	default:
-		return fmt.Sprintf("%d", value), nil
+		return fmt.Sprintf("%d", value)
+	}
+}
+
+// unknownValue renders an enum value that has no matching item
+func unknownValue(value int64) string {
+	return fmt.Sprintf("Unknown(%d)", value)
+}
+
+// valueInRange reports whether value is within the control's declared range
+func (ctl *Control) valueInRange(value int64) bool {
+	switch ctl.Type {
+	case ControlTypeBoolean:
+		return value == 0 || value == 1
+	case ControlTypeEnumerated:
+		return value >= 0 && value < int64(len(ctl.Items))
+	case ControlTypeInteger, ControlTypeInteger64:
+		return value >= ctl.Min && value <= ctl.Max
+	default:
+		return true
 	}
 }
 
+// IsOff reports whether the control is currently off: for enums, whether the
+// value is the item named "Off" (case-insensitive), wherever the device puts it
+// in the list; for other types, whether the value is 0
+func (ctl *Control) IsOff() (bool, error) {
+	value, err := ctl.GetValue()
+	if err != nil {
+		return false, err
+	}
+	return ctl.isOffValue(value), nil
+}
+
+// isOffValue reports whether a value of the control means off, as for IsOff
+func (ctl *Control) isOffValue(value int64) bool {
+	if ctl.Type == ControlTypeEnumerated {
+		return ctl.valueInRange(value) && strings.EqualFold(ctl.Items[value], "Off")
+	}
+	return value == 0
+}
+
+// IsValueValid reports whether the control's current value is within its
+// declared range. Firmware can report an enum value beyond the known items,
+// e.g. after a partial re-enumeration; such values render as "Unknown(n)".
+func (ctl *Control) IsValueValid() (bool, error) {
+	value, err := ctl.GetValue()
+	if err != nil {
+		return false, err
+	}
+	return ctl.valueInRange(value), nil
+}
+
 // SetValueByString sets the control value from a string representation
 func (ctl *Control) SetValueByString(valueStr string) error {
+	value, err := ctl.ParseValue(valueStr)
+	if err != nil {
+		return err
+	}
+	return ctl.SetValue(value)
+}
+
+// ParseValue parses a string representation of a value for the control: on/off
+// for booleans, an item name (or unique part of one) or index for enums, and a
+// number for integers
+func (ctl *Control) ParseValue(valueStr string) (int64, error) {
 	switch ctl.Type {
 	case ControlTypeBoolean:
-		lowerVal := strings.ToLower(valueStr)
-		if lowerVal == "on" || lowerVal == "true" || lowerVal == "1" || lowerVal == "yes" {
-			return ctl.SetValue(1)
-		}
-		if lowerVal == "off" || lowerVal == "false" || lowerVal == "0" || lowerVal == "no" {
-			return ctl.SetValue(0)
-		}
-		return fmt.Errorf("invalid boolean value: %s (use on/off, true/false, 1/0, yes/no)", valueStr)
+		return parseBool(valueStr)
 
 	case ControlTypeEnumerated:
 		// try to find matching enum item
 		for i, item := range ctl.Items {
 			if strings.EqualFold(item, valueStr) {
-				return ctl.SetValue(int64(i))
+				return int64(i), nil
 			}
 		}
 		// try parsing as index
-		var index int64
-		if _, err := fmt.Sscanf(valueStr, "%d", &index); err == nil {
-			return ctl.SetValue(index)
+		if index, err := strconv.ParseInt(valueStr, 10, 64); err == nil {
+			return index, nil
+		}
+		// fall back to a unique partial match, e.g. "ADAT" for "ADAT (optical)"
+		index, err := ctl.matchItem(valueStr)
+		if err != nil {
+			return 0, err
 		}
-		return fmt.Errorf("invalid enum value: %s (valid: %v)", valueStr, ctl.Items)
+		return int64(index), nil
 
 	case ControlTypeInteger, ControlTypeInteger64:
-		var value int64
-		if _, err := fmt.Sscanf(valueStr, "%d", &value); err != nil {
-			return fmt.Errorf("invalid integer value: %s", valueStr)
+		if ctl.IsBooleanLike() {
+			return parseBool(valueStr)
 		}
-		return ctl.SetValue(value)
+		value, err := strconv.ParseInt(valueStr, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid integer value: %s", valueStr)
+		}
+		return value, nil
 
 	default:
-		return fmt.Errorf("unsupported control type: %v", ctl.Type)
+		return 0, fmt.Errorf("unsupported control type: %v", ctl.Type)
+	}
+}
+
+// parseBool parses an on/off value string
+func parseBool(valueStr string) (int64, error) {
+	lowerVal := strings.ToLower(valueStr)
+	if lowerVal == "on" || lowerVal == "true" || lowerVal == "1" || lowerVal == "yes" {
+		return 1, nil
+	}
+	if lowerVal == "off" || lowerVal == "false" || lowerVal == "0" || lowerVal == "no" {
+		return 0, nil
+	}
+	return 0, fmt.Errorf("invalid boolean value: %s (use on/off, true/false, 1/0, yes/no)", valueStr)
+}
+
+// IsBooleanLike reports whether the control is a switch: a boolean, or an
+// integer with a 0..1 range, as some drivers expose switches. Switch-like
+// integers are shown as On/Off and take on/off values like booleans.
+func (ctl *Control) IsBooleanLike() bool {
+	switch ctl.Type {
+	case ControlTypeBoolean:
+		return true
+	case ControlTypeInteger, ControlTypeInteger64:
+		return ctl.Min == 0 && ctl.Max == 1
+	}
+	return false
+}
+
+// matchItem finds the enum item uniquely matched by s, case-insensitively:
+// first by prefix, then by substring
+func (ctl *Control) matchItem(s string) (int, error) {
+	lower := strings.ToLower(s)
+
+	for _, match := range []func(item string) bool{
+		func(item string) bool { return strings.HasPrefix(strings.ToLower(item), lower) },
+		func(item string) bool { return strings.Contains(strings.ToLower(item), lower) },
+	} {
+		var found []int
+		for i, item := range ctl.Items {
+			if match(item) {
+				found = append(found, i)
+			}
+		}
+
+		switch len(found) {
+		case 0:
+			continue
+		case 1:
+			return found[0], nil
+		default:
+			candidates := make([]string, len(found))
+			for i, index := range found {
+				candidates[i] = ctl.Items[index]
+			}
+			return 0, fmt.Errorf("value '%s' is %w, matching: %s", s, ErrAmbiguous, strings.Join(candidates, ", "))
+		}
+	}
+
+	msg := fmt.Sprintf("invalid enum value: %s", s)
+	if closest, ok := closestItem(s, ctl.Items); ok {
+		msg += fmt.Sprintf(" (did you mean '%s'?)", closest)
+	}
+	return 0, fmt.Errorf("%s; valid values:\n%s", msg, formatItems(ctl.Items, 78))
+}
+
+// formatItems lists enum items with their indices, e.g. "0: Off", wrapped to
+// lines of at most width columns
+func formatItems(items []string, width int) string {
+	var sb strings.Builder
+	line := 0
+	for i, item := range items {
+		entry := fmt.Sprintf("%d: %s", i, item)
+		switch {
+		case line == 0:
+			sb.WriteString("  ")
+			line = 2
+		case line+2+len(entry) > width:
+			sb.WriteString(",\n  ")
+			line = 2
+		default:
+			sb.WriteString(", ")
+			line += 2
+		}
+		sb.WriteString(entry)
+		line += len(entry)
+	}
+	return sb.String()
+}
+
+// closestItem returns the item nearest to s by case-insensitive edit distance,
+// if it is close enough to be a likely typo
+func closestItem(s string, items []string) (string, bool) {
+	lower := strings.ToLower(s)
+	best, bestDistance := "", -1
+	for _, item := range items {
+		distance := editDistance(lower, strings.ToLower(item))
+		if bestDistance < 0 || distance < bestDistance {
+			best, bestDistance = item, distance
+		}
 	}
+
+	// allow roughly one typo per three characters
+	limit := len(lower) / 3
+	if limit < 1 {
+		limit = 1
+	}
+	return best, bestDistance >= 0 && bestDistance <= limit
+}
+
+// editDistance returns the Levenshtein distance between two strings
+func editDistance(a, b string) int {
+	ar, br := []rune(a), []rune(b)
+	previous := make([]int, len(br)+1)
+	current := make([]int, len(br)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(ar); i++ {
+		current[0] = i
+		for j := 1; j <= len(br); j++ {
+			cost := 1
+			if ar[i-1] == br[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(br)]
 }
 
 // String returns a string representation of the control
@@ -208,7 +824,12 @@ func (ctl *Control) String() string {
 
 	// show interface/device/subdevice prefix for disambiguation
 	sb.WriteString(fmt.Sprintf("[%s:%d.%d] ", ctl.Interface, ctl.Device, ctl.Subdevice))
-	sb.WriteString(fmt.Sprintf("%-50s [%s]", ctl.Name, ctl.Type))
+	// show the full ID when the name alone doesn't identify the control
+	name := ctl.Name
+	if ctl.ambiguous {
+		name = ctl.FullID()
+	}
+	sb.WriteString(fmt.Sprintf("%-50s [%s]", name, ctl.Type))
 
 	switch ctl.Type {
 	case ControlTypeInteger, ControlTypeInteger64:
@@ -224,11 +845,75 @@ func (ctl *Control) String() string {
 	return sb.String()
 }
 
+// Unit names the unit of the control's values, so a GUI can label it: "dB"
+// for integer controls with a dB scale, "item" for enumerated controls, "bool"
+// for switches and integer controls that only go from 0 to 1, and "" for
+// anything else. Finding a dB scale reads the control's TLV data from the
+// hardware the first time the card is asked about the element.
+func (ctl *Control) Unit() string {
+	switch {
+	case ctl.IsBooleanLike():
+		return "bool"
+	case ctl.Type == ControlTypeEnumerated:
+		return "item"
+	case ctl.Type == ControlTypeInteger || ctl.Type == ControlTypeInteger64:
+		if ctl.card != nil && ctl.card.hasDecibelScale(ctl) {
+			return "dB"
+		}
+	}
+	return ""
+}
+
+// IsAmbiguous reports whether another element on the card shares this control's name
+// Such controls must be addressed by FullID.
+func (ctl *Control) IsAmbiguous() bool {
+	return ctl.ambiguous
+}
+
+// Key returns the numid/index pair identifying this control value
+func (ctl *Control) Key() ControlKey {
+	return ControlKey{NumID: ctl.NumID, Index: ctl.Index}
+}
+
 // FullID returns a unique identifier string for the control
 func (ctl *Control) FullID() string {
 	return fmt.Sprintf("%s:%d.%d/%s[%d]", ctl.Interface, ctl.Device, ctl.Subdevice, ctl.Name, ctl.Index)
 }
 
+// checkCard fails unless the control belongs to a card that is still open
+func (ctl *Control) checkCard() error {
+	if ctl.card == nil {
+		return fmt.Errorf("control not associated with a card")
+	}
+	return ctl.card.checkOpen()
+}
+
+// ElementID returns the identifier of the control's element, without the
+// value index, e.g. "mixer:0.0/Level Meter"
+func (ctl *Control) ElementID() string {
+	return fmt.Sprintf("%s:%d.%d/%s", ctl.Interface, ctl.Device, ctl.Subdevice, ctl.Name)
+}
+
+// GroupElements collapses per-index controls back into their ALSA elements,
+// in the order each element first appears. GetControls returns one Control
+// per value index, so a 20-value level meter becomes a single group of 20.
+func GroupElements(controls []*Control) [][]*Control {
+	var groups [][]*Control
+	position := make(map[uint]int)
+
+	for _, ctl := range controls {
+		i, ok := position[ctl.NumID]
+		if !ok {
+			i = len(groups)
+			position[ctl.NumID] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], ctl)
+	}
+
+	return groups
+}
+
 // DetailedString returns a detailed string representation including current value
 func (ctl *Control) DetailedString() string {
 	value, err := ctl.GetValueString()
@@ -236,5 +921,8 @@ func (ctl *Control) DetailedString() string {
 		value = fmt.Sprintf("Error: %v", err)
 	}
 
+	if unit := ctl.Unit(); unit != "" {
+		return fmt.Sprintf("%s unit: %s = %s", ctl.String(), unit, value)
+	}
 	return fmt.Sprintf("%s = %s", ctl.String(), value)
 }
@@ -0,0 +1,240 @@
+package scarlettctl
+
+import (
+	"encoding/binary"
+	"errors"
+	"testing"
+)
+
+func TestFormatValue(t *testing.T) {
+	enum := &Control{Type: ControlTypeEnumerated, Items: []string{"Off", "Analogue 1", "PCM 1"}, Max: 2}
+	boolean := &Control{Type: ControlTypeBoolean, Max: 1}
+	integer := &Control{Type: ControlTypeInteger, Min: 0, Max: 127}
+
+	tests := []struct {
+		ctl   *Control
+		value int64
+		want  string
+	}{
+		{enum, 0, "Off"},
+		{enum, 2, "PCM 1"},
+		{enum, 3, "Unknown(3)"}, // one past the items
+		{enum, 42, "Unknown(42)"},
+		{enum, -1, "Unknown(-1)"},
+		{boolean, 0, "Off"},
+		{boolean, 1, "On"},
+		{integer, 64, "64"},
+		{integer, 200, "200"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.ctl.FormatValue(tt.value); got != tt.want {
+			t.Errorf("FormatValue(%d) on %v = %q, want %q", tt.value, tt.ctl.Type, got, tt.want)
+		}
+	}
+}
+
+func TestIsValueValid(t *testing.T) {
+	card, dev := newFakeCard(t,
+		fakeElement{numid: 1, name: "Analogue Output 01 Playback Enum", typ: ControlTypeEnumerated, max: 2, items: []string{"Off", "Analogue 1", "PCM 1"}},
+	)
+	ctl, err := card.FindControl("Analogue Output 01 Playback Enum")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if valid, err := ctl.IsValueValid(); err != nil || !valid {
+		t.Errorf("IsValueValid() = %v, %v for an item, want true", valid, err)
+	}
+
+	// firmware reporting a value beyond the known items
+	dev.values[1][0] = 5
+	if valid, err := ctl.IsValueValid(); err != nil || valid {
+		t.Errorf("IsValueValid() = %v, %v beyond the items, want false", valid, err)
+	}
+}
+
+func TestAmbiguousAcrossInterfaces(t *testing.T) {
+	card, _ := newFakeCard(t,
+		fakeElement{numid: 1, name: "Level Meter", iface: InterfaceMixer, typ: ControlTypeInteger, count: 2, max: 4095},
+		fakeElement{numid: 2, name: "Level Meter", iface: InterfacePCM, typ: ControlTypeInteger, max: 4095},
+		fakeElement{numid: 3, name: "Line 01 (Monitor L) Playback Volume", typ: ControlTypeInteger, max: 127},
+	)
+
+	controls, err := card.GetControls()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, ctl := range controls {
+		if want := ctl.Name == "Level Meter"; ctl.IsAmbiguous() != want {
+			t.Errorf("%s: IsAmbiguous() = %v, want %v", ctl.FullID(), ctl.IsAmbiguous(), want)
+		}
+	}
+
+	if _, err := card.FindControl("Level Meter"); !errors.Is(err, ErrAmbiguous) {
+		t.Errorf("FindControl by the shared name: expected ErrAmbiguous, got %v", err)
+	}
+	ctl, err := card.FindControl("pcm:0.0/Level Meter[0]")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ctl.NumID != 2 {
+		t.Errorf("FindControl by full ID = numid %d, want 2", ctl.NumID)
+	}
+}
+
+func TestSetValueByStringRejectsTrailingText(t *testing.T) {
+	card, dev := newFakeCard(t,
+		fakeElement{numid: 1, name: "Line 01 (Monitor L) Playback Volume", typ: ControlTypeInteger, max: 127},
+		fakeElement{numid: 2, name: "Analogue Output 01 Playback Enum", typ: ControlTypeEnumerated, max: 2, items: []string{"Off", "Analogue 1", "PCM 1"}},
+	)
+
+	for _, tt := range []struct{ name, value string }{
+		{"Line 01 (Monitor L) Playback Volume", "12abc"},
+		{"Line 01 (Monitor L) Playback Volume", "1e2"},
+		{"Analogue Output 01 Playback Enum", "2nd"},
+	} {
+		ctl, err := card.FindControl(tt.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := ctl.SetValueByString(tt.value); err == nil {
+			t.Errorf("SetValueByString(%q) on %s succeeded, want an error", tt.value, tt.name)
+		}
+	}
+	if _, writes := dev.counts(); writes != 0 {
+		t.Errorf("made %d writes, want none", writes)
+	}
+}
+
+func TestSetValueVerifiedReadsOnce(t *testing.T) {
+	for _, verify := range []bool{false, true} {
+		card, dev := newFakeCard(t,
+			fakeElement{numid: 1, name: "Line In 1 Gain Capture Volume", typ: ControlTypeInteger, max: 70},
+		)
+		card.SetVerify(verify)
+
+		ctl, err := card.FindControl("Line In 1 Gain Capture Volume")
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, exact := range []bool{false, true} {
+			readsBefore, _ := dev.counts()
+			if err := ctl.SetValueVerified(20, exact); err != nil {
+				t.Fatal(err)
+			}
+			if reads, _ := dev.counts(); reads != readsBefore+1 {
+				t.Errorf("verify mode %v, exact %v: read back %d times, want once", verify, exact, reads-readsBefore)
+			}
+		}
+	}
+}
+
+// dbScaleTLV encodes a dB scale TLV starting at min with the given step,
+// both in hundredths of a dB
+func dbScaleTLV(min int32, step uint32) []byte {
+	raw := make([]byte, 16)
+	for i, w := range []uint32{TLVTypeDBScale, 8, uint32(min), step} {
+		binary.NativeEndian.PutUint32(raw[i*4:], w)
+	}
+	return raw
+}
+
+func TestUnit(t *testing.T) {
+	card, dev := newFakeCard(t,
+		fakeElement{numid: 1, name: "Line 01 (Monitor L) Playback Volume", typ: ControlTypeInteger, max: 127, tlv: dbScaleTLV(-12700, 100)},
+		fakeElement{numid: 2, name: "Mix A Input 01 Playback Volume", typ: ControlTypeInteger, max: 172},
+		fakeElement{numid: 3, name: "Direct Monitor Playback Switch", typ: ControlTypeInteger, max: 1},
+		fakeElement{numid: 4, name: "Line 01 Mute Playback Switch", typ: ControlTypeBoolean, max: 1},
+		fakeElement{numid: 5, name: "Analogue Output 01 Playback Enum", typ: ControlTypeEnumerated, max: 1, items: []string{"Off", "PCM 1"}},
+	)
+
+	want := map[uint]string{1: "dB", 2: "", 3: "bool", 4: "bool", 5: "item"}
+	controls, err := card.GetControls()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		for _, ctl := range controls {
+			if got := ctl.Unit(); got != want[ctl.NumID] {
+				t.Errorf("%s: Unit() = %q, want %q", ctl.Name, got, want[ctl.NumID])
+			}
+		}
+	}
+
+	// only the two integer controls with more than two values have a TLV to read
+	if dev.tlvReads != 2 {
+		t.Errorf("read TLV data %d times, want once for each of 2 elements", dev.tlvReads)
+	}
+}
+
+func TestReadAllValuesReadsEachElementOnce(t *testing.T) {
+	card, dev := newFakeCard(t,
+		fakeElement{numid: 1, name: "Level Meter", typ: ControlTypeInteger, count: 8, max: 4095, values: []int64{1, 2, 3, 4, 5, 6, 7, 8}},
+		fakeElement{numid: 2, name: "Line In 1-2 Phantom Power Capture Switch", typ: ControlTypeBoolean, count: 2, max: 1, values: []int64{0, 1}},
+		fakeElement{numid: 3, name: "Line 01 (Monitor L) Playback Volume", typ: ControlTypeInteger, max: 127, values: []int64{100}},
+	)
+
+	values, err := card.ReadAllValues()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reads, _ := dev.counts(); reads != 3 {
+		t.Errorf("made %d hardware reads, want one for each of 3 elements", reads)
+	}
+
+	if len(values) != 11 {
+		t.Errorf("got %d values, want 11", len(values))
+	}
+	for index := 0; index < 8; index++ {
+		if got := values[ControlKey{NumID: 1, Index: index}]; got != int64(index+1) {
+			t.Errorf("meter %d = %d, want %d", index, got, index+1)
+		}
+	}
+	if got := values[ControlKey{NumID: 2, Index: 1}]; got != 1 {
+		t.Errorf("phantom 2 = %d, want 1", got)
+	}
+	if got := values[ControlKey{NumID: 3}]; got != 100 {
+		t.Errorf("volume = %d, want 100", got)
+	}
+}
+
+// benchmarkCard opens the first Scarlett card, skipping when none is attached
+func benchmarkCard(b *testing.B) *Card {
+	cards, err := ListCards()
+	if err != nil || len(cards) == 0 {
+		b.Skip("no Scarlett card attached")
+	}
+	card, err := OpenCardWithoutEvents(cards[0].Number)
+	if err != nil {
+		b.Skipf("opening card %d: %v", cards[0].Number, err)
+	}
+	b.Cleanup(func() { card.Close() })
+	return card
+}
+
+func BenchmarkReadAllValues(b *testing.B) {
+	card := benchmarkCard(b)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := card.ReadAllValues(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkGetValueEach(b *testing.B) {
+	card := benchmarkCard(b)
+	controls, err := card.GetControls()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, ctl := range controls {
+			ctl.GetValue()
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package scarlettctl
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Digital I/O kinds accepted by SetDigitalIOEnabled
+const (
+	DigitalSPDIF = "spdif"
+	DigitalADAT  = "adat"
+)
+
+// digitalModeRe matches the control that selects which digital I/O is
+// available on interfaces that share ports between S/PDIF and ADAT (e.g.,
+// "S/PDIF Mode Capture Enum", items such as "S/PDIF RCA" or "Dual ADAT")
+var digitalModeRe = regexp.MustCompile(`^(?:S/PDIF|Digital I/O) Mode (?:Capture |Playback )?Enum$`)
+
+// DigitalIOStatus describes a card's S/PDIF and ADAT ports
+type DigitalIOStatus struct {
+	Mode  string   // selected digital I/O mode, "" without a mode control
+	Modes []string // the modes the card offers
+
+	SPDIFInputs  int
+	SPDIFOutputs int
+	ADATInputs   int
+	ADATOutputs  int
+	// ADATLive is how many of the ADAT inputs carry channels at the current
+	// sample rate; ADAT carries fewer channels at higher rates
+	ADATLive int
+}
+
+// findDigitalMode locates the digital I/O mode control
+func (c *Card) findDigitalMode() (*Control, error) {
+	controls, err := c.GetControls()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ctl := range controls {
+		if ctl.Type == ControlTypeEnumerated && digitalModeRe.MatchString(ctl.Name) {
+			return ctl, nil
+		}
+	}
+
+	return nil, fmt.Errorf("digital I/O mode: %w", ErrNotSupported)
+}
+
+// GetDigitalIOStatus reports the card's digital I/O mode and the S/PDIF and
+// ADAT ports in its routing. Cards without a mode control report an empty
+// Mode; cards without digital ports return an error wrapping ErrNotSupported.
+func (c *Card) GetDigitalIOStatus() (*DigitalIOStatus, error) {
+	status := &DigitalIOStatus{}
+
+	if ctl, err := c.findDigitalMode(); err == nil {
+		if status.Mode, err = ctl.GetValueString(); err != nil {
+			return nil, err
+		}
+		status.Modes = ctl.Items
+	}
+
+	// cards without routing controls have no digital ports to count
+	if sources, err := c.GetRoutingSources(); err == nil {
+		for _, src := range sources {
+			switch src.HardwareType {
+			case "S/PDIF":
+				status.SPDIFInputs++
+			case "ADAT":
+				status.ADATInputs++
+			}
+		}
+	}
+	if sinks, err := c.GetRoutingSinks(); err == nil {
+		for _, sink := range sinks {
+			switch {
+			case strings.HasPrefix(sink.Name, "S/PDIF"):
+				status.SPDIFOutputs++
+			case strings.HasPrefix(sink.Name, "ADAT"):
+				status.ADATOutputs++
+			}
+		}
+	}
+
+	status.ADATLive = status.ADATInputs
+	if rate, err := c.SampleRate(); err == nil {
+		status.ADATLive = status.ADATInputs / adatDivisor(rate)
+	}
+
+	if status.Mode == "" && status.SPDIFInputs+status.SPDIFOutputs+status.ADATInputs+status.ADATOutputs == 0 {
+		return nil, fmt.Errorf("digital I/O: %w", ErrNotSupported)
+	}
+	return status, nil
+}
+
+// SetDigitalIOEnabled enables or disables S/PDIF or ADAT (DigitalSPDIF or
+// DigitalADAT) through the card's digital I/O mode. Enabling selects the first
+// mode naming the kind; disabling selects the first mode that doesn't. Cards
+// without a mode control return an error wrapping ErrNotSupported.
+func (c *Card) SetDigitalIOEnabled(kind string, enabled bool) error {
+	kind = strings.ToLower(kind)
+	if kind != DigitalSPDIF && kind != DigitalADAT {
+		return fmt.Errorf("invalid digital I/O kind '%s' (valid: %s, %s)", kind, DigitalSPDIF, DigitalADAT)
+	}
+
+	ctl, err := c.findDigitalMode()
+	if err != nil {
+		return err
+	}
+
+	for i, item := range ctl.Items {
+		if digitalModeEnables(item, kind) == enabled {
+			return ctl.SetValue(int64(i))
+		}
+	}
+
+	state := "enables"
+	if !enabled {
+		state = "disables"
+	}
+	return fmt.Errorf("%s has no mode that %s %s (modes: %s)", ctl.Name, state, kind, strings.Join(ctl.Items, ", "))
+}
+
+// digitalModeEnables reports whether a digital I/O mode item enables a kind
+func digitalModeEnables(item, kind string) bool {
+	item = strings.ToLower(item)
+	if kind == DigitalADAT {
+		return strings.Contains(item, "adat")
+	}
+	for _, word := range []string{"s/pdif", "spdif", "coax", "rca"} {
+		if strings.Contains(item, word) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,71 @@
+package scarlettctl
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// directMonitorRe matches the direct monitor control: an enum with modes on most
+// models (e.g., "Direct Monitor Playback Enum"), a plain switch on others (e.g.,
+// "Direct Monitor Playback Switch")
+var directMonitorRe = regexp.MustCompile(`^Direct Monitor (?:Playback )?(Enum|Switch)$`)
+
+// findDirectMonitor locates the direct monitor control
+func (c *Card) findDirectMonitor() (*Control, error) {
+	controls, err := c.GetControls()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ctl := range controls {
+		m := directMonitorRe.FindStringSubmatch(ctl.Name)
+		if m == nil {
+			continue
+		}
+		if (m[1] == "Enum" && ctl.Type == ControlTypeEnumerated) || (m[1] == "Switch" && ctl.Type == ControlTypeBoolean) {
+			return ctl, nil
+		}
+	}
+
+	return nil, fmt.Errorf("direct monitor: %w", ErrNotSupported)
+}
+
+// GetDirectMonitor returns the current direct monitor mode (e.g., "Off", "Mono",
+// "Stereo", or "Off"/"On" on models with a switch)
+func (c *Card) GetDirectMonitor() (string, error) {
+	ctl, err := c.findDirectMonitor()
+	if err != nil {
+		return "", err
+	}
+
+	return ctl.GetValueString()
+}
+
+// SetDirectMonitor sets the direct monitor mode by name (case-insensitive). On
+// models with a switch the modes are off and on; mono is accepted for on, as
+// such a switch monitors the inputs in mono.
+func (c *Card) SetDirectMonitor(mode string) error {
+	ctl, err := c.findDirectMonitor()
+	if err != nil {
+		return err
+	}
+
+	if ctl.Type == ControlTypeBoolean {
+		switch strings.ToLower(mode) {
+		case "off":
+			return ctl.SetValue(0)
+		case "on", "mono":
+			return ctl.SetValue(1)
+		}
+		return fmt.Errorf("invalid direct monitor mode '%s' (valid: Off, On)", mode)
+	}
+
+	for i, item := range ctl.Items {
+		if strings.EqualFold(item, mode) {
+			return ctl.SetValue(int64(i))
+		}
+	}
+
+	return fmt.Errorf("invalid direct monitor mode '%s' (valid: %s)", mode, strings.Join(ctl.Items, ", "))
+}
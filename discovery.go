@@ -0,0 +1,244 @@
+package scarlettctl
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// discoveryCache is the control metadata of a card saved between runs, with
+// what identifies the enumeration it came from
+type discoveryCache struct {
+	CardID        string          `json:"card_id"`
+	Name          string          `json:"name"`
+	Driver        string          `json:"driver,omitempty"`
+	Firmware      string          `json:"firmware,omitempty"`
+	FirmwareNumID uint            `json:"firmware_numid,omitempty"`
+	Elements      int             `json:"elements"`
+	Controls      []cachedElement `json:"controls"`
+}
+
+// cachedElement is the metadata of one control element. Values aren't cached,
+// and neither is write access, which the driver can toggle at runtime (e.g.
+// output volumes switching between software and front-panel control).
+type cachedElement struct {
+	NumID     uint          `json:"numid"`
+	Name      string        `json:"name"`
+	Type      ControlType   `json:"type"`
+	Count     int           `json:"count"`
+	Interface InterfaceType `json:"iface"`
+	Device    uint          `json:"device,omitempty"`
+	Subdevice uint          `json:"subdevice,omitempty"`
+	Min       int64         `json:"min,omitempty"`
+	Max       int64         `json:"max,omitempty"`
+	Step      int64         `json:"step,omitempty"`
+	Items     []string      `json:"items,omitempty"`
+}
+
+// DefaultDiscoveryCacheDir returns the default discovery cache location (~/.cache/scarlettctl)
+func DefaultDiscoveryCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "scarlettctl"), nil
+}
+
+// EnableDiscoveryCache makes the card keep its control metadata (names, types,
+// ranges and items, not values) in dir, one file per card id, so later runs
+// can resolve controls without a full enumeration; only each element's write
+// access, which can change at runtime, is queried. The cache is checked
+// against the card's element count, driver and firmware version, and rebuilt
+// when they change. An empty dir disables it.
+func (c *Card) EnableDiscoveryCache(dir string) {
+	c.discoveryMu.Lock()
+	defer c.discoveryMu.Unlock()
+
+	c.discoveryDir = dir
+	c.discovered = nil
+}
+
+// discoveryPath returns the card's cache file, or "" when caching is disabled
+func (c *Card) discoveryPath() string {
+	c.discoveryMu.Lock()
+	defer c.discoveryMu.Unlock()
+
+	if c.discoveryDir == "" {
+		return ""
+	}
+	return filepath.Join(c.discoveryDir, c.alsaID()+".json")
+}
+
+// cachedControls returns controls built from the cached metadata, once the
+// cache has been found to match the card, with write access read afresh
+func (c *Card) cachedControls() ([]*Control, bool) {
+	path := c.discoveryPath()
+	if path == "" {
+		return nil, false
+	}
+
+	c.discoveryMu.Lock()
+	elements := c.discovered
+	c.discoveryMu.Unlock()
+
+	if elements == nil {
+		cache, ok := c.loadDiscovery(path)
+		if !ok {
+			return nil, false
+		}
+		elements = cache.Controls
+
+		c.discoveryMu.Lock()
+		c.discovered = elements
+		c.discoveryMu.Unlock()
+	}
+
+	numids := make([]uint, len(elements))
+	for i, el := range elements {
+		numids[i] = el.NumID
+	}
+	var writable []bool
+	err := c.call(func(h *alsaHandle) (err error) {
+		writable, err = hardware.writable(h, numids)
+		return err
+	})
+	if err != nil {
+		logger.Debug("discovery cache out of date", "path", path, "error", err)
+		return nil, false
+	}
+
+	var controls []*Control
+	for i, el := range elements {
+		for idx := 0; idx < el.Count; idx++ {
+			controls = append(controls, &Control{
+				NumID:     el.NumID,
+				Name:      el.Name,
+				Type:      el.Type,
+				Count:     el.Count,
+				Index:     idx,
+				card:      c,
+				Interface: el.Interface,
+				Device:    el.Device,
+				Subdevice: el.Subdevice,
+				Writable:  writable[i],
+				Min:       el.Min,
+				Max:       el.Max,
+				Step:      el.Step,
+				Items:     el.Items,
+			})
+		}
+	}
+	markAmbiguous(controls)
+	logger.Debug("loaded cached controls", "card", c.Name, "count", len(controls), "path", path)
+
+	return controls, true
+}
+
+// loadDiscovery reads the cache file and checks it still describes the card
+func (c *Card) loadDiscovery(path string) (*discoveryCache, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+
+	cache := &discoveryCache{}
+	if err := json.Unmarshal(data, cache); err != nil {
+		logger.Debug("ignoring unreadable discovery cache", "path", path, "error", err)
+		return nil, false
+	}
+
+	driver, _ := DriverVersion()
+	if cache.CardID != c.alsaID() || cache.Name != c.Name || cache.Driver != driver {
+		logger.Debug("discovery cache is for another card or driver", "path", path)
+		return nil, false
+	}
+
+	var elements int
+	err = c.call(func(h *alsaHandle) (err error) {
+		elements, err = hardware.count(h)
+		return err
+	})
+	if err != nil || elements != cache.Elements {
+		logger.Debug("discovery cache element count changed", "path", path, "cached", cache.Elements, "current", elements)
+		return nil, false
+	}
+
+	if cache.FirmwareNumID != 0 {
+		if firmware, err := c.readFirmware(cache.FirmwareNumID); err != nil || firmware != cache.Firmware {
+			logger.Debug("discovery cache firmware changed", "path", path, "cached", cache.Firmware, "current", firmware)
+			return nil, false
+		}
+	}
+
+	return cache, true
+}
+
+// storeDiscovery saves freshly enumerated controls to the cache file, if
+// caching is enabled. Failures only cost the next run an enumeration, so they
+// are logged rather than returned.
+func (c *Card) storeDiscovery(controls []*Control) {
+	path := c.discoveryPath()
+	if path == "" {
+		return
+	}
+
+	driver, _ := DriverVersion()
+	cache := &discoveryCache{CardID: c.alsaID(), Name: c.Name, Driver: driver}
+	for _, ctl := range controls {
+		if ctl.Index != 0 {
+			continue
+		}
+		cache.Elements++
+		cache.Controls = append(cache.Controls, cachedElement{
+			NumID:     ctl.NumID,
+			Name:      ctl.Name,
+			Type:      ctl.Type,
+			Count:     ctl.Count,
+			Interface: ctl.Interface,
+			Device:    ctl.Device,
+			Subdevice: ctl.Subdevice,
+			Min:       ctl.Min,
+			Max:       ctl.Max,
+			Step:      ctl.Step,
+			Items:     ctl.Items,
+		})
+		if ctl.Type == ControlTypeInteger && firmwareVersionRe.MatchString(ctl.Name) {
+			if firmware, err := c.readFirmware(ctl.NumID); err == nil {
+				cache.Firmware, cache.FirmwareNumID = firmware, ctl.NumID
+			}
+		}
+	}
+
+	data, err := json.Marshal(cache)
+	if err == nil {
+		if err = os.MkdirAll(filepath.Dir(path), 0755); err == nil {
+			err = os.WriteFile(path, data, 0644)
+		}
+	}
+	if err != nil {
+		logger.Debug("failed to write discovery cache", "path", path, "error", err)
+		return
+	}
+
+	c.discoveryMu.Lock()
+	c.discovered = cache.Controls
+	c.discoveryMu.Unlock()
+}
+
+// readFirmware reads the firmware version control by numid
+func (c *Card) readFirmware(numid uint) (string, error) {
+	var values []int64
+	err := c.call(func(h *alsaHandle) (err error) {
+		values, err = hardware.readElement(h, numid, ControlTypeInteger, 1)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(values) == 0 {
+		return "", fmt.Errorf("firmware version control has no value")
+	}
+	return strconv.FormatInt(values[0], 10), nil
+}
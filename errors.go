@@ -0,0 +1,57 @@
+package scarlettctl
+
+import (
+	"errors"
+	"fmt"
+
+	"golang.org/x/sys/unix"
+)
+
+// ErrAmbiguous is returned when a name matches more than one control element
+var ErrAmbiguous = errors.New("ambiguous")
+
+// ErrNotSupported is returned when a feature's controls don't exist on the connected device
+var ErrNotSupported = errors.New("not supported on this device")
+
+// ErrVerifyFailed is returned when a control reads back a different value than was written
+var ErrVerifyFailed = errors.New("write not applied")
+
+// ControlError records an element that enumeration couldn't query
+type ControlError struct {
+	NumID uint
+	Name  string
+	Err   error
+}
+
+func (e ControlError) Error() string {
+	return fmt.Sprintf("numid %d (%s): %v", e.NumID, e.Name, e.Err)
+}
+
+func (e ControlError) Unwrap() error {
+	return e.Err
+}
+
+// ErrClosed is returned when using a card, or one of its controls, after Close
+var ErrClosed = errors.New("card is closed")
+
+// ErrLocked is returned when writing to a card whose writes are locked
+var ErrLocked = errors.New("card is locked")
+
+// ErrAboveGainCap is returned when setting a preamp gain above the channel's
+// cap (see SetGainCap)
+var ErrAboveGainCap = errors.New("above the gain cap")
+
+// ErrDeviceDisconnected is returned when the device goes away, e.g. is
+// unplugged, while it is being read or written
+var ErrDeviceDisconnected = errors.New("device disconnected")
+
+// alsaSentinel maps a negative ALSA error code to the sentinel error callers
+// can test for with errors.Is, or nil when the code has no sentinel
+func alsaSentinel(code int) error {
+	switch unix.Errno(-code) {
+	case unix.ENODEV, unix.EPIPE:
+		return ErrDeviceDisconnected
+	default:
+		return nil
+	}
+}
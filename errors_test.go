@@ -0,0 +1,28 @@
+package scarlettctl
+
+import (
+	"testing"
+
+	"golang.org/x/sys/unix"
+)
+
+func TestAlsaSentinel(t *testing.T) {
+	tests := []struct {
+		code int
+		want error
+	}{
+		{-int(unix.ENODEV), ErrDeviceDisconnected},
+		{-int(unix.EPIPE), ErrDeviceDisconnected},
+		{-int(unix.EBUSY), nil},
+		{-int(unix.EINVAL), nil},
+		{-int(unix.EPERM), nil},
+		{-int(unix.EIO), nil},
+		{0, nil},
+	}
+
+	for _, tt := range tests {
+		if got := alsaSentinel(tt.code); got != tt.want {
+			t.Errorf("alsaSentinel(%d) = %v, want %v", tt.code, got, tt.want)
+		}
+	}
+}
@@ -1,40 +1,94 @@
 package scarlettctl
 
 import (
+	"errors"
 	"fmt"
+	"slices"
+	"sync"
 	"time"
 
 	"golang.org/x/sys/unix"
 )
 
+// default event monitor timing
+const (
+	DefaultPollTimeout    = time.Second
+	DefaultCoalesceWindow = 50 * time.Millisecond
+	DefaultPollInterval   = 500 * time.Millisecond
+)
+
 // EventMonitor monitors ALSA control events
 type EventMonitor struct {
-	card     *Card
-	running  bool
-	stopChan chan struct{}
+	card           *Card
+	stopChan       chan struct{} // closed by Stop
+	stopOnce       sync.Once
+	pollTimeout    time.Duration
+	coalesceWindow time.Duration
+	pollInterval   time.Duration
+	filter         func(*Control) bool // controls WatchControls reports, nil for all
 }
 
 // NewEventMonitor creates a new event monitor for the card
 func (c *Card) NewEventMonitor() *EventMonitor {
 	return &EventMonitor{
-		card:     c,
-		stopChan: make(chan struct{}),
+		card:           c,
+		stopChan:       make(chan struct{}),
+		pollTimeout:    DefaultPollTimeout,
+		coalesceWindow: DefaultCoalesceWindow,
+		pollInterval:   DefaultPollInterval,
+	}
+}
+
+// SetPollTimeout sets how long each poll waits for events before checking whether
+// the monitor was stopped. Shorter timeouts make Stop take effect sooner at the
+// cost of more wakeups while idle.
+func (em *EventMonitor) SetPollTimeout(d time.Duration) {
+	if d < time.Millisecond {
+		d = time.Millisecond
+	}
+	em.pollTimeout = d
+}
+
+// SetCoalesceWindow sets how long to keep absorbing events after the first one in
+// a burst before invoking the callback once. A longer window means fewer re-reads
+// while, say, a knob is being turned, but delays each report by that much; zero
+// reports each batch of pending events immediately.
+func (em *EventMonitor) SetCoalesceWindow(d time.Duration) {
+	if d < 0 {
+		d = 0
+	}
+	em.coalesceWindow = d
+}
+
+// SetPollInterval sets how often control values are re-read when the card
+// provides no poll descriptors and so can't deliver change events
+func (em *EventMonitor) SetPollInterval(d time.Duration) {
+	if d < time.Millisecond {
+		d = time.Millisecond
 	}
+	em.pollInterval = d
+}
+
+// SetFilter limits WatchControls, and so WatchWithDisplay, to the controls for
+// which filter returns true; nil watches every control
+func (em *EventMonitor) SetFilter(filter func(*Control) bool) {
+	em.filter = filter
 }
 
 // Watch starts monitoring for control changes and calls the callback for each change
-// The callback receives the numid of the changed control
+// The callback receives the numid of the changed element. Events within the
+// coalesce window are gathered, so a burst results in a single callback per
+// element that changed. If the card provides no poll descriptors, values are
+// re-read at the poll interval instead and the callback is invoked for each
+// element whose values changed. Watch returns nil once the card is closed.
 func (em *EventMonitor) Watch(callback func(numid uint) error) error {
-	if em.card.handle == nil {
-		return fmt.Errorf("card not open")
+	if err := em.card.checkOpen(); err != nil {
+		return err
 	}
 
-	em.running = true
-	defer func() { em.running = false }()
-
 	pollFds := em.card.GetPollFds()
 	if len(pollFds) == 0 {
-		return fmt.Errorf("no poll descriptors available")
+		return em.watchPolling(callback)
 	}
 
 	// build pollfd array for unix.Poll
@@ -46,7 +100,7 @@ func (em *EventMonitor) Watch(callback func(numid uint) error) error {
 		}
 	}
 
-	for em.running {
+	for {
 		// check if we should stop
 		select {
 		case <-em.stopChan:
@@ -55,7 +109,7 @@ func (em *EventMonitor) Watch(callback func(numid uint) error) error {
 		}
 
 		// poll with timeout
-		n, err := unix.Poll(fds, 1000) // 1 second timeout
+		n, err := unix.Poll(fds, int(em.pollTimeout/time.Millisecond))
 		if err != nil {
 			if err == unix.EINTR {
 				continue
@@ -69,50 +123,181 @@ func (em *EventMonitor) Watch(callback func(numid uint) error) error {
 		}
 
 		// check for events
-		for {
-			hasEvent, err := checkEvent(em.card.handle)
+		changed, err := em.drainEvents(fds, nil)
+		if errors.Is(err, ErrClosed) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if len(changed) == 0 {
+			continue
+		}
+
+		// absorb the rest of a burst so each element is reported once
+		if em.coalesceWindow > 0 {
+			time.Sleep(em.coalesceWindow)
+			changed, err = em.drainEvents(fds, changed)
+			if errors.Is(err, ErrClosed) {
+				return nil
+			}
 			if err != nil {
-				return fmt.Errorf("check event failed: %v", err)
+				return err
 			}
+		}
 
-			if !hasEvent {
-				break
-			}
+		// cached values may be stale now
+		em.card.InvalidateCache()
 
-			// we detected an event but don't have the numid from checkEvent
-			// for simplicity, we'll call the callback with 0
-			// in a production implementation, you'd extract the numid from the event
-			if callback != nil {
-				if err := callback(0); err != nil {
-					return err
-				}
-			}
+		if err := notify(callback, changed); err != nil {
+			return err
 		}
 	}
+}
 
+// notify calls back once for each changed element
+func notify(callback func(numid uint) error, numids []uint) error {
+	if callback == nil {
+		return nil
+	}
+	for _, numid := range numids {
+		if err := callback(numid); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// addNumID appends a numid to a list of changed elements unless already there
+func addNumID(numids []uint, numid uint) []uint {
+	if slices.Contains(numids, numid) {
+		return numids
+	}
+	return append(numids, numid)
+}
+
+// watchPolling re-reads every control at the poll interval, for cards that
+// can't deliver events, calling back for each element whose values changed
+func (em *EventMonitor) watchPolling(callback func(numid uint) error) error {
+	controls, err := em.card.GetControls()
+	if err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(em.pollInterval)
+	defer ticker.Stop()
+
+	last := em.card.readValues(controls)
+	for {
+		select {
+		case <-em.stopChan:
+			return nil
+		case <-ticker.C:
+		}
+
+		values := em.card.readValues(controls)
+		changed := changedElements(controls, last, values)
+		last = values
+
+		if err := notify(callback, changed); err != nil {
+			return err
+		}
+	}
+}
+
+// changedElements returns the numids of the elements whose values differ
+// between two reads, in control order
+func changedElements(controls []*Control, a, b map[ControlKey]int64) []uint {
+	var numids []uint
+	for _, ctl := range controls {
+		old, hadOld := a[ctl.Key()]
+		value, hasValue := b[ctl.Key()]
+		if hadOld != hasValue || old != value {
+			numids = addNumID(numids, ctl.NumID)
+		}
+	}
+	return numids
+}
+
+// drainEvents reads every pending event, adding the numid of each element
+// reported changed to changed
+func (em *EventMonitor) drainEvents(fds []unix.PollFd, changed []uint) ([]uint, error) {
+	for {
+		// only read while an event is pending, so the read never blocks
+		n, err := unix.Poll(fds, 0)
+		if err != nil {
+			if err == unix.EINTR {
+				continue
+			}
+			return changed, fmt.Errorf("poll failed: %v", err)
+		}
+		if n == 0 {
+			return changed, nil
+		}
+
+		// a card closed under the monitor invalidates its descriptors too, so
+		// check for that before treating a hang-up as an unplug
+		h, err := em.card.openHandle()
+		if err != nil {
+			return changed, err
+		}
+
+		// the kernel hangs up the control device when the card is unplugged
+		for _, fd := range fds {
+			if fd.Revents&(unix.POLLHUP|unix.POLLERR|unix.POLLNVAL) != 0 {
+				return changed, fmt.Errorf("poll: %w", ErrDeviceDisconnected)
+			}
+		}
+
+		// checkEvent fails with ErrClosed if the card closes after openHandle
+		numid, ok, err := checkEvent(h)
+		if err != nil {
+			return changed, fmt.Errorf("check event failed: %w", err)
+		}
+		if ok {
+			changed = addNumID(changed, numid)
+		}
+	}
+}
+
 // WatchControls monitors specific controls and calls the callback with control details
+// Only controls passing the monitor's filter, if set, are reported.
 func (em *EventMonitor) WatchControls(callback func(control *Control, value int64) error) error {
 	// get all controls once at the start
 	controls, err := em.card.GetControls()
 	if err != nil {
 		return err
 	}
+	if em.filter != nil {
+		var filtered []*Control
+		for _, ctl := range controls {
+			if em.filter(ctl) {
+				filtered = append(filtered, ctl)
+			}
+		}
+		controls = filtered
+	}
 
-	// build a map of numid -> control for quick lookup
-	controlMap := make(map[uint]*Control)
+	// the values of a multi-value element share its numid
+	byNumID := make(map[uint][]*Control)
 	for _, ctl := range controls {
-		controlMap[ctl.NumID] = ctl
+		byNumID[ctl.NumID] = append(byNumID[ctl.NumID], ctl)
 	}
 
 	return em.Watch(func(numid uint) error {
-		// if numid is 0, check all controls (since we simplified the event handling)
-		// in practice, you'd check only the changed control
-		for _, ctl := range controls {
-			value, err := ctl.GetValue()
-			if err != nil {
+		// only the changed element is re-read; numid 0 re-reads everything
+		changed := controls
+		if numid != 0 {
+			changed = byNumID[numid]
+		}
+		if len(changed) == 0 {
+			return nil // not watched
+		}
+
+		values := em.card.readValues(changed)
+		for _, ctl := range changed {
+			value, ok := values[ctl.Key()]
+			if !ok {
 				continue // skip controls we can't read
 			}
 
@@ -126,21 +311,28 @@ func (em *EventMonitor) WatchControls(callback func(control *Control, value int6
 	})
 }
 
-// Stop stops the event monitor
+// Stop stops the event monitor. It is safe to call more than once and from
+// any goroutine; a running Watch returns within the poll timeout.
 func (em *EventMonitor) Stop() {
-	em.running = false
-	close(em.stopChan)
+	em.stopOnce.Do(func() {
+		close(em.stopChan)
+	})
 }
 
 // WatchWithDisplay monitors controls and displays changes in a human-readable format
 func (c *Card) WatchWithDisplay() error {
-	monitor := c.NewEventMonitor()
+	return c.NewEventMonitor().WatchWithDisplay()
+}
 
-	lastUpdate := make(map[uint]int64)
+// WatchWithDisplay monitors controls and displays changes in a human-readable
+// format, using the monitor's timing settings
+func (em *EventMonitor) WatchWithDisplay() error {
+	// multi-value controls share a numid, so track each index separately
+	lastUpdate := make(map[ControlKey]int64)
 
-	return monitor.WatchControls(func(control *Control, value int64) error {
+	return em.WatchControls(func(control *Control, value int64) error {
 		// only print if value changed
-		key := control.NumID
+		key := control.Key()
 		if lastValue, exists := lastUpdate[key]; exists && lastValue == value {
 			return nil
 		}
@@ -151,7 +343,12 @@ func (c *Card) WatchWithDisplay() error {
 		timestamp := time.Now().Format("15:04:05")
 		valueStr, _ := control.GetValueString()
 
-		fmt.Printf("[%s] %-50s = %s\n", timestamp, control.Name, valueStr)
+		name := control.Name
+		if control.Count > 1 {
+			name = fmt.Sprintf("%s[%d]", control.Name, control.Index)
+		}
+
+		fmt.Printf("[%s] %-50s = %s\n", timestamp, name, valueStr)
 
 		return nil
 	})
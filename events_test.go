@@ -0,0 +1,85 @@
+package scarlettctl
+
+import (
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestStopConcurrently(t *testing.T) {
+	// an idle pipe stands in for the card's poll descriptor
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	defer w.Close()
+
+	card := &Card{handle: &alsaHandle{pollFds: []int{int(r.Fd())}}}
+	monitor := card.NewEventMonitor()
+	monitor.SetPollTimeout(5 * time.Millisecond)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- monitor.Watch(nil)
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			monitor.Stop()
+		}()
+	}
+	wg.Wait()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("watch failed: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("watch didn't return after Stop")
+	}
+
+	// stopping a stopped monitor is harmless
+	monitor.Stop()
+}
+
+func TestWatchReturnsOnClose(t *testing.T) {
+	// a pipe with unread data keeps an event pending on every poll
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	defer w.Close()
+	if _, err := w.Write([]byte{0}); err != nil {
+		t.Fatal(err)
+	}
+
+	card := &Card{handle: &alsaHandle{ptr: 1, pollFds: []int{int(r.Fd())}}}
+	monitor := card.NewEventMonitor()
+	monitor.SetPollTimeout(5 * time.Millisecond)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- monitor.Watch(nil)
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	if err := card.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Watch after Close returned %v, want nil", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Watch did not return after Close")
+	}
+}
@@ -0,0 +1,128 @@
+package scarlettctl
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ExportScript writes a shell script of scarlettctl commands that reproduce the
+// card's current writable state. The script takes the target card as its first
+// argument, defaulting to this card's name. Gain and phantom power use the
+// dedicated commands; every other writable control is emitted as a 'set' with
+// its human-readable value, grouped by category. Read-only controls and level
+// meters are skipped.
+func (c *Card) ExportScript(w io.Writer) error {
+	controls, err := c.GetControls()
+	if err != nil {
+		return err
+	}
+
+	values, err := c.ReadAllValues()
+	if err != nil {
+		return err
+	}
+
+	channels, err := c.GetPreampChannels()
+	if err != nil {
+		return err
+	}
+
+	// preamp gain and phantom have their own commands
+	special := make(map[ControlKey]bool)
+	var preamp []string
+	for _, ch := range channels {
+		if ch.Gain != nil && ch.Gain.Writable {
+			if value, ok := values[ch.Gain.Key()]; ok {
+				preamp = append(preamp, fmt.Sprintf("scarlettctl gain \"$CARD\" %d %d", ch.ChannelNum, value))
+				special[ch.Gain.Key()] = true
+			}
+		}
+		if ch.Phantom != nil && ch.Phantom.Writable {
+			if value, ok := values[ch.Phantom.Key()]; ok {
+				// --force skips the confirmation prompt when run from a terminal
+				preamp = append(preamp, fmt.Sprintf("scarlettctl phantom --force \"$CARD\" %d %s",
+					ch.ChannelNum, strings.ToLower(ch.Phantom.FormatValue(value))))
+				special[ch.Phantom.Key()] = true
+			}
+		}
+	}
+
+	var routing, mixer, other []string
+	for _, ctl := range controls {
+		if !ctl.Writable || isMeter(ctl) || special[ctl.Key()] {
+			continue
+		}
+		switch ctl.Type {
+		case ControlTypeBoolean, ControlTypeInteger, ControlTypeInteger64, ControlTypeEnumerated:
+		default:
+			continue // no string form to set
+		}
+
+		value, ok := values[ctl.Key()]
+		if !ok {
+			continue
+		}
+
+		line := exportSetCommand(ctl, value)
+		switch GroupOf(ctl) {
+		case GroupRouting:
+			routing = append(routing, line)
+		case GroupMixer:
+			mixer = append(mixer, line)
+		case GroupPreamp:
+			preamp = append(preamp, line)
+		default:
+			other = append(other, line)
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString("#!/bin/sh\n")
+	sb.WriteString(fmt.Sprintf("# scarlettctl settings exported from %s\n", c.Name))
+	sb.WriteString("# usage: sh script.sh [card]\n")
+	sb.WriteString("set -e\n\n")
+	sb.WriteString(fmt.Sprintf("CARD=${1:-%s}\n", shellQuote(c.Name)))
+
+	writeGroup := func(title string, lines []string) {
+		if len(lines) == 0 {
+			return
+		}
+		sb.WriteString(fmt.Sprintf("\n# %s\n", title))
+		for _, line := range lines {
+			sb.WriteString(line + "\n")
+		}
+	}
+	writeGroup("preamp", preamp)
+	writeGroup("routing", routing)
+	writeGroup("mixer", mixer)
+	writeGroup("other", other)
+
+	_, err = io.WriteString(w, sb.String())
+	return err
+}
+
+// exportSetCommand returns the 'set' command restoring a control's value
+// Controls that a name alone doesn't identify are addressed by full ID.
+func exportSetCommand(ctl *Control, value int64) string {
+	target := ctl.Name
+	if ctl.Count > 1 || ctl.ambiguous {
+		target = ctl.FullID()
+	}
+
+	if !ctl.valueInRange(value) {
+		return fmt.Sprintf("# %s: current value %d is out of range, skipped", target, value)
+	}
+
+	text := ctl.FormatValue(value)
+	if ctl.IsBooleanLike() {
+		text = strings.ToLower(text)
+	}
+
+	return fmt.Sprintf("scarlettctl set \"$CARD\" %s %s", shellQuote(target), shellQuote(text))
+}
+
+// shellQuote quotes s for a POSIX shell
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
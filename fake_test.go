@@ -0,0 +1,254 @@
+package scarlettctl
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// fakeElement describes a control element of a fakeDevice
+type fakeElement struct {
+	numid    uint
+	name     string
+	iface    InterfaceType // InterfaceMixer when zero
+	typ      ControlType
+	count    int // values, 1 when zero
+	min, max int64
+	items    []string
+	readOnly bool
+	values   []int64 // initial values, zero when missing
+	tlv      []byte  // raw TLV data, none when nil
+}
+
+// fakeDevice is an in-memory card standing in for ALSA in tests
+type fakeDevice struct {
+	mu       sync.Mutex
+	elements []fakeElement
+	values   map[uint][]int64
+	reads    int // hardware reads, of a value or a whole element
+	writes   int
+	tlvReads int
+
+	readErr error         // returned by every read when set
+	stall   chan struct{} // when set, reads hold the handle until it is closed or sent to
+}
+
+// newFakeCard returns a card backed by a fakeDevice with the given elements,
+// in place of ALSA until the test ends
+func newFakeCard(t testing.TB, elements ...fakeElement) (*Card, *fakeDevice) {
+	dev := &fakeDevice{values: make(map[uint][]int64)}
+	for _, el := range elements {
+		if el.count == 0 {
+			el.count = 1
+		}
+		values := make([]int64, el.count)
+		copy(values, el.values)
+		dev.values[el.numid] = values
+		dev.elements = append(dev.elements, el)
+	}
+
+	saved := hardware
+	t.Cleanup(func() { hardware = saved })
+
+	hardware.enumerate = func(h *alsaHandle) ([]*Control, []ControlError, error) {
+		unlock, err := lockHandle(h)
+		if err != nil {
+			return nil, nil, err
+		}
+		defer unlock()
+		return dev.enumerate(), nil, nil
+	}
+	hardware.count = func(h *alsaHandle) (int, error) {
+		unlock, err := lockHandle(h)
+		if err != nil {
+			return 0, err
+		}
+		defer unlock()
+		return len(dev.elements), nil
+	}
+	hardware.writable = dev.writable
+	hardware.read = dev.read
+	hardware.readElement = dev.readElement
+	hardware.write = dev.write
+	hardware.tlv = dev.readTLV
+	hardware.close = closeFakeHandle
+
+	return &Card{Name: "Fake Scarlett", Device: "hw:99", handle: &alsaHandle{ptr: 1}}, dev
+}
+
+// lockHandle locks a fake card's handle as the cgo calls do, failing with
+// ErrClosed once it has been closed
+func lockHandle(h *alsaHandle) (unlock func(), err error) {
+	h.mu.Lock()
+	if h.ptr == 0 {
+		h.mu.Unlock()
+		return nil, ErrClosed
+	}
+	return h.mu.Unlock, nil
+}
+
+// closeFakeHandle closes a fake card's handle, waiting for any call holding it
+func closeFakeHandle(h *alsaHandle) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.ptr = 0
+	return nil
+}
+
+func (dev *fakeDevice) enumerate() []*Control {
+	var controls []*Control
+	for _, el := range dev.elements {
+		iface := el.iface
+		if iface == 0 {
+			iface = InterfaceMixer
+		}
+		for idx := 0; idx < el.count; idx++ {
+			controls = append(controls, &Control{
+				NumID:     el.numid,
+				Name:      el.name,
+				Type:      el.typ,
+				Count:     el.count,
+				Index:     idx,
+				Interface: iface,
+				Writable:  !el.readOnly,
+				Min:       el.min,
+				Max:       el.max,
+				Items:     el.items,
+			})
+		}
+	}
+	return controls
+}
+
+func (dev *fakeDevice) writable(h *alsaHandle, numids []uint) ([]bool, error) {
+	unlock, err := lockHandle(h)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	writable := make([]bool, len(numids))
+	for i, numid := range numids {
+		el, err := dev.element(numid)
+		if err != nil {
+			return nil, err
+		}
+		writable[i] = !el.readOnly
+	}
+	return writable, nil
+}
+
+func (dev *fakeDevice) element(numid uint) (fakeElement, error) {
+	for _, el := range dev.elements {
+		if el.numid == numid {
+			return el, nil
+		}
+	}
+	return fakeElement{}, fmt.Errorf("no element with numid %d", numid)
+}
+
+func (dev *fakeDevice) read(h *alsaHandle, ctl *Control) (int64, error) {
+	unlock, err := lockHandle(h)
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+	if dev.stall != nil {
+		<-dev.stall // wedged, holding the handle
+	}
+
+	dev.mu.Lock()
+	defer dev.mu.Unlock()
+
+	dev.reads++
+	if dev.readErr != nil {
+		return 0, dev.readErr
+	}
+	values, ok := dev.values[ctl.NumID]
+	if !ok || ctl.Index >= len(values) {
+		return 0, fmt.Errorf("no value %d of numid %d", ctl.Index, ctl.NumID)
+	}
+	return values[ctl.Index], nil
+}
+
+func (dev *fakeDevice) readElement(h *alsaHandle, numid uint, ctlType ControlType, count int) ([]int64, error) {
+	unlock, err := lockHandle(h)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	dev.mu.Lock()
+	defer dev.mu.Unlock()
+
+	dev.reads++
+	if dev.readErr != nil {
+		return nil, dev.readErr
+	}
+	values, ok := dev.values[numid]
+	if !ok {
+		return nil, fmt.Errorf("no element with numid %d", numid)
+	}
+	return append([]int64(nil), values...), nil
+}
+
+func (dev *fakeDevice) write(h *alsaHandle, ctl *Control, value int64) error {
+	unlock, err := lockHandle(h)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	dev.mu.Lock()
+	defer dev.mu.Unlock()
+
+	dev.writes++
+	values, ok := dev.values[ctl.NumID]
+	if !ok || ctl.Index >= len(values) {
+		return fmt.Errorf("no value %d of numid %d", ctl.Index, ctl.NumID)
+	}
+	values[ctl.Index] = value
+	return nil
+}
+
+func (dev *fakeDevice) readTLV(h *alsaHandle, numid uint) ([]byte, error) {
+	unlock, err := lockHandle(h)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	dev.mu.Lock()
+	defer dev.mu.Unlock()
+
+	dev.tlvReads++
+	el, err := dev.element(numid)
+	if err != nil {
+		return nil, err
+	}
+	if el.tlv == nil {
+		return nil, fmt.Errorf("numid %d has no tlv: %w", numid, ErrNotSupported)
+	}
+	return el.tlv, nil
+}
+
+// value returns a value the device holds
+func (dev *fakeDevice) value(numid uint, index int) int64 {
+	dev.mu.Lock()
+	defer dev.mu.Unlock()
+	return dev.values[numid][index]
+}
+
+// set changes a value behind the card's back, as the device itself might
+func (dev *fakeDevice) set(numid uint, index int, value int64) {
+	dev.mu.Lock()
+	defer dev.mu.Unlock()
+	dev.values[numid][index] = value
+}
+
+// counts returns the reads and writes made so far
+func (dev *fakeDevice) counts() (reads, writes int) {
+	dev.mu.Lock()
+	defer dev.mu.Unlock()
+	return dev.reads, dev.writes
+}
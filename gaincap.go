@@ -0,0 +1,41 @@
+package scarlettctl
+
+import "fmt"
+
+// SetGainCap sets the highest gain a channel's gain control accepts, as a
+// guard against accidentally blasting gain. Every write through the card is
+// checked, whether from SetPreampGain, SetValue, a ControlWriter, a preset or
+// a restored snapshot; higher values fail with ErrAboveGainCap. The cap
+// applies to this Card only.
+func (c *Card) SetGainCap(channelNum int, max int64) {
+	if c.gainCaps == nil {
+		c.gainCaps = make(map[int]int64)
+	}
+	c.gainCaps[channelNum] = max
+}
+
+// ClearGainCap removes a channel's gain cap
+func (c *Card) ClearGainCap(channelNum int) {
+	delete(c.gainCaps, channelNum)
+}
+
+// GainCap returns a channel's gain cap, if one is set
+func (c *Card) GainCap(channelNum int) (int64, bool) {
+	max, ok := c.gainCaps[channelNum]
+	return max, ok
+}
+
+// checkGainCap fails a write that would set a preamp gain above its channel's cap
+func (c *Card) checkGainCap(ctl *Control, value int64) error {
+	if len(c.gainCaps) == 0 {
+		return nil
+	}
+	channelNum, ok := preampGainChannel(ctl)
+	if !ok {
+		return nil
+	}
+	if max, ok := c.GainCap(channelNum); ok && value > max {
+		return fmt.Errorf("channel %d gain %d is %w (%d)", channelNum, value, ErrAboveGainCap, max)
+	}
+	return nil
+}
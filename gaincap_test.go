@@ -0,0 +1,33 @@
+package scarlettctl
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGainCapAppliesToEveryWrite(t *testing.T) {
+	card := &Card{handle: &alsaHandle{}}
+	card.SetGainCap(1, 40)
+
+	gain := &Control{Name: "Line In 1 Gain Capture Volume", Type: ControlTypeInteger, Count: 1, Max: 70, card: card}
+	other := &Control{Name: "Line In 2 Gain Capture Volume", Type: ControlTypeInteger, Count: 1, Max: 70, card: card}
+	volume := &Control{Name: "Line 01 Playback Volume", Type: ControlTypeInteger, Count: 1, Max: 127, card: card}
+
+	if err := gain.checkValue(60); !errors.Is(err, ErrAboveGainCap) {
+		t.Fatalf("expected ErrAboveGainCap above the cap, got %v", err)
+	}
+	if err := gain.checkValue(40); err != nil {
+		t.Fatalf("expected the cap itself to be accepted, got %v", err)
+	}
+	if err := other.checkValue(60); err != nil {
+		t.Fatalf("expected an uncapped channel to be accepted, got %v", err)
+	}
+	if err := volume.checkValue(100); err != nil {
+		t.Fatalf("expected a non-gain control to be accepted, got %v", err)
+	}
+
+	card.ClearGainCap(1)
+	if err := gain.checkValue(60); err != nil {
+		t.Fatalf("expected a cleared cap to be lifted, got %v", err)
+	}
+}
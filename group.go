@@ -0,0 +1,63 @@
+package scarlettctl
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ControlGroup is a broad category of controls, used to export or snapshot
+// part of a card's state
+type ControlGroup string
+
+const (
+	GroupPreamp  ControlGroup = "preamp"  // "Line In" gain, phantom, air, pad, ...
+	GroupRouting ControlGroup = "routing" // routing sink selectors
+	GroupMixer   ControlGroup = "mixer"   // mixer input levels
+	GroupOther   ControlGroup = "other"   // everything else
+)
+
+// ControlGroups lists every group in display order
+var ControlGroups = []ControlGroup{GroupPreamp, GroupRouting, GroupMixer, GroupOther}
+
+// GroupOf classifies a control by its name and type
+func GroupOf(ctl *Control) ControlGroup {
+	name, _ := ParseControlName(ctl.Name)
+	switch {
+	case isRoutingSink(ctl.Name) && ctl.Type == ControlTypeEnumerated:
+		return GroupRouting
+	case mixInputFamilyRe.MatchString(name.Family) || matrixMixSuffixRe.MatchString(name.Suffix):
+		return GroupMixer
+	case name.Family == "Line In":
+		return GroupPreamp
+	default:
+		return GroupOther
+	}
+}
+
+// ParseControlGroup parses a group name (case-insensitive)
+func ParseControlGroup(s string) (ControlGroup, error) {
+	for _, group := range ControlGroups {
+		if strings.EqualFold(s, string(group)) {
+			return group, nil
+		}
+	}
+
+	names := make([]string, len(ControlGroups))
+	for i, group := range ControlGroups {
+		names[i] = string(group)
+	}
+	return "", fmt.Errorf("invalid group '%s' (valid: %s)", s, strings.Join(names, ", "))
+}
+
+// InGroups returns a filter accepting controls in any of the given groups
+func InGroups(groups ...ControlGroup) func(*Control) bool {
+	return func(ctl *Control) bool {
+		group := GroupOf(ctl)
+		for _, g := range groups {
+			if g == group {
+				return true
+			}
+		}
+		return false
+	}
+}
@@ -0,0 +1,828 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        (unknown)
+// source: scarlettctl.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Control struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"` // full ID, e.g. "mixer:0.0/Level Meter[0]"
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Numid         uint32                 `protobuf:"varint,3,opt,name=numid,proto3" json:"numid,omitempty"`
+	Index         int32                  `protobuf:"varint,4,opt,name=index,proto3" json:"index,omitempty"`
+	Type          string                 `protobuf:"bytes,5,opt,name=type,proto3" json:"type,omitempty"`
+	Min           int64                  `protobuf:"varint,6,opt,name=min,proto3" json:"min,omitempty"`
+	Max           int64                  `protobuf:"varint,7,opt,name=max,proto3" json:"max,omitempty"`
+	Items         []string               `protobuf:"bytes,8,rep,name=items,proto3" json:"items,omitempty"`
+	Value         int64                  `protobuf:"varint,9,opt,name=value,proto3" json:"value,omitempty"`
+	ValueString   string                 `protobuf:"bytes,10,opt,name=value_string,json=valueString,proto3" json:"value_string,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Control) Reset() {
+	*x = Control{}
+	mi := &file_scarlettctl_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Control) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Control) ProtoMessage() {}
+
+func (x *Control) ProtoReflect() protoreflect.Message {
+	mi := &file_scarlettctl_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Control.ProtoReflect.Descriptor instead.
+func (*Control) Descriptor() ([]byte, []int) {
+	return file_scarlettctl_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Control) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Control) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Control) GetNumid() uint32 {
+	if x != nil {
+		return x.Numid
+	}
+	return 0
+}
+
+func (x *Control) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *Control) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Control) GetMin() int64 {
+	if x != nil {
+		return x.Min
+	}
+	return 0
+}
+
+func (x *Control) GetMax() int64 {
+	if x != nil {
+		return x.Max
+	}
+	return 0
+}
+
+func (x *Control) GetItems() []string {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *Control) GetValue() int64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *Control) GetValueString() string {
+	if x != nil {
+		return x.ValueString
+	}
+	return ""
+}
+
+type ListControlsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Values        bool                   `protobuf:"varint,1,opt,name=values,proto3" json:"values,omitempty"` // also read each control's current value
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListControlsRequest) Reset() {
+	*x = ListControlsRequest{}
+	mi := &file_scarlettctl_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListControlsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListControlsRequest) ProtoMessage() {}
+
+func (x *ListControlsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_scarlettctl_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListControlsRequest.ProtoReflect.Descriptor instead.
+func (*ListControlsRequest) Descriptor() ([]byte, []int) {
+	return file_scarlettctl_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ListControlsRequest) GetValues() bool {
+	if x != nil {
+		return x.Values
+	}
+	return false
+}
+
+type ListControlsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Controls      []*Control             `protobuf:"bytes,1,rep,name=controls,proto3" json:"controls,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListControlsResponse) Reset() {
+	*x = ListControlsResponse{}
+	mi := &file_scarlettctl_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListControlsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListControlsResponse) ProtoMessage() {}
+
+func (x *ListControlsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_scarlettctl_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListControlsResponse.ProtoReflect.Descriptor instead.
+func (*ListControlsResponse) Descriptor() ([]byte, []int) {
+	return file_scarlettctl_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListControlsResponse) GetControls() []*Control {
+	if x != nil {
+		return x.Controls
+	}
+	return nil
+}
+
+type GetControlRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"` // name or full ID
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetControlRequest) Reset() {
+	*x = GetControlRequest{}
+	mi := &file_scarlettctl_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetControlRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetControlRequest) ProtoMessage() {}
+
+func (x *GetControlRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_scarlettctl_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetControlRequest.ProtoReflect.Descriptor instead.
+func (*GetControlRequest) Descriptor() ([]byte, []int) {
+	return file_scarlettctl_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetControlRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type SetControlRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Name  string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"` // name or full ID
+	// Types that are valid to be assigned to Value:
+	//
+	//	*SetControlRequest_Raw
+	//	*SetControlRequest_Text
+	Value         isSetControlRequest_Value `protobuf_oneof:"value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetControlRequest) Reset() {
+	*x = SetControlRequest{}
+	mi := &file_scarlettctl_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetControlRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetControlRequest) ProtoMessage() {}
+
+func (x *SetControlRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_scarlettctl_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetControlRequest.ProtoReflect.Descriptor instead.
+func (*SetControlRequest) Descriptor() ([]byte, []int) {
+	return file_scarlettctl_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *SetControlRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SetControlRequest) GetValue() isSetControlRequest_Value {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+func (x *SetControlRequest) GetRaw() int64 {
+	if x != nil {
+		if x, ok := x.Value.(*SetControlRequest_Raw); ok {
+			return x.Raw
+		}
+	}
+	return 0
+}
+
+func (x *SetControlRequest) GetText() string {
+	if x != nil {
+		if x, ok := x.Value.(*SetControlRequest_Text); ok {
+			return x.Text
+		}
+	}
+	return ""
+}
+
+type isSetControlRequest_Value interface {
+	isSetControlRequest_Value()
+}
+
+type SetControlRequest_Raw struct {
+	Raw int64 `protobuf:"varint,2,opt,name=raw,proto3,oneof"`
+}
+
+type SetControlRequest_Text struct {
+	Text string `protobuf:"bytes,3,opt,name=text,proto3,oneof"` // as accepted by the CLI "set" command, e.g. "on"
+}
+
+func (*SetControlRequest_Raw) isSetControlRequest_Value() {}
+
+func (*SetControlRequest_Text) isSetControlRequest_Value() {}
+
+type Route struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sink          string                 `protobuf:"bytes,1,opt,name=sink,proto3" json:"sink,omitempty"`
+	SourceId      int32                  `protobuf:"varint,2,opt,name=source_id,json=sourceId,proto3" json:"source_id,omitempty"`
+	Source        string                 `protobuf:"bytes,3,opt,name=source,proto3" json:"source,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Route) Reset() {
+	*x = Route{}
+	mi := &file_scarlettctl_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Route) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Route) ProtoMessage() {}
+
+func (x *Route) ProtoReflect() protoreflect.Message {
+	mi := &file_scarlettctl_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Route.ProtoReflect.Descriptor instead.
+func (*Route) Descriptor() ([]byte, []int) {
+	return file_scarlettctl_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Route) GetSink() string {
+	if x != nil {
+		return x.Sink
+	}
+	return ""
+}
+
+func (x *Route) GetSourceId() int32 {
+	if x != nil {
+		return x.SourceId
+	}
+	return 0
+}
+
+func (x *Route) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+type GetRoutingRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRoutingRequest) Reset() {
+	*x = GetRoutingRequest{}
+	mi := &file_scarlettctl_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRoutingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRoutingRequest) ProtoMessage() {}
+
+func (x *GetRoutingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_scarlettctl_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRoutingRequest.ProtoReflect.Descriptor instead.
+func (*GetRoutingRequest) Descriptor() ([]byte, []int) {
+	return file_scarlettctl_proto_rawDescGZIP(), []int{6}
+}
+
+type GetRoutingResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Routes        []*Route               `protobuf:"bytes,1,rep,name=routes,proto3" json:"routes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRoutingResponse) Reset() {
+	*x = GetRoutingResponse{}
+	mi := &file_scarlettctl_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRoutingResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRoutingResponse) ProtoMessage() {}
+
+func (x *GetRoutingResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_scarlettctl_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRoutingResponse.ProtoReflect.Descriptor instead.
+func (*GetRoutingResponse) Descriptor() ([]byte, []int) {
+	return file_scarlettctl_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetRoutingResponse) GetRoutes() []*Route {
+	if x != nil {
+		return x.Routes
+	}
+	return nil
+}
+
+type SetRoutingRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Sink  string                 `protobuf:"bytes,1,opt,name=sink,proto3" json:"sink,omitempty"` // sink name or substring
+	// Types that are valid to be assigned to Source:
+	//
+	//	*SetRoutingRequest_SourceId
+	//	*SetRoutingRequest_SourceName
+	Source        isSetRoutingRequest_Source `protobuf_oneof:"source"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetRoutingRequest) Reset() {
+	*x = SetRoutingRequest{}
+	mi := &file_scarlettctl_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetRoutingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetRoutingRequest) ProtoMessage() {}
+
+func (x *SetRoutingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_scarlettctl_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetRoutingRequest.ProtoReflect.Descriptor instead.
+func (*SetRoutingRequest) Descriptor() ([]byte, []int) {
+	return file_scarlettctl_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *SetRoutingRequest) GetSink() string {
+	if x != nil {
+		return x.Sink
+	}
+	return ""
+}
+
+func (x *SetRoutingRequest) GetSource() isSetRoutingRequest_Source {
+	if x != nil {
+		return x.Source
+	}
+	return nil
+}
+
+func (x *SetRoutingRequest) GetSourceId() int32 {
+	if x != nil {
+		if x, ok := x.Source.(*SetRoutingRequest_SourceId); ok {
+			return x.SourceId
+		}
+	}
+	return 0
+}
+
+func (x *SetRoutingRequest) GetSourceName() string {
+	if x != nil {
+		if x, ok := x.Source.(*SetRoutingRequest_SourceName); ok {
+			return x.SourceName
+		}
+	}
+	return ""
+}
+
+type isSetRoutingRequest_Source interface {
+	isSetRoutingRequest_Source()
+}
+
+type SetRoutingRequest_SourceId struct {
+	SourceId int32 `protobuf:"varint,2,opt,name=source_id,json=sourceId,proto3,oneof"`
+}
+
+type SetRoutingRequest_SourceName struct {
+	SourceName string `protobuf:"bytes,3,opt,name=source_name,json=sourceName,proto3,oneof"`
+}
+
+func (*SetRoutingRequest_SourceId) isSetRoutingRequest_Source() {}
+
+func (*SetRoutingRequest_SourceName) isSetRoutingRequest_Source() {}
+
+type WatchControlsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Names         []string               `protobuf:"bytes,1,rep,name=names,proto3" json:"names,omitempty"` // names or full IDs to watch, empty for all
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchControlsRequest) Reset() {
+	*x = WatchControlsRequest{}
+	mi := &file_scarlettctl_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchControlsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchControlsRequest) ProtoMessage() {}
+
+func (x *WatchControlsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_scarlettctl_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchControlsRequest.ProtoReflect.Descriptor instead.
+func (*WatchControlsRequest) Descriptor() ([]byte, []int) {
+	return file_scarlettctl_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *WatchControlsRequest) GetNames() []string {
+	if x != nil {
+		return x.Names
+	}
+	return nil
+}
+
+type ControlChange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Control       *Control               `protobuf:"bytes,1,opt,name=control,proto3" json:"control,omitempty"`
+	Time          *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=time,proto3" json:"time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ControlChange) Reset() {
+	*x = ControlChange{}
+	mi := &file_scarlettctl_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ControlChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ControlChange) ProtoMessage() {}
+
+func (x *ControlChange) ProtoReflect() protoreflect.Message {
+	mi := &file_scarlettctl_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ControlChange.ProtoReflect.Descriptor instead.
+func (*ControlChange) Descriptor() ([]byte, []int) {
+	return file_scarlettctl_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ControlChange) GetControl() *Control {
+	if x != nil {
+		return x.Control
+	}
+	return nil
+}
+
+func (x *ControlChange) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+var File_scarlettctl_proto protoreflect.FileDescriptor
+
+const file_scarlettctl_proto_rawDesc = "" +
+	"\n" +
+	"\x11scarlettctl.proto\x12\x0escarlettctl.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xe0\x01\n" +
+	"\aControl\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
+	"\x05numid\x18\x03 \x01(\rR\x05numid\x12\x14\n" +
+	"\x05index\x18\x04 \x01(\x05R\x05index\x12\x12\n" +
+	"\x04type\x18\x05 \x01(\tR\x04type\x12\x10\n" +
+	"\x03min\x18\x06 \x01(\x03R\x03min\x12\x10\n" +
+	"\x03max\x18\a \x01(\x03R\x03max\x12\x14\n" +
+	"\x05items\x18\b \x03(\tR\x05items\x12\x14\n" +
+	"\x05value\x18\t \x01(\x03R\x05value\x12!\n" +
+	"\fvalue_string\x18\n" +
+	" \x01(\tR\vvalueString\"-\n" +
+	"\x13ListControlsRequest\x12\x16\n" +
+	"\x06values\x18\x01 \x01(\bR\x06values\"K\n" +
+	"\x14ListControlsResponse\x123\n" +
+	"\bcontrols\x18\x01 \x03(\v2\x17.scarlettctl.v1.ControlR\bcontrols\"'\n" +
+	"\x11GetControlRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"Z\n" +
+	"\x11SetControlRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x12\n" +
+	"\x03raw\x18\x02 \x01(\x03H\x00R\x03raw\x12\x14\n" +
+	"\x04text\x18\x03 \x01(\tH\x00R\x04textB\a\n" +
+	"\x05value\"P\n" +
+	"\x05Route\x12\x12\n" +
+	"\x04sink\x18\x01 \x01(\tR\x04sink\x12\x1b\n" +
+	"\tsource_id\x18\x02 \x01(\x05R\bsourceId\x12\x16\n" +
+	"\x06source\x18\x03 \x01(\tR\x06source\"\x13\n" +
+	"\x11GetRoutingRequest\"C\n" +
+	"\x12GetRoutingResponse\x12-\n" +
+	"\x06routes\x18\x01 \x03(\v2\x15.scarlettctl.v1.RouteR\x06routes\"s\n" +
+	"\x11SetRoutingRequest\x12\x12\n" +
+	"\x04sink\x18\x01 \x01(\tR\x04sink\x12\x1d\n" +
+	"\tsource_id\x18\x02 \x01(\x05H\x00R\bsourceId\x12!\n" +
+	"\vsource_name\x18\x03 \x01(\tH\x00R\n" +
+	"sourceNameB\b\n" +
+	"\x06source\",\n" +
+	"\x14WatchControlsRequest\x12\x14\n" +
+	"\x05names\x18\x01 \x03(\tR\x05names\"r\n" +
+	"\rControlChange\x121\n" +
+	"\acontrol\x18\x01 \x01(\v2\x17.scarlettctl.v1.ControlR\acontrol\x12.\n" +
+	"\x04time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x04time2\xee\x03\n" +
+	"\bScarlett\x12Y\n" +
+	"\fListControls\x12#.scarlettctl.v1.ListControlsRequest\x1a$.scarlettctl.v1.ListControlsResponse\x12H\n" +
+	"\n" +
+	"GetControl\x12!.scarlettctl.v1.GetControlRequest\x1a\x17.scarlettctl.v1.Control\x12H\n" +
+	"\n" +
+	"SetControl\x12!.scarlettctl.v1.SetControlRequest\x1a\x17.scarlettctl.v1.Control\x12S\n" +
+	"\n" +
+	"GetRouting\x12!.scarlettctl.v1.GetRoutingRequest\x1a\".scarlettctl.v1.GetRoutingResponse\x12F\n" +
+	"\n" +
+	"SetRouting\x12!.scarlettctl.v1.SetRoutingRequest\x1a\x15.scarlettctl.v1.Route\x12V\n" +
+	"\rWatchControls\x12$.scarlettctl.v1.WatchControlsRequest\x1a\x1d.scarlettctl.v1.ControlChange0\x01B2Z0github.com/michaelquigley/scarlettctl/grpc/pb;pbb\x06proto3"
+
+var (
+	file_scarlettctl_proto_rawDescOnce sync.Once
+	file_scarlettctl_proto_rawDescData []byte
+)
+
+func file_scarlettctl_proto_rawDescGZIP() []byte {
+	file_scarlettctl_proto_rawDescOnce.Do(func() {
+		file_scarlettctl_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_scarlettctl_proto_rawDesc), len(file_scarlettctl_proto_rawDesc)))
+	})
+	return file_scarlettctl_proto_rawDescData
+}
+
+var file_scarlettctl_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_scarlettctl_proto_goTypes = []any{
+	(*Control)(nil),               // 0: scarlettctl.v1.Control
+	(*ListControlsRequest)(nil),   // 1: scarlettctl.v1.ListControlsRequest
+	(*ListControlsResponse)(nil),  // 2: scarlettctl.v1.ListControlsResponse
+	(*GetControlRequest)(nil),     // 3: scarlettctl.v1.GetControlRequest
+	(*SetControlRequest)(nil),     // 4: scarlettctl.v1.SetControlRequest
+	(*Route)(nil),                 // 5: scarlettctl.v1.Route
+	(*GetRoutingRequest)(nil),     // 6: scarlettctl.v1.GetRoutingRequest
+	(*GetRoutingResponse)(nil),    // 7: scarlettctl.v1.GetRoutingResponse
+	(*SetRoutingRequest)(nil),     // 8: scarlettctl.v1.SetRoutingRequest
+	(*WatchControlsRequest)(nil),  // 9: scarlettctl.v1.WatchControlsRequest
+	(*ControlChange)(nil),         // 10: scarlettctl.v1.ControlChange
+	(*timestamppb.Timestamp)(nil), // 11: google.protobuf.Timestamp
+}
+var file_scarlettctl_proto_depIdxs = []int32{
+	0,  // 0: scarlettctl.v1.ListControlsResponse.controls:type_name -> scarlettctl.v1.Control
+	5,  // 1: scarlettctl.v1.GetRoutingResponse.routes:type_name -> scarlettctl.v1.Route
+	0,  // 2: scarlettctl.v1.ControlChange.control:type_name -> scarlettctl.v1.Control
+	11, // 3: scarlettctl.v1.ControlChange.time:type_name -> google.protobuf.Timestamp
+	1,  // 4: scarlettctl.v1.Scarlett.ListControls:input_type -> scarlettctl.v1.ListControlsRequest
+	3,  // 5: scarlettctl.v1.Scarlett.GetControl:input_type -> scarlettctl.v1.GetControlRequest
+	4,  // 6: scarlettctl.v1.Scarlett.SetControl:input_type -> scarlettctl.v1.SetControlRequest
+	6,  // 7: scarlettctl.v1.Scarlett.GetRouting:input_type -> scarlettctl.v1.GetRoutingRequest
+	8,  // 8: scarlettctl.v1.Scarlett.SetRouting:input_type -> scarlettctl.v1.SetRoutingRequest
+	9,  // 9: scarlettctl.v1.Scarlett.WatchControls:input_type -> scarlettctl.v1.WatchControlsRequest
+	2,  // 10: scarlettctl.v1.Scarlett.ListControls:output_type -> scarlettctl.v1.ListControlsResponse
+	0,  // 11: scarlettctl.v1.Scarlett.GetControl:output_type -> scarlettctl.v1.Control
+	0,  // 12: scarlettctl.v1.Scarlett.SetControl:output_type -> scarlettctl.v1.Control
+	7,  // 13: scarlettctl.v1.Scarlett.GetRouting:output_type -> scarlettctl.v1.GetRoutingResponse
+	5,  // 14: scarlettctl.v1.Scarlett.SetRouting:output_type -> scarlettctl.v1.Route
+	10, // 15: scarlettctl.v1.Scarlett.WatchControls:output_type -> scarlettctl.v1.ControlChange
+	10, // [10:16] is the sub-list for method output_type
+	4,  // [4:10] is the sub-list for method input_type
+	4,  // [4:4] is the sub-list for extension type_name
+	4,  // [4:4] is the sub-list for extension extendee
+	0,  // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_scarlettctl_proto_init() }
+func file_scarlettctl_proto_init() {
+	if File_scarlettctl_proto != nil {
+		return
+	}
+	file_scarlettctl_proto_msgTypes[4].OneofWrappers = []any{
+		(*SetControlRequest_Raw)(nil),
+		(*SetControlRequest_Text)(nil),
+	}
+	file_scarlettctl_proto_msgTypes[8].OneofWrappers = []any{
+		(*SetRoutingRequest_SourceId)(nil),
+		(*SetRoutingRequest_SourceName)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_scarlettctl_proto_rawDesc), len(file_scarlettctl_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   11,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_scarlettctl_proto_goTypes,
+		DependencyIndexes: file_scarlettctl_proto_depIdxs,
+		MessageInfos:      file_scarlettctl_proto_msgTypes,
+	}.Build()
+	File_scarlettctl_proto = out.File
+	file_scarlettctl_proto_goTypes = nil
+	file_scarlettctl_proto_depIdxs = nil
+}
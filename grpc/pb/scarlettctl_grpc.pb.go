@@ -0,0 +1,331 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.2
+// - protoc             (unknown)
+// source: scarlettctl.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Scarlett_ListControls_FullMethodName  = "/scarlettctl.v1.Scarlett/ListControls"
+	Scarlett_GetControl_FullMethodName    = "/scarlettctl.v1.Scarlett/GetControl"
+	Scarlett_SetControl_FullMethodName    = "/scarlettctl.v1.Scarlett/SetControl"
+	Scarlett_GetRouting_FullMethodName    = "/scarlettctl.v1.Scarlett/GetRouting"
+	Scarlett_SetRouting_FullMethodName    = "/scarlettctl.v1.Scarlett/SetRouting"
+	Scarlett_WatchControls_FullMethodName = "/scarlettctl.v1.Scarlett/WatchControls"
+)
+
+// ScarlettClient is the client API for Scarlett service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Scarlett exposes the controls of a single card
+type ScarlettClient interface {
+	// ListControls returns every control on the card
+	ListControls(ctx context.Context, in *ListControlsRequest, opts ...grpc.CallOption) (*ListControlsResponse, error)
+	// GetControl returns a control, with its current value, by name or full ID
+	GetControl(ctx context.Context, in *GetControlRequest, opts ...grpc.CallOption) (*Control, error)
+	// SetControl writes a control and returns it with its new value
+	SetControl(ctx context.Context, in *SetControlRequest, opts ...grpc.CallOption) (*Control, error)
+	// GetRouting returns the source currently feeding each routing sink
+	GetRouting(ctx context.Context, in *GetRoutingRequest, opts ...grpc.CallOption) (*GetRoutingResponse, error)
+	// SetRouting connects a source to a routing sink
+	SetRouting(ctx context.Context, in *SetRoutingRequest, opts ...grpc.CallOption) (*Route, error)
+	// WatchControls streams control values as they change
+	WatchControls(ctx context.Context, in *WatchControlsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ControlChange], error)
+}
+
+type scarlettClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewScarlettClient(cc grpc.ClientConnInterface) ScarlettClient {
+	return &scarlettClient{cc}
+}
+
+func (c *scarlettClient) ListControls(ctx context.Context, in *ListControlsRequest, opts ...grpc.CallOption) (*ListControlsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListControlsResponse)
+	err := c.cc.Invoke(ctx, Scarlett_ListControls_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *scarlettClient) GetControl(ctx context.Context, in *GetControlRequest, opts ...grpc.CallOption) (*Control, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Control)
+	err := c.cc.Invoke(ctx, Scarlett_GetControl_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *scarlettClient) SetControl(ctx context.Context, in *SetControlRequest, opts ...grpc.CallOption) (*Control, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Control)
+	err := c.cc.Invoke(ctx, Scarlett_SetControl_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *scarlettClient) GetRouting(ctx context.Context, in *GetRoutingRequest, opts ...grpc.CallOption) (*GetRoutingResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetRoutingResponse)
+	err := c.cc.Invoke(ctx, Scarlett_GetRouting_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *scarlettClient) SetRouting(ctx context.Context, in *SetRoutingRequest, opts ...grpc.CallOption) (*Route, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Route)
+	err := c.cc.Invoke(ctx, Scarlett_SetRouting_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *scarlettClient) WatchControls(ctx context.Context, in *WatchControlsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ControlChange], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Scarlett_ServiceDesc.Streams[0], Scarlett_WatchControls_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchControlsRequest, ControlChange]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Scarlett_WatchControlsClient = grpc.ServerStreamingClient[ControlChange]
+
+// ScarlettServer is the server API for Scarlett service.
+// All implementations must embed UnimplementedScarlettServer
+// for forward compatibility.
+//
+// Scarlett exposes the controls of a single card
+type ScarlettServer interface {
+	// ListControls returns every control on the card
+	ListControls(context.Context, *ListControlsRequest) (*ListControlsResponse, error)
+	// GetControl returns a control, with its current value, by name or full ID
+	GetControl(context.Context, *GetControlRequest) (*Control, error)
+	// SetControl writes a control and returns it with its new value
+	SetControl(context.Context, *SetControlRequest) (*Control, error)
+	// GetRouting returns the source currently feeding each routing sink
+	GetRouting(context.Context, *GetRoutingRequest) (*GetRoutingResponse, error)
+	// SetRouting connects a source to a routing sink
+	SetRouting(context.Context, *SetRoutingRequest) (*Route, error)
+	// WatchControls streams control values as they change
+	WatchControls(*WatchControlsRequest, grpc.ServerStreamingServer[ControlChange]) error
+	mustEmbedUnimplementedScarlettServer()
+}
+
+// UnimplementedScarlettServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedScarlettServer struct{}
+
+func (UnimplementedScarlettServer) ListControls(context.Context, *ListControlsRequest) (*ListControlsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListControls not implemented")
+}
+func (UnimplementedScarlettServer) GetControl(context.Context, *GetControlRequest) (*Control, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetControl not implemented")
+}
+func (UnimplementedScarlettServer) SetControl(context.Context, *SetControlRequest) (*Control, error) {
+	return nil, status.Error(codes.Unimplemented, "method SetControl not implemented")
+}
+func (UnimplementedScarlettServer) GetRouting(context.Context, *GetRoutingRequest) (*GetRoutingResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetRouting not implemented")
+}
+func (UnimplementedScarlettServer) SetRouting(context.Context, *SetRoutingRequest) (*Route, error) {
+	return nil, status.Error(codes.Unimplemented, "method SetRouting not implemented")
+}
+func (UnimplementedScarlettServer) WatchControls(*WatchControlsRequest, grpc.ServerStreamingServer[ControlChange]) error {
+	return status.Error(codes.Unimplemented, "method WatchControls not implemented")
+}
+func (UnimplementedScarlettServer) mustEmbedUnimplementedScarlettServer() {}
+func (UnimplementedScarlettServer) testEmbeddedByValue()                  {}
+
+// UnsafeScarlettServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ScarlettServer will
+// result in compilation errors.
+type UnsafeScarlettServer interface {
+	mustEmbedUnimplementedScarlettServer()
+}
+
+func RegisterScarlettServer(s grpc.ServiceRegistrar, srv ScarlettServer) {
+	// If the following call panics, it indicates UnimplementedScarlettServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Scarlett_ServiceDesc, srv)
+}
+
+func _Scarlett_ListControls_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListControlsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ScarlettServer).ListControls(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Scarlett_ListControls_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ScarlettServer).ListControls(ctx, req.(*ListControlsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Scarlett_GetControl_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetControlRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ScarlettServer).GetControl(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Scarlett_GetControl_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ScarlettServer).GetControl(ctx, req.(*GetControlRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Scarlett_SetControl_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetControlRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ScarlettServer).SetControl(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Scarlett_SetControl_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ScarlettServer).SetControl(ctx, req.(*SetControlRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Scarlett_GetRouting_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRoutingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ScarlettServer).GetRouting(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Scarlett_GetRouting_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ScarlettServer).GetRouting(ctx, req.(*GetRoutingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Scarlett_SetRouting_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetRoutingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ScarlettServer).SetRouting(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Scarlett_SetRouting_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ScarlettServer).SetRouting(ctx, req.(*SetRoutingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Scarlett_WatchControls_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchControlsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ScarlettServer).WatchControls(m, &grpc.GenericServerStream[WatchControlsRequest, ControlChange]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Scarlett_WatchControlsServer = grpc.ServerStreamingServer[ControlChange]
+
+// Scarlett_ServiceDesc is the grpc.ServiceDesc for Scarlett service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Scarlett_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "scarlettctl.v1.Scarlett",
+	HandlerType: (*ScarlettServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListControls",
+			Handler:    _Scarlett_ListControls_Handler,
+		},
+		{
+			MethodName: "GetControl",
+			Handler:    _Scarlett_GetControl_Handler,
+		},
+		{
+			MethodName: "SetControl",
+			Handler:    _Scarlett_SetControl_Handler,
+		},
+		{
+			MethodName: "GetRouting",
+			Handler:    _Scarlett_GetRouting_Handler,
+		},
+		{
+			MethodName: "SetRouting",
+			Handler:    _Scarlett_SetRouting_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchControls",
+			Handler:       _Scarlett_WatchControls_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "scarlettctl.proto",
+}
@@ -0,0 +1,298 @@
+// Package grpc serves a card's controls over gRPC
+// The service is defined in scarlettctl.proto; regenerate the pb package with
+// protoc-gen-go and protoc-gen-go-grpc after changing it.
+package grpc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+
+	"github.com/michaelquigley/scarlettctl"
+	"github.com/michaelquigley/scarlettctl/grpc/pb"
+	grpcgo "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Server implements the Scarlett gRPC service backed by a card
+type Server struct {
+	pb.UnimplementedScarlettServer
+	card *scarlettctl.Card
+
+	watchMu  sync.Mutex
+	watchers map[*watcher]bool         // WatchControls streams
+	monitor  *scarlettctl.EventMonitor // shared by the streams, nil when none are open
+}
+
+// NewServer creates a server for an open card
+func NewServer(card *scarlettctl.Card) *Server {
+	return &Server{card: card}
+}
+
+// Register registers the service, and server reflection so tools like grpcurl
+// can discover it, on gs
+func (s *Server) Register(gs *grpcgo.Server) {
+	pb.RegisterScarlettServer(gs, s)
+	reflection.Register(gs)
+}
+
+// Serve listens on addr and serves the card until the listener fails
+func Serve(card *scarlettctl.Card, addr string) error {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	gs := grpcgo.NewServer()
+	NewServer(card).Register(gs)
+	return gs.Serve(lis)
+}
+
+// ListControls returns every control on the card
+func (s *Server) ListControls(ctx context.Context, req *pb.ListControlsRequest) (*pb.ListControlsResponse, error) {
+	controls, err := s.card.GetControls()
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+
+	var values map[scarlettctl.ControlKey]int64
+	if req.GetValues() {
+		if values, err = s.card.ReadAllValues(); err != nil {
+			return nil, status.Error(codes.Unavailable, err.Error())
+		}
+	}
+
+	resp := &pb.ListControlsResponse{Controls: make([]*pb.Control, 0, len(controls))}
+	for _, ctl := range controls {
+		msg := controlMessage(ctl)
+		if value, ok := values[ctl.Key()]; ok {
+			setValue(msg, ctl, value)
+		}
+		resp.Controls = append(resp.Controls, msg)
+	}
+
+	return resp, nil
+}
+
+// GetControl returns a control with its current value
+func (s *Server) GetControl(ctx context.Context, req *pb.GetControlRequest) (*pb.Control, error) {
+	ctl, err := s.findControl(req.GetName())
+	if err != nil {
+		return nil, err
+	}
+	return readControl(ctl)
+}
+
+// SetControl writes a control and returns it with its new value
+func (s *Server) SetControl(ctx context.Context, req *pb.SetControlRequest) (*pb.Control, error) {
+	ctl, err := s.findControl(req.GetName())
+	if err != nil {
+		return nil, err
+	}
+
+	switch v := req.GetValue().(type) {
+	case *pb.SetControlRequest_Raw:
+		err = ctl.SetValue(v.Raw)
+	case *pb.SetControlRequest_Text:
+		err = ctl.SetValueByString(v.Text)
+	default:
+		return nil, status.Error(codes.InvalidArgument, "no value given")
+	}
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return readControl(ctl)
+}
+
+// GetRouting returns the source currently feeding each routing sink
+func (s *Server) GetRouting(ctx context.Context, req *pb.GetRoutingRequest) (*pb.GetRoutingResponse, error) {
+	sinks, err := s.card.GetRoutingSinks()
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+
+	resp := &pb.GetRoutingResponse{Routes: make([]*pb.Route, 0, len(sinks))}
+	for _, sink := range sinks {
+		route, err := routeMessage(sink.Control)
+		if err != nil {
+			return nil, err
+		}
+		resp.Routes = append(resp.Routes, route)
+	}
+
+	return resp, nil
+}
+
+// SetRouting connects a source to a routing sink
+func (s *Server) SetRouting(ctx context.Context, req *pb.SetRoutingRequest) (*pb.Route, error) {
+	sink, err := s.card.FindRoutingSink(req.GetSink())
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+
+	switch src := req.GetSource().(type) {
+	case *pb.SetRoutingRequest_SourceId:
+		err = sink.Control.SetValue(int64(src.SourceId))
+	case *pb.SetRoutingRequest_SourceName:
+		err = s.card.SetRoutingByNames(sink.Name, src.SourceName)
+	default:
+		return nil, status.Error(codes.InvalidArgument, "no source given")
+	}
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return routeMessage(sink.Control)
+}
+
+// WatchControls streams the current value of each watched control, then every
+// change reported by the card's event monitor until the client goes away. All
+// streams share one monitor.
+func (s *Server) WatchControls(req *pb.WatchControlsRequest, stream pb.Scarlett_WatchControlsServer) error {
+	controls, err := s.watchedControls(req.GetNames())
+	if err != nil {
+		return err
+	}
+
+	watched := make(map[scarlettctl.ControlKey]bool, len(controls))
+	for _, ctl := range controls {
+		watched[ctl.Key()] = true
+	}
+
+	send := func(ctl *scarlettctl.Control, value int64) error {
+		msg := controlMessage(ctl)
+		setValue(msg, ctl, value)
+		return stream.Send(&pb.ControlChange{Control: msg, Time: timestamppb.Now()})
+	}
+
+	// subscribe before reading the current state, so no change is missed
+	w := s.subscribe()
+	defer s.unsubscribe(w)
+
+	// start from the current state so clients don't need a separate read
+	last := make(map[scarlettctl.ControlKey]int64)
+	values, err := s.card.ReadAllValues()
+	if err != nil {
+		return status.Error(codes.Unavailable, err.Error())
+	}
+	for _, ctl := range controls {
+		value, ok := values[ctl.Key()]
+		if !ok {
+			continue
+		}
+		last[ctl.Key()] = value
+		if err := send(ctl, value); err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-w.notify:
+		}
+
+		changes, err := w.take()
+		for _, c := range changes {
+			key := c.ctl.Key()
+			if !watched[key] {
+				continue
+			}
+			if lastValue, exists := last[key]; exists && lastValue == c.value {
+				continue
+			}
+			last[key] = c.value
+			if err := send(c.ctl, c.value); err != nil {
+				return err
+			}
+		}
+		if err != nil {
+			return status.Error(codes.Unavailable, err.Error())
+		}
+	}
+}
+
+// watchedControls resolves the requested names, defaulting to every control
+func (s *Server) watchedControls(names []string) ([]*scarlettctl.Control, error) {
+	if len(names) == 0 {
+		controls, err := s.card.GetControls()
+		if err != nil {
+			return nil, status.Error(codes.Unavailable, err.Error())
+		}
+		return controls, nil
+	}
+
+	controls := make([]*scarlettctl.Control, 0, len(names))
+	for _, name := range names {
+		ctl, err := s.findControl(name)
+		if err != nil {
+			return nil, err
+		}
+		controls = append(controls, ctl)
+	}
+	return controls, nil
+}
+
+// findControl looks up a control by name or full ID, as a gRPC status error
+func (s *Server) findControl(name string) (*scarlettctl.Control, error) {
+	ctl, err := s.card.FindControl(name)
+	if err != nil {
+		if errors.Is(err, scarlettctl.ErrAmbiguous) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	return ctl, nil
+}
+
+// readControl reads a control's current value into a message
+func readControl(ctl *scarlettctl.Control) (*pb.Control, error) {
+	value, err := ctl.GetValue()
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+
+	msg := controlMessage(ctl)
+	setValue(msg, ctl, value)
+	return msg, nil
+}
+
+// routeMessage reads the source currently feeding a sink
+func routeMessage(ctl *scarlettctl.Control) (*pb.Route, error) {
+	value, err := ctl.GetValue()
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "failed to read routing for %s: %v", ctl.Name, err)
+	}
+
+	return &pb.Route{
+		Sink:     ctl.Name,
+		SourceId: int32(value),
+		Source:   ctl.FormatValue(value),
+	}, nil
+}
+
+// controlMessage describes a control without its value
+func controlMessage(ctl *scarlettctl.Control) *pb.Control {
+	return &pb.Control{
+		Id:    ctl.FullID(),
+		Name:  ctl.Name,
+		Numid: uint32(ctl.NumID),
+		Index: int32(ctl.Index),
+		Type:  ctl.Type.String(),
+		Min:   ctl.Min,
+		Max:   ctl.Max,
+		Items: ctl.Items,
+	}
+}
+
+// setValue fills in a message's value fields
+func setValue(msg *pb.Control, ctl *scarlettctl.Control, value int64) {
+	msg.Value = value
+	msg.ValueString = ctl.FormatValue(value)
+}
@@ -0,0 +1,132 @@
+package grpc
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/michaelquigley/scarlettctl"
+)
+
+// change is a control's new value, as reported by the card's event monitor
+type change struct {
+	ctl   *scarlettctl.Control
+	value int64
+}
+
+// watcher is one WatchControls stream's share of the card's event monitor. The
+// monitor never waits on a stream: changes queue up, keeping only the latest
+// value of each control, until the stream takes them.
+type watcher struct {
+	mu      sync.Mutex
+	pending map[scarlettctl.ControlKey]int64 // latest value of each changed control
+	order   []*scarlettctl.Control           // changed controls, in order of first change
+	err     error                            // why the monitor stopped, if it failed
+	notify  chan struct{}                    // signaled when there is something to take
+}
+
+func newWatcher() *watcher {
+	return &watcher{
+		pending: make(map[scarlettctl.ControlKey]int64),
+		notify:  make(chan struct{}, 1),
+	}
+}
+
+// push queues a change for the stream
+func (w *watcher) push(ctl *scarlettctl.Control, value int64) {
+	w.mu.Lock()
+	if _, queued := w.pending[ctl.Key()]; !queued {
+		w.order = append(w.order, ctl)
+	}
+	w.pending[ctl.Key()] = value
+	w.mu.Unlock()
+	w.signal()
+}
+
+// fail ends the stream with the monitor's error
+func (w *watcher) fail(err error) {
+	w.mu.Lock()
+	w.err = err
+	w.mu.Unlock()
+	w.signal()
+}
+
+func (w *watcher) signal() {
+	select {
+	case w.notify <- struct{}{}:
+	default:
+	}
+}
+
+// take returns the queued changes, and the monitor's error once it has failed
+func (w *watcher) take() ([]change, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	changes := make([]change, 0, len(w.order))
+	for _, ctl := range w.order {
+		changes = append(changes, change{ctl: ctl, value: w.pending[ctl.Key()]})
+	}
+	clear(w.pending)
+	w.order = w.order[:0]
+	return changes, w.err
+}
+
+// subscribe adds a watcher, starting the card's event monitor for the first.
+// Every stream shares the one monitor, since monitors on the same card would
+// read, and so take, each other's events.
+func (s *Server) subscribe() *watcher {
+	s.watchMu.Lock()
+	defer s.watchMu.Unlock()
+
+	w := newWatcher()
+	if s.watchers == nil {
+		s.watchers = make(map[*watcher]bool)
+	}
+	s.watchers[w] = true
+
+	if s.monitor == nil {
+		s.monitor = s.card.NewEventMonitor()
+		go s.runMonitor(s.monitor)
+	}
+	return w
+}
+
+// unsubscribe removes a watcher, stopping the monitor after the last one
+func (s *Server) unsubscribe(w *watcher) {
+	s.watchMu.Lock()
+	defer s.watchMu.Unlock()
+
+	delete(s.watchers, w)
+	if len(s.watchers) == 0 && s.monitor != nil {
+		s.monitor.Stop()
+		s.monitor = nil
+	}
+}
+
+// runMonitor fans the changes a monitor reports out to every watcher until it
+// is stopped or fails; a failure ends the streams watching
+func (s *Server) runMonitor(monitor *scarlettctl.EventMonitor) {
+	err := monitor.WatchControls(func(ctl *scarlettctl.Control, value int64) error {
+		s.watchMu.Lock()
+		defer s.watchMu.Unlock()
+
+		for w := range s.watchers {
+			w.push(ctl, value)
+		}
+		return nil
+	})
+
+	s.watchMu.Lock()
+	defer s.watchMu.Unlock()
+
+	if s.monitor != monitor {
+		return // stopped after the last watcher left
+	}
+	s.monitor = nil
+	if err == nil {
+		err = errors.New("event monitor stopped")
+	}
+	for w := range s.watchers {
+		w.fail(err)
+	}
+}
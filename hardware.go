@@ -0,0 +1,24 @@
+package scarlettctl
+
+// hardware holds the ALSA calls a card's controls are enumerated, read and
+// written through, and its handle closed with, so tests can stand an in-memory
+// device in for them
+var hardware = struct {
+	enumerate   func(h *alsaHandle) ([]*Control, []ControlError, error)
+	count       func(h *alsaHandle) (int, error)
+	writable    func(h *alsaHandle, numids []uint) ([]bool, error)
+	read        func(h *alsaHandle, ctl *Control) (int64, error)
+	readElement func(h *alsaHandle, numid uint, ctlType ControlType, count int) ([]int64, error)
+	write       func(h *alsaHandle, ctl *Control, value int64) error
+	tlv         func(h *alsaHandle, numid uint) ([]byte, error)
+	close       func(h *alsaHandle) error
+}{
+	enumerate:   enumerateControls,
+	count:       countControls,
+	writable:    elementsWritable,
+	read:        readControl,
+	readElement: readElement,
+	write:       writeControl,
+	tlv:         readTLV,
+	close:       closeCard,
+}
@@ -0,0 +1,52 @@
+package scarlettctl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// healthReadAttempts bounds how many controls HealthCheck tries to read, so a
+// wedged device fails quickly rather than timing out on every control
+const healthReadAttempts = 3
+
+// HealthCheck reports whether the card responds: its controls must enumerate
+// and at least one value must read back from the hardware, bypassing the
+// value cache. It is meant for watchdogs that need to notice a wedged or
+// unplugged interface. A read that times out (see SetTimeout) fails the check
+// at once, since further reads would only queue behind it.
+func (c *Card) HealthCheck() error {
+	controls, err := c.GetControls()
+	if err != nil {
+		return fmt.Errorf("failed to enumerate controls: %w", err)
+	}
+
+	var lastErr error
+	attempts := 0
+	for _, ctl := range controls {
+		if ctl.Type == ControlTypeBytes || ctl.Type == ControlTypeIEC958 {
+			continue
+		}
+		if attempts == healthReadAttempts {
+			break
+		}
+		attempts++
+
+		err := c.call(func(h *alsaHandle) error {
+			_, err := hardware.read(h, ctl)
+			return err
+		})
+		if err == nil {
+			return nil
+		}
+		lastErr = fmt.Errorf("failed to read %s: %w", ctl.Name, err)
+		if errors.Is(err, context.DeadlineExceeded) {
+			break
+		}
+	}
+
+	if lastErr == nil {
+		return fmt.Errorf("no readable controls found")
+	}
+	return lastErr
+}
@@ -0,0 +1,121 @@
+package scarlettctl
+
+import (
+	"fmt"
+	"strings"
+)
+
+// inputModeNames spells out the abbreviated items of input mode enums
+var inputModeNames = map[string]string{
+	"inst": "Instrument",
+	"line": "Line",
+	"mic":  "Mic",
+}
+
+// InputMode returns the control selecting the channel's input source: the
+// input select enum where the device has one, otherwise the level enum. Older
+// interfaces switch instrument level with the impedance switch instead, which
+// SetInputMode and GetInputMode also handle.
+func (ch *PreampChannel) InputMode() *Control {
+	if ch.InputSelect != nil {
+		return ch.InputSelect
+	}
+	return ch.Level
+}
+
+// GetInputMode returns the channel's input mode, e.g. "Instrument" or "Line"
+func (c *Card) GetInputMode(channelNum int) (string, error) {
+	ch, err := c.GetPreampChannel(channelNum)
+	if err != nil {
+		return "", err
+	}
+
+	mode, ok := inputModeState(ch)
+	if !ok {
+		return "", fmt.Errorf("channel %d input mode: %w", channelNum, ErrNotSupported)
+	}
+	return mode, nil
+}
+
+// SetInputMode selects a channel's input source (e.g. "Line", "Inst",
+// "Instrument" or "Mic"), validated against the items of its input select or
+// level enum. On interfaces with an impedance switch instead, "Inst" turns it
+// on and "Line" off.
+func (c *Card) SetInputMode(channelNum int, mode string) error {
+	ch, err := c.GetPreampChannel(channelNum)
+	if err != nil {
+		return err
+	}
+
+	if ctl := ch.InputMode(); ctl != nil {
+		value, err := inputModeValue(ctl, mode)
+		if err != nil {
+			return fmt.Errorf("channel %d: %v", channelNum, err)
+		}
+		return ctl.SetValueVerified(value, true)
+	}
+
+	if ch.Impedance != nil {
+		switch strings.ToLower(mode) {
+		case "inst", "instrument":
+			return ch.Impedance.SetValue(1)
+		case "line":
+			return ch.Impedance.SetValue(0)
+		}
+		return fmt.Errorf("channel %d: invalid input mode '%s' (valid: Line, Inst)", channelNum, mode)
+	}
+
+	return fmt.Errorf("channel %d input mode: %w", channelNum, ErrNotSupported)
+}
+
+// inputModeValue resolves a mode to an item of an input mode enum. Besides the
+// item names and their prefixes, a spelled-out mode matches its abbreviated
+// item, so "Instrument" selects "Inst".
+func inputModeValue(ctl *Control, mode string) (int64, error) {
+	var found []int
+	for i, item := range ctl.Items {
+		if strings.EqualFold(item, mode) {
+			return int64(i), nil
+		}
+		if strings.HasPrefix(strings.ToLower(mode), strings.ToLower(item)) {
+			found = append(found, i)
+		}
+	}
+	if len(found) == 1 {
+		return int64(found[0]), nil
+	}
+
+	index, err := ctl.matchItem(mode)
+	if err != nil {
+		return 0, err
+	}
+	return int64(index), nil
+}
+
+// inputModeState reads a channel's input mode with abbreviations spelled out,
+// reporting false when the channel has no input mode control or it can't be read
+func inputModeState(ch *PreampChannel) (string, bool) {
+	if ctl := ch.InputMode(); ctl != nil {
+		value, err := ctl.GetValueString()
+		if err != nil {
+			return "", false
+		}
+		if name, ok := inputModeNames[strings.ToLower(value)]; ok {
+			return name, true
+		}
+		return value, true
+	}
+
+	if ch.Impedance != nil {
+		value, err := ch.Impedance.GetValue()
+		if err != nil {
+			return "", false
+		}
+		if value != 0 {
+			return "Instrument", true
+		}
+		return "Line", true
+	}
+
+	return "", false
+}
@@ -0,0 +1,94 @@
+package scarlettctl
+
+import (
+	"fmt"
+	"math"
+)
+
+// GainNormalization chooses the gain NormalizeLinkedGain gives a linked pair
+type GainNormalization int
+
+const (
+	// NormalizeAverage sets both channels to the mean of their gains
+	NormalizeAverage GainNormalization = iota
+	// NormalizeFirst sets the second channel to the first channel's gain
+	NormalizeFirst
+)
+
+// NormalizeLinkedGain reconciles linked channel pairs whose gains have drifted
+// apart, setting both channels of each pair to one gain chosen by mode. A pair
+// is an odd channel and the next one, linked when either has its link or gain
+// link switch on; pairs already matching are left alone. Gain caps apply. It
+// returns the channels that were changed; on error, the channels changed
+// before the failure are returned.
+func (c *Card) NormalizeLinkedGain(mode GainNormalization) ([]int, error) {
+	channels, err := c.GetPreampChannels()
+	if err != nil {
+		return nil, err
+	}
+
+	byNumber := make(map[int]PreampChannel, len(channels))
+	for _, ch := range channels {
+		byNumber[ch.ChannelNum] = ch
+	}
+
+	var changed []int
+	for _, first := range channels {
+		second, ok := byNumber[first.ChannelNum+1]
+		if first.ChannelNum%2 == 0 || !ok || first.Gain == nil || second.Gain == nil {
+			continue
+		}
+		if !switchOn(first.Link) && !switchOn(first.GainLink) && !switchOn(second.Link) && !switchOn(second.GainLink) {
+			continue
+		}
+
+		firstGain, err := first.Gain.GetValue()
+		if err != nil {
+			return changed, fmt.Errorf("channel %d: %v", first.ChannelNum, err)
+		}
+		secondGain, err := second.Gain.GetValue()
+		if err != nil {
+			return changed, fmt.Errorf("channel %d: %v", second.ChannelNum, err)
+		}
+		if firstGain == secondGain {
+			continue
+		}
+
+		target := firstGain
+		if mode == NormalizeAverage {
+			target = averageGain(first.Gain, firstGain, secondGain)
+		}
+
+		for _, ch := range []struct {
+			num  int
+			gain int64
+		}{{first.ChannelNum, firstGain}, {second.ChannelNum, secondGain}} {
+			if ch.gain == target {
+				continue
+			}
+			if err := c.SetPreampGain(ch.num, target); err != nil {
+				return changed, err
+			}
+			changed = append(changed, ch.num)
+		}
+	}
+
+	return changed, nil
+}
+
+// switchOn reports whether a switch control exists and is on
+func switchOn(ctl *Control) bool {
+	enabled := boolState(ctl)
+	return enabled != nil && *enabled
+}
+
+// averageGain returns the mean of two gains, rounded to the nearest step of
+// the gain control
+func averageGain(ctl *Control, a, b int64) int64 {
+	step := ctl.Step
+	if step < 1 {
+		step = 1
+	}
+	steps := math.Round(float64(a+b-2*ctl.Min) / 2 / float64(step))
+	return ctl.Min + int64(steps)*step
+}
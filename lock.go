@@ -0,0 +1,100 @@
+package scarlettctl
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// State holds settings that persist between runs, such as which cards are
+// locked and the writes that can be undone
+type State struct {
+	Locked []string                `json:"locked,omitempty"` // keys of locked cards
+	Undo   map[string][]UndoRecord `json:"undo,omitempty"`   // undo stacks by card key, oldest first
+}
+
+// DefaultStatePath returns the default state file location (~/.config/scarlettctl/state.json)
+func DefaultStatePath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "scarlettctl", "state.json"), nil
+}
+
+// LoadState reads a state file. A missing file yields an empty state.
+func LoadState(path string) (*State, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &State{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	state := &State{}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("failed to parse state file '%s': %v", path, err)
+	}
+	return state, nil
+}
+
+// Save writes the state file, creating its directory if needed
+func (s *State) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// StateKey returns the key the card's entries in a State are stored under:
+// its ALSA id (e.g. "USB", or "USB_1" for a second identical interface), or
+// its name if the id can't be read
+func (c *Card) StateKey() string {
+	if id, err := getCardID(c.Number); err == nil && id != "" {
+		return id
+	}
+	return c.Name
+}
+
+// IsLocked reports whether the card with the given StateKey is locked
+func (s *State) IsLocked(cardKey string) bool {
+	for _, key := range s.Locked {
+		if key == cardKey {
+			return true
+		}
+	}
+	return false
+}
+
+// SetLocked locks or unlocks the card with the given StateKey
+func (s *State) SetLocked(cardKey string, locked bool) {
+	kept := s.Locked[:0]
+	for _, key := range s.Locked {
+		if key != cardKey {
+			kept = append(kept, key)
+		}
+	}
+	if locked {
+		kept = append(kept, cardKey)
+	}
+	s.Locked = kept
+}
+
+// SetLocked locks or unlocks writes to the card. While locked, every write
+// fails with ErrLocked; reads are unaffected.
+func (c *Card) SetLocked(locked bool) {
+	c.locked = locked
+}
+
+// IsLocked reports whether writes to the card are locked
+func (c *Card) IsLocked() bool {
+	return c.locked
+}
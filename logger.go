@@ -0,0 +1,17 @@
+package scarlettctl
+
+import "log/slog"
+
+// logger receives the package's operational detail: card opens, control
+// resolution, and every ALSA read and write. It discards everything until
+// SetLogger is called.
+var logger = slog.New(slog.DiscardHandler)
+
+// SetLogger sets the logger used for operational detail; most of it is logged
+// at debug level. A nil logger discards all output.
+func SetLogger(l *slog.Logger) {
+	if l == nil {
+		l = slog.New(slog.DiscardHandler)
+	}
+	logger = l
+}
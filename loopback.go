@@ -0,0 +1,99 @@
+package scarlettctl
+
+import (
+	"fmt"
+	"strings"
+)
+
+// GetLoopbackSinks returns the PCM capture sinks that carry loopback audio
+// back into the computer, in port order. Sinks named "Loopback" are used when
+// the driver labels them; otherwise the PCM capture channels beyond the card's
+// hardware inputs are the loopback channels (e.g. PCM 03-04 on a 2i2 4th gen).
+func (c *Card) GetLoopbackSinks() ([]RoutingSink, error) {
+	sinks, err := c.GetRoutingSinks()
+	if err != nil {
+		return nil, err
+	}
+
+	var pcm, named []RoutingSink
+	for _, sink := range sinks {
+		if sink.Category != PortCategoryPCM || !strings.Contains(sink.Name, "Capture") {
+			continue
+		}
+		pcm = append(pcm, sink)
+		if strings.Contains(sink.Name, "Loopback") {
+			named = append(named, sink)
+		}
+	}
+	if len(named) > 0 {
+		return named, nil
+	}
+
+	sources, err := c.GetRoutingSources()
+	if err != nil {
+		return nil, err
+	}
+
+	hwInputs := 0
+	for _, src := range sources {
+		if src.Category == PortCategoryHW {
+			hwInputs++
+		}
+	}
+
+	var loopback []RoutingSink
+	for _, sink := range pcm {
+		if sink.PortNum > hwInputs {
+			loopback = append(loopback, sink)
+		}
+	}
+	if len(loopback) == 0 {
+		return nil, fmt.Errorf("loopback: %w", ErrNotSupported)
+	}
+
+	return loopback, nil
+}
+
+// SetupLoopback routes a mix to the loopback capture sinks so it can be
+// recorded by the computer, e.g. to stream desktop audio mixed with a mic.
+// Mixes are treated as stereo pairs: the named mix feeds the first loopback
+// sink and the following mix (Mix B for Mix A) the second.
+func (c *Card) SetupLoopback(mix string) error {
+	mix = NormalizeMixName(mix)
+	if !strings.HasPrefix(mix, "Mix ") || len(mix) != len("Mix A") {
+		return fmt.Errorf("invalid mix '%s' (use e.g. A or \"Mix A\")", mix)
+	}
+
+	sinks, err := c.GetLoopbackSinks()
+	if err != nil {
+		return err
+	}
+
+	sources, err := c.GetRoutingSources()
+	if err != nil {
+		return err
+	}
+
+	sourceIDs := make(map[string]int)
+	for _, src := range sources {
+		sourceIDs[src.Name] = src.ID
+	}
+
+	letter := mix[len(mix)-1]
+	for i, sink := range sinks {
+		name := fmt.Sprintf("Mix %c", letter+byte(i))
+		id, ok := sourceIDs[name]
+		if !ok {
+			if i == 0 {
+				return fmt.Errorf("mix '%s' not found", name)
+			}
+			break // mono mix, nothing left to pair with
+		}
+
+		if err := sink.Control.SetValue(int64(id)); err != nil {
+			return fmt.Errorf("failed to route %s to %s: %v", name, sink.Name, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,169 @@
+// Package metrics exports a card's control values to Prometheus
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/michaelquigley/scarlettctl"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// DefaultInterval is how often control values are re-read by default
+const DefaultInterval = 5 * time.Second
+
+var (
+	controlLabels = []string{"card", "name", "numid", "index"}
+
+	valueDesc = prometheus.NewDesc(
+		"scarlett_control_value",
+		"Current value of an integer, boolean or enumerated control (meters included).",
+		controlLabels, nil)
+
+	stateDesc = prometheus.NewDesc(
+		"scarlett_control_state",
+		"Current item of an enumerated control, as the state label; always 1.",
+		append(controlLabels, "state"), nil)
+
+	lastReadDesc = prometheus.NewDesc(
+		"scarlett_last_read_timestamp_seconds",
+		"Time of the last successful read of the card's controls.",
+		[]string{"card"}, nil)
+
+	readErrorsDesc = prometheus.NewDesc(
+		"scarlett_read_errors_total",
+		"Number of failed reads of the card's controls.",
+		[]string{"card"}, nil)
+)
+
+// Collector is a Prometheus collector for a card's controls. Values are read
+// on an interval rather than per scrape, so frequent scrapes don't load the
+// device, and served from the last successful read.
+type Collector struct {
+	card     *scarlettctl.Card
+	interval time.Duration
+
+	mu       sync.Mutex
+	controls []*scarlettctl.Control
+	values   map[scarlettctl.ControlKey]int64
+	lastRead time.Time
+	errors   int
+
+	stop chan struct{}
+	once sync.Once
+}
+
+// NewCollector creates a collector reading the card every interval
+// (DefaultInterval when zero). Call Start to begin reading.
+func NewCollector(card *scarlettctl.Card, interval time.Duration) *Collector {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	return &Collector{card: card, interval: interval, stop: make(chan struct{})}
+}
+
+// Start reads the card once, then keeps reading it in the background until Stop
+func (c *Collector) Start() error {
+	if err := c.refresh(); err != nil {
+		return err
+	}
+
+	go func() {
+		ticker := time.NewTicker(c.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-c.stop:
+				return
+			case <-ticker.C:
+				c.refresh()
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop ends background reading
+func (c *Collector) Stop() {
+	c.once.Do(func() { close(c.stop) })
+}
+
+// refresh reads every control value, counting failures
+func (c *Collector) refresh() error {
+	controls, err := c.card.GetControls()
+	var values map[scarlettctl.ControlKey]int64
+	if err == nil {
+		values, err = c.card.ReadAllValues()
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err != nil {
+		c.errors++
+		return err
+	}
+	c.controls = controls
+	c.values = values
+	c.lastRead = time.Now()
+	return nil
+}
+
+// Describe implements prometheus.Collector
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- valueDesc
+	ch <- stateDesc
+	ch <- lastReadDesc
+	ch <- readErrorsDesc
+}
+
+// Collect implements prometheus.Collector
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	card := c.card.Name
+	ch <- prometheus.MustNewConstMetric(readErrorsDesc, prometheus.CounterValue, float64(c.errors), card)
+	if c.lastRead.IsZero() {
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(lastReadDesc, prometheus.GaugeValue, float64(c.lastRead.Unix()), card)
+
+	for _, ctl := range c.controls {
+		value, ok := c.values[ctl.Key()]
+		if !ok {
+			continue
+		}
+
+		labels := []string{card, ctl.Name, strconv.FormatUint(uint64(ctl.NumID), 10), strconv.Itoa(ctl.Index)}
+		switch ctl.Type {
+		case scarlettctl.ControlTypeBoolean, scarlettctl.ControlTypeInteger, scarlettctl.ControlTypeInteger64:
+			ch <- prometheus.MustNewConstMetric(valueDesc, prometheus.GaugeValue, float64(value), labels...)
+
+		case scarlettctl.ControlTypeEnumerated:
+			ch <- prometheus.MustNewConstMetric(valueDesc, prometheus.GaugeValue, float64(value), labels...)
+			ch <- prometheus.MustNewConstMetric(stateDesc, prometheus.GaugeValue, 1, append(labels, ctl.FormatValue(value))...)
+		}
+	}
+}
+
+// Serve reads the card every interval and serves its metrics on addr at
+// /metrics until the server fails
+func Serve(card *scarlettctl.Card, addr string, interval time.Duration) error {
+	collector := NewCollector(card, interval)
+	if err := collector.Start(); err != nil {
+		return err
+	}
+	defer collector.Stop()
+
+	registry := prometheus.NewRegistry()
+	if err := registry.Register(collector); err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+	return http.ListenAndServe(addr, mux)
+}
@@ -1,8 +1,13 @@
 package scarlettctl
 
 import (
+	"errors"
 	"fmt"
+	"math"
+	"os"
 	"regexp"
+	"strconv"
+	"strings"
 )
 
 // MixerInput represents a mixer input channel
@@ -12,6 +17,13 @@ type MixerInput struct {
 	Control   *Control
 }
 
+var (
+	// family of gen 2/3/4 mixer inputs, e.g. "Mix A Input"
+	mixInputFamilyRe = regexp.MustCompile(`^Mix ([A-Z]) Input$`)
+	// suffix of gen 1 mixer inputs, e.g. "Mix A Playback Volume"
+	matrixMixSuffixRe = regexp.MustCompile(`^Mix ([A-Z]) Playback Volume$`)
+)
+
 // GetMixerInputs returns all mixer input volume controls
 func (c *Card) GetMixerInputs() ([]MixerInput, error) {
 	controls, err := c.GetControls()
@@ -21,39 +33,31 @@ func (c *Card) GetMixerInputs() ([]MixerInput, error) {
 
 	var inputs []MixerInput
 
-	// gen 2/3/4 pattern: "Mix A Input 01 Playback Volume"
-	// gen 1 pattern: "Matrix 01 Mix A Playback Volume"
-	gen234Re := regexp.MustCompile(`^Mix ([A-Z]) Input (\d+) Playback Volume$`)
-	gen1Re := regexp.MustCompile(`^Matrix (\d+) Mix ([A-Z]) Playback Volume$`)
-
 	for _, ctl := range controls {
 		if ctl.Type != ControlTypeInteger {
 			continue
 		}
 
-		// try gen 2/3/4 pattern
-		if matches := gen234Re.FindStringSubmatch(ctl.Name); matches != nil {
-			mixName := "Mix " + matches[1]
-			var inputNum int
-			fmt.Sscanf(matches[2], "%d", &inputNum)
+		name, ok := ParseControlName(ctl.Name)
+		if !ok {
+			continue
+		}
 
+		// gen 2/3/4 pattern: "Mix A Input 01 Playback Volume"
+		if matches := mixInputFamilyRe.FindStringSubmatch(name.Family); matches != nil && name.Suffix == "Playback Volume" {
 			inputs = append(inputs, MixerInput{
-				MixName:  mixName,
-				InputNum: inputNum,
+				MixName:  "Mix " + matches[1],
+				InputNum: name.ChannelNum,
 				Control:  ctl,
 			})
 			continue
 		}
 
-		// try gen 1 pattern
-		if matches := gen1Re.FindStringSubmatch(ctl.Name); matches != nil {
-			var inputNum int
-			fmt.Sscanf(matches[1], "%d", &inputNum)
-			mixName := "Mix " + matches[2]
-
+		// gen 1 pattern: "Matrix 01 Mix A Playback Volume"
+		if matches := matrixMixSuffixRe.FindStringSubmatch(name.Suffix); matches != nil && name.Family == "Matrix" {
 			inputs = append(inputs, MixerInput{
-				MixName:  mixName,
-				InputNum: inputNum,
+				MixName:  "Mix " + matches[1],
+				InputNum: name.ChannelNum,
 				Control:  ctl,
 			})
 		}
@@ -98,40 +102,238 @@ func (c *Card) GetMixerLevel(mixName string, inputNum int) (int64, error) {
 	return ctl.GetValue()
 }
 
+// SetMixerLevelDB sets a mixer input to the level nearest a dB send, clamped
+// to the control's range, and returns the dB actually applied
+func (c *Card) SetMixerLevelDB(mixName string, inputNum int, db float64) (float64, error) {
+	ctl, err := c.GetMixerInput(mixName, inputNum)
+	if err != nil {
+		return 0, err
+	}
+
+	level, err := MixerLevelFromDB(ctl, db)
+	if err != nil {
+		return 0, err
+	}
+	if err := ctl.SetValue(level); err != nil {
+		return 0, err
+	}
+	return MixerLevelToDB(ctl, level)
+}
+
+// GetMixerLevelDB gets a mixer input level in dB
+func (c *Card) GetMixerLevelDB(mixName string, inputNum int) (float64, error) {
+	ctl, err := c.GetMixerInput(mixName, inputNum)
+	if err != nil {
+		return 0, err
+	}
+
+	level, err := ctl.GetValue()
+	if err != nil {
+		return 0, err
+	}
+	return MixerLevelToDB(ctl, level)
+}
+
+// scarlett2 mixer volumes run from -80 dB in 0.5 dB steps
+const (
+	mixerMinDB  = -80.0
+	mixerStepDB = 0.5
+)
+
+// mixerDecibelScale returns the dB level of a mixer control's minimum value and
+// the dB step per value, from the control's TLV or, for a control without one,
+// the scarlett2 mixer scale
+func mixerDecibelScale(ctl *Control) (min, step float64, err error) {
+	min, _, step, err = ctl.DecibelRange()
+	if errors.Is(err, ErrNotSupported) {
+		return mixerMinDB, mixerStepDB, nil
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+	if step == 0 {
+		return 0, 0, fmt.Errorf("%s has no uniform dB steps: %w", ctl.Name, ErrNotSupported)
+	}
+	return min, step, nil
+}
+
+// MixerLevelToDB converts a raw mixer level to dB
+func MixerLevelToDB(ctl *Control, level int64) (float64, error) {
+	min, step, err := mixerDecibelScale(ctl)
+	if err != nil {
+		return 0, err
+	}
+	return min + float64(level-ctl.Min)*step, nil
+}
+
+// MixerLevelFromDB converts a dB level to the nearest raw mixer level, clamped
+// to the control's range
+func MixerLevelFromDB(ctl *Control, db float64) (int64, error) {
+	min, step, err := mixerDecibelScale(ctl)
+	if err != nil {
+		return 0, err
+	}
+	return ctl.clamp(ctl.Min + int64(math.Round((db-min)/step))), nil
+}
+
+// NormalizeMixName accepts "A", "a", or "Mix A" and returns "Mix A"
+func NormalizeMixName(mixName string) string {
+	mixName = strings.TrimSpace(mixName)
+	if len(mixName) == 1 {
+		return "Mix " + strings.ToUpper(mixName)
+	}
+	return mixName
+}
+
+// SetMixLevel sets every input of the named mix to the same level
+// It returns the number of inputs that were set.
+func (c *Card) SetMixLevel(mixName string, level int64) (int, error) {
+	inputs, err := c.GetMixerInputs()
+	if err != nil {
+		return 0, err
+	}
+
+	mixName = NormalizeMixName(mixName)
+	count := 0
+	for _, input := range inputs {
+		if input.MixName != mixName {
+			continue
+		}
+
+		if err := input.Control.SetValue(level); err != nil {
+			return count, fmt.Errorf("input %02d: %v", input.InputNum, err)
+		}
+		count++
+	}
+
+	if count == 0 {
+		return 0, fmt.Errorf("mix '%s' not found", mixName)
+	}
+
+	return count, nil
+}
+
+// MixerUnityLevel returns the raw value for unity gain (0 dB) on a mixer input control
+func MixerUnityLevel(ctl *Control) int64 {
+	return ctl.clamp(int64(-mixerMinDB / mixerStepDB))
+}
+
+// ParseMixerLevel parses a mixer level for a control: a raw value, a percentage
+// of the control's range (e.g. "50%"), a dB level (e.g. "-6dB", clamped to the
+// range), or "unity"/"0dB" for unity gain
+func ParseMixerLevel(ctl *Control, s string) (int64, error) {
+	s = strings.TrimSpace(strings.ToLower(s))
+
+	switch s {
+	case "unity", "0db":
+		return MixerUnityLevel(ctl), nil
+	}
+
+	if strings.HasSuffix(s, "db") {
+		db, err := strconv.ParseFloat(strings.TrimSpace(strings.TrimSuffix(s, "db")), 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid dB level: %s", s)
+		}
+		return MixerLevelFromDB(ctl, db)
+	}
+
+	if strings.HasSuffix(s, "%") {
+		percent, err := strconv.ParseFloat(strings.TrimSuffix(s, "%"), 64)
+		if err != nil || percent < 0 || percent > 100 {
+			return 0, fmt.Errorf("invalid percentage: %s", s)
+		}
+		return ctl.Min + int64(math.Round(float64(ctl.Max-ctl.Min)*percent/100)), nil
+	}
+
+	level, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid level: %s (use a number, a percentage, a dB level, or 'unity')", s)
+	}
+	return level, nil
+}
+
+// GetMixerInputLabels resolves each mixer input port (1-based) to the name of
+// the source currently routed to it, e.g. 1 -> "Analogue 1". Inputs routed to
+// "Off" are left out. Gen 1 cards without mixer input routing yield an empty map.
+func (c *Card) GetMixerInputLabels() (map[int]string, error) {
+	labels := make(map[int]string)
+
+	sinks, err := c.GetRoutingSinks()
+	if errors.Is(err, ErrNotSupported) {
+		return labels, nil // no routing controls to resolve from
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, sink := range sinks {
+		if sink.Category != PortCategoryMix {
+			continue
+		}
+		value, err := sink.Control.GetValue()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read routing for %s: %w", sink.Name, err)
+		}
+		if sink.Control.isOffValue(value) {
+			continue
+		}
+		labels[sink.PortNum] = sink.Control.FormatValue(value)
+	}
+
+	return labels, nil
+}
+
 // PrintMixerState prints the current state of all mixer inputs
 func (c *Card) PrintMixerState() error {
+	return c.RenderMixerState(NewRenderer(os.Stdout))
+}
+
+// RenderMixerState writes the current state of all mixer inputs using the renderer
+func (c *Card) RenderMixerState(r *Renderer) error {
 	inputs, err := c.GetMixerInputs()
 	if err != nil {
 		return err
 	}
 
 	if len(inputs) == 0 {
-		fmt.Println("no mixer controls found")
+		r.Line("no mixer controls found")
 		return nil
 	}
 
-	fmt.Println("\nmixer state:")
-	fmt.Println("============")
+	labels, err := c.GetMixerInputLabels()
+	if err != nil {
+		return err
+	}
+
+	r.Printf("\nmixer state:\n")
+	r.Line("============")
+	if note := c.rateNote(); note != "" {
+		r.Line(note)
+	}
 
 	currentMix := ""
 	for _, input := range inputs {
 		if input.MixName != currentMix {
 			if currentMix != "" {
-				fmt.Println()
+				r.Printf("\n")
 			}
-			fmt.Printf("%s:\n", input.MixName)
+			r.Printf("%s:\n", r.Category(PortCategoryMix, input.MixName))
 			currentMix = input.MixName
 		}
 
 		value, err := input.Control.GetValue()
 		if err != nil {
-			fmt.Printf("  input %02d: error - %v\n", input.InputNum, err)
+			r.Line(fmt.Sprintf("  input %02d: error - %v", input.InputNum, err))
 			continue
 		}
 
-		// show value and range
-		fmt.Printf("  input %02d: %5d [%d..%d]\n",
+		// show value and range, then the source feeding the input
+		line := fmt.Sprintf("  input %02d: %5d [%d..%d]",
 			input.InputNum, value, input.Control.Min, input.Control.Max)
+		if label, ok := labels[input.InputNum]; ok {
+			line += fmt.Sprintf(" (%s)", label)
+		}
+		r.Line(line)
 	}
 
 	return nil
@@ -0,0 +1,54 @@
+package scarlettctl
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestGetMixerInputLabels(t *testing.T) {
+	sources := []string{"Off", "Analogue 1", "PCM 1"}
+	card, dev := newFakeCard(t,
+		fakeElement{numid: 1, name: "Mixer Input 01 Capture Enum", typ: ControlTypeEnumerated, max: 2, items: sources, values: []int64{1}},
+		fakeElement{numid: 2, name: "Mixer Input 02 Capture Enum", typ: ControlTypeEnumerated, max: 2, items: sources, values: []int64{0}},
+		fakeElement{numid: 3, name: "Mixer Input 03 Capture Enum", typ: ControlTypeEnumerated, max: 2, items: sources, values: []int64{2}},
+	)
+
+	labels, err := card.GetMixerInputLabels()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[int]string{1: "Analogue 1", 3: "PCM 1"}; !reflect.DeepEqual(labels, want) {
+		t.Errorf("GetMixerInputLabels() = %v, want %v", labels, want)
+	}
+
+	dev.readErr = ErrDeviceDisconnected
+	if _, err := card.GetMixerInputLabels(); !errors.Is(err, ErrDeviceDisconnected) {
+		t.Errorf("expected a read failure wrapping ErrDeviceDisconnected, got %v", err)
+	}
+}
+
+func TestGetMixerInputLabelsWithoutRouting(t *testing.T) {
+	card, _ := newFakeCard(t,
+		fakeElement{numid: 1, name: "Mix A Input 01 Playback Volume", typ: ControlTypeInteger, max: 172},
+	)
+
+	labels, err := card.GetMixerInputLabels()
+	if err != nil {
+		t.Fatalf("a card without routing should have no labels, got %v", err)
+	}
+	if len(labels) != 0 {
+		t.Errorf("GetMixerInputLabels() = %v, want none", labels)
+	}
+}
+
+func TestGetMixerInputLabelsClosed(t *testing.T) {
+	card, _ := newFakeCard(t,
+		fakeElement{numid: 1, name: "Mixer Input 01 Capture Enum", typ: ControlTypeEnumerated, max: 1, items: []string{"Off", "PCM 1"}},
+	)
+	card.Close()
+
+	if _, err := card.GetMixerInputLabels(); !errors.Is(err, ErrClosed) {
+		t.Errorf("expected ErrClosed, got %v", err)
+	}
+}
@@ -0,0 +1,93 @@
+package scarlettctl
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ControlName is a control name split according to the common Scarlett naming
+// scheme of "<family> <channel>[-<channel>] <suffix>", e.g.
+//
+//	"Line In 1 Gain Capture Volume"       -> Line In, 1, Gain Capture Volume
+//	"Line In 1-2 Link Capture Switch"     -> Line In, 1 (to 2), Link Capture Switch
+//	"Mix A Input 01 Playback Volume"      -> Mix A Input, 1, Playback Volume
+//	"Analogue Output 03 Playback Enum"    -> Analogue Output, 3, Playback Enum
+//
+// Routing source names such as "PCM 4" or "S/PDIF 1" parse the same way with
+// an empty suffix.
+type ControlName struct {
+	Family     string // words before the channel number
+	ChannelNum int    // first (or only) channel number
+	ChannelEnd int    // last channel of a "1-2" pair, zero when not a pair
+	Suffix     string // words after the channel number
+}
+
+// controlNameRe splits a name at its first channel number
+var controlNameRe = regexp.MustCompile(`^(.*?) ?(\d+)(?:-(\d+))?(?: (.*))?$`)
+
+// ParseControlName splits a control name into family, channel and suffix. It
+// reports false for names without a channel number, such as "Level Meter".
+func ParseControlName(name string) (ControlName, bool) {
+	matches := controlNameRe.FindStringSubmatch(name)
+	if matches == nil || matches[1] == "" {
+		return ControlName{}, false
+	}
+
+	// the regex guarantees digits, but a number too long for an int must not
+	// become channel 0
+	parsed := ControlName{Family: matches[1], Suffix: matches[4]}
+	var err error
+	if parsed.ChannelNum, err = strconv.Atoi(matches[2]); err != nil {
+		return ControlName{}, false
+	}
+	if matches[3] != "" {
+		if parsed.ChannelEnd, err = strconv.Atoi(matches[3]); err != nil {
+			return ControlName{}, false
+		}
+	}
+	return parsed, true
+}
+
+// IsPair reports whether the name covers a channel pair, e.g. "Line In 1-2"
+func (n ControlName) IsPair() bool {
+	return n.ChannelEnd != 0
+}
+
+// String reassembles the name, without leading zeros on channel numbers
+func (n ControlName) String() string {
+	s := n.Family + " " + strconv.Itoa(n.ChannelNum)
+	if n.IsPair() {
+		s += "-" + strconv.Itoa(n.ChannelEnd)
+	}
+	if n.Suffix != "" {
+		s += " " + n.Suffix
+	}
+	return s
+}
+
+// NormalizeControlName returns a canonical form of a control name for
+// comparison: lower case, single spaces, and no leading zeros on channel
+// numbers, so "Mix A Input 01 Playback Volume" and "mix a  input 1 playback
+// volume" normalize to the same string.
+func NormalizeControlName(name string) string {
+	fields := strings.Fields(strings.ToLower(name))
+	for i, field := range fields {
+		fields[i] = trimLeadingZeros(field)
+	}
+	return strings.Join(fields, " ")
+}
+
+// trimLeadingZeros strips leading zeros from an all-digit word
+func trimLeadingZeros(word string) string {
+	for _, r := range word {
+		if r < '0' || r > '9' {
+			return word
+		}
+	}
+	trimmed := strings.TrimLeft(word, "0")
+	if trimmed == "" {
+		return "0"
+	}
+	return trimmed
+}
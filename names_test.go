@@ -0,0 +1,103 @@
+package scarlettctl
+
+import "testing"
+
+func TestParseControlName(t *testing.T) {
+	tests := []struct {
+		name string
+		want ControlName
+		ok   bool
+	}{
+		// preamp controls
+		{"Line In 1 Gain Capture Volume", ControlName{"Line In", 1, 0, "Gain Capture Volume"}, true},
+		{"Line In 2 Pad Capture Switch", ControlName{"Line In", 2, 0, "Pad Capture Switch"}, true},
+		{"Line In 1 Air Capture Enum", ControlName{"Line In", 1, 0, "Air Capture Enum"}, true},
+		{"Line In 1 Level Capture Enum", ControlName{"Line In", 1, 0, "Level Capture Enum"}, true},
+		{"Line In 1 Autogain Status Capture Enum", ControlName{"Line In", 1, 0, "Autogain Status Capture Enum"}, true},
+		{"Line In 1-2 Link Capture Switch", ControlName{"Line In", 1, 2, "Link Capture Switch"}, true},
+		{"Line In 3-4 Phantom Power Capture Switch", ControlName{"Line In", 3, 4, "Phantom Power Capture Switch"}, true},
+
+		// outputs and mixer
+		{"Line 01 (Monitor L) Playback Volume", ControlName{"Line", 1, 0, "(Monitor L) Playback Volume"}, true},
+		{"Line 03 Mute Playback Switch", ControlName{"Line", 3, 0, "Mute Playback Switch"}, true},
+		{"Mix A Input 01 Playback Volume", ControlName{"Mix A Input", 1, 0, "Playback Volume"}, true},
+		{"Mix H Input 25 Playback Volume", ControlName{"Mix H Input", 25, 0, "Playback Volume"}, true},
+		{"Monitor 1 Mix A Input 01 Playback Volume", ControlName{"Monitor", 1, 0, "Mix A Input 01 Playback Volume"}, true},
+
+		// routing sinks
+		{"Analogue Output 01 Playback Enum", ControlName{"Analogue Output", 1, 0, "Playback Enum"}, true},
+		{"Analogue Output 10 Playback Enum", ControlName{"Analogue Output", 10, 0, "Playback Enum"}, true},
+		{"S/PDIF Output 1 Playback Enum", ControlName{"S/PDIF Output", 1, 0, "Playback Enum"}, true},
+		{"ADAT Output 8 Playback Enum", ControlName{"ADAT Output", 8, 0, "Playback Enum"}, true},
+		{"PCM 01 Capture Enum", ControlName{"PCM", 1, 0, "Capture Enum"}, true},
+		{"Mixer Input 12 Capture Enum", ControlName{"Mixer Input", 12, 0, "Capture Enum"}, true},
+		{"DSP Input 2 Capture Enum", ControlName{"DSP Input", 2, 0, "Capture Enum"}, true},
+
+		// routing sources have no suffix
+		{"PCM 4", ControlName{"PCM", 4, 0, ""}, true},
+		{"S/PDIF 1", ControlName{"S/PDIF", 1, 0, ""}, true},
+		{"Analogue 1", ControlName{"Analogue", 1, 0, ""}, true},
+
+		// names without a channel number
+		{"Level Meter", ControlName{}, false},
+		{"Master HW Playback Volume", ControlName{}, false},
+		{"Direct Monitor Playback Switch", ControlName{}, false},
+		{"Speaker Switching Playback Enum", ControlName{}, false},
+		{"Input Select Capture Enum", ControlName{}, false},
+		{"Firmware Version", ControlName{}, false},
+		{"Mix A", ControlName{}, false},
+		{"Off", ControlName{}, false},
+		{"", ControlName{}, false},
+
+		// a number with nothing before it isn't a channel
+		{"1 Gain Capture Volume", ControlName{}, false},
+		// nor is one too long for an int
+		{"Line In 99999999999999999999 Gain Capture Volume", ControlName{}, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := ParseControlName(tt.name)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("ParseControlName(%q) = %+v, %v; want %+v, %v", tt.name, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestControlNameString(t *testing.T) {
+	tests := []struct {
+		name, want string
+	}{
+		{"Line In 1 Gain Capture Volume", "Line In 1 Gain Capture Volume"},
+		{"Line In 1-2 Link Capture Switch", "Line In 1-2 Link Capture Switch"},
+		{"Mix A Input 01 Playback Volume", "Mix A Input 1 Playback Volume"},
+		{"PCM 04", "PCM 4"},
+	}
+
+	for _, tt := range tests {
+		parsed, ok := ParseControlName(tt.name)
+		if !ok {
+			t.Fatalf("ParseControlName(%q) failed", tt.name)
+		}
+		if got := parsed.String(); got != tt.want {
+			t.Errorf("%q: String() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeControlName(t *testing.T) {
+	tests := []struct {
+		name, want string
+	}{
+		{"Mix A Input 01 Playback Volume", "mix a input 1 playback volume"},
+		{"mix a  input 1 playback volume", "mix a input 1 playback volume"},
+		{"Analogue Output 00 Playback Enum", "analogue output 0 playback enum"},
+		{"Line 01 (Monitor L) Playback Volume", "line 1 (monitor l) playback volume"},
+		{"S/PDIF 01", "s/pdif 1"},
+	}
+
+	for _, tt := range tests {
+		if got := NormalizeControlName(tt.name); got != tt.want {
+			t.Errorf("NormalizeControlName(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package scarlettctl
+
+import (
+	"fmt"
+	"strings"
+)
+
+// GetPCMMapping correlates the card's PCM channels with the hardware routing.
+// Capture channels report the source routed to them; playback channels report
+// every sink they currently feed. Channels are numbered from 1, matching the
+// ports the DAW, PipeWire or JACK sees (capture_1, playback_1, ...).
+func (c *Card) GetPCMMapping() ([]PCMChannel, error) {
+	sinks, err := c.GetRoutingSinks()
+	if err != nil {
+		return nil, err
+	}
+
+	sources, err := c.GetRoutingSources()
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[int][]RoutingSink)
+	var mapping []PCMChannel
+
+	for _, sink := range sinks {
+		value, err := sink.Control.GetValue()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read routing for %s: %v", sink.Name, err)
+		}
+		values[int(value)] = append(values[int(value)], sink)
+
+		if sink.Category != PortCategoryPCM || !strings.Contains(sink.Name, "Capture") {
+			continue
+		}
+
+		channel := PCMChannel{
+			Direction: PCMCapture,
+			Channel:   sink.PortNum,
+			Port:      sink.Name,
+		}
+		if !sink.Control.isOffValue(value) {
+			channel.Routes = []string{sink.Control.FormatValue(value)}
+		}
+		mapping = append(mapping, channel)
+	}
+
+	for _, src := range sources {
+		if src.Category != PortCategoryPCM {
+			continue
+		}
+
+		channel := PCMChannel{
+			Direction: PCMPlayback,
+			Channel:   src.PortNum + 1,
+			Port:      src.Name,
+		}
+		for _, sink := range values[src.ID] {
+			channel.Routes = append(channel.Routes, shortSinkName(sink.Name))
+		}
+		mapping = append(mapping, channel)
+	}
+
+	if len(mapping) == 0 {
+		return nil, fmt.Errorf("pcm mapping: %w", ErrNotSupported)
+	}
+
+	return mapping, nil
+}
@@ -1,8 +1,13 @@
 package scarlettctl
 
 import (
+	"errors"
 	"fmt"
+	"os"
 	"regexp"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // PreampChannel represents a preamp input channel with all its controls
@@ -14,9 +19,66 @@ type PreampChannel struct {
 	Pad           *Control
 	Impedance     *Control
 	Level         *Control
+	InputSelect   *Control // input source select (e.g. Mic/Line/Inst)
 	Autogain      *Control
 	Safe          *Control
 	Link          *Control
+	GainHalo      *Control // 4th gen gain halo brightness/enable
+	GainLink      *Control // 4th gen gain link for a stereo pair
+}
+
+// PreampState is a serializable snapshot of a preamp channel's resolved values
+// Fields are omitted when the channel has no such control.
+type PreampState struct {
+	Channel   int      `json:"channel"`
+	Gain      *int64   `json:"gain,omitempty"`
+	GainDB    *float64 `json:"gain_db,omitempty"`
+	Phantom   *bool    `json:"phantom,omitempty"`
+	Air       string   `json:"air,omitempty"`
+	Pad       *bool    `json:"pad,omitempty"`
+	Impedance *bool    `json:"impedance,omitempty"`
+	Level     string   `json:"level,omitempty"`
+	Input     string   `json:"input,omitempty"`
+	Autogain  *bool    `json:"autogain,omitempty"`
+	Safe      *bool    `json:"safe,omitempty"`
+	Link      *bool    `json:"link,omitempty"`
+	GainHalo  string   `json:"gain_halo,omitempty"`
+	GainLink  *bool    `json:"gain_link,omitempty"`
+}
+
+// scarlett2 preamp gain runs in 1 dB steps starting at -1 dB
+const (
+	preampGainMinDB  = -1.0
+	preampGainStepDB = 1.0
+)
+
+// preampGainRe matches the suffix of a "Line In <n>" gain control
+var preampGainRe = regexp.MustCompile(`^Gain Capture Volume$`)
+
+// preampPhantomRe matches the suffix of a "Line In <n>" phantom power control
+var preampPhantomRe = regexp.MustCompile(`^Phantom Power Capture Switch$`)
+
+// preampFields maps the suffixes of "Line In <n>" controls to channel fields
+// Controls named for a channel pair ("Line In 1-2 ...") attach to the first
+// channel of the pair.
+var preampFields = []struct {
+	suffix   *regexp.Regexp
+	pair     bool // may be named for a channel pair
+	pairOnly bool // is always named for a channel pair
+	set      func(ch *PreampChannel, ctl *Control)
+}{
+	{preampGainRe, false, false, func(ch *PreampChannel, ctl *Control) { ch.Gain = ctl }},
+	{preampPhantomRe, true, false, func(ch *PreampChannel, ctl *Control) { ch.Phantom = ctl }},
+	{regexp.MustCompile(`^Air Capture (?:Switch|Enum)$`), false, false, func(ch *PreampChannel, ctl *Control) { ch.Air = ctl }},
+	{regexp.MustCompile(`^Pad Capture Switch$`), false, false, func(ch *PreampChannel, ctl *Control) { ch.Pad = ctl }},
+	{regexp.MustCompile(`^Impedance Switch$`), false, false, func(ch *PreampChannel, ctl *Control) { ch.Impedance = ctl }},
+	{regexp.MustCompile(`^Level Capture Enum$`), false, false, func(ch *PreampChannel, ctl *Control) { ch.Level = ctl }},
+	{regexp.MustCompile(`^Input Select Capture Enum$`), false, false, func(ch *PreampChannel, ctl *Control) { ch.InputSelect = ctl }},
+	{regexp.MustCompile(`^Autogain Capture Switch$`), false, false, func(ch *PreampChannel, ctl *Control) { ch.Autogain = ctl }},
+	{regexp.MustCompile(`^Safe Capture Switch$`), false, false, func(ch *PreampChannel, ctl *Control) { ch.Safe = ctl }},
+	{regexp.MustCompile(`^Link Capture Switch$`), true, true, func(ch *PreampChannel, ctl *Control) { ch.Link = ctl }},
+	{regexp.MustCompile(`^Gain Halos? (?:Level )?Capture (?:Switch|Enum)$`), false, false, func(ch *PreampChannel, ctl *Control) { ch.GainHalo = ctl }},
+	{regexp.MustCompile(`^Gain Link Capture Switch$`), true, false, func(ch *PreampChannel, ctl *Control) { ch.GainLink = ctl }},
 }
 
 // GetPreampChannels returns all preamp channels with their controls
@@ -29,74 +91,25 @@ func (c *Card) GetPreampChannels() ([]PreampChannel, error) {
 	// build a map of channel number -> controls
 	channelMap := make(map[int]*PreampChannel)
 
-	gainRe := regexp.MustCompile(`^Line In (\d+) Gain Capture Volume$`)
-	phantomRe := regexp.MustCompile(`^Line In (\d+)(?:-\d+)? Phantom Power Capture Switch$`)
-	airRe := regexp.MustCompile(`^Line In (\d+) Air Capture (?:Switch|Enum)$`)
-	padRe := regexp.MustCompile(`^Line In (\d+) Pad Capture Switch$`)
-	impedanceRe := regexp.MustCompile(`^Line In (\d+) Impedance Switch$`)
-	levelRe := regexp.MustCompile(`^Line In (\d+) Level Capture Enum$`)
-	autogainRe := regexp.MustCompile(`^Line In (\d+) Autogain Capture Switch$`)
-	safeRe := regexp.MustCompile(`^Line In (\d+) Safe Capture Switch$`)
-	linkRe := regexp.MustCompile(`^Line In (\d+)-\d+ Link Capture Switch$`)
-
 	for _, ctl := range controls {
-		var channelNum int
+		channelNum, suffix, pair, ok := preampControlChannel(ctl)
+		if !ok {
+			continue
+		}
 
-		// match against each pattern
-		if matches := gainRe.FindStringSubmatch(ctl.Name); matches != nil {
-			fmt.Sscanf(matches[1], "%d", &channelNum)
-			if _, exists := channelMap[channelNum]; !exists {
-				channelMap[channelNum] = &PreampChannel{ChannelNum: channelNum}
+		for _, field := range preampFields {
+			if !field.suffix.MatchString(suffix) {
+				continue
 			}
-			channelMap[channelNum].Gain = ctl
-		} else if matches := phantomRe.FindStringSubmatch(ctl.Name); matches != nil {
-			fmt.Sscanf(matches[1], "%d", &channelNum)
-			if _, exists := channelMap[channelNum]; !exists {
-				channelMap[channelNum] = &PreampChannel{ChannelNum: channelNum}
-			}
-			channelMap[channelNum].Phantom = ctl
-		} else if matches := airRe.FindStringSubmatch(ctl.Name); matches != nil {
-			fmt.Sscanf(matches[1], "%d", &channelNum)
-			if _, exists := channelMap[channelNum]; !exists {
-				channelMap[channelNum] = &PreampChannel{ChannelNum: channelNum}
-			}
-			channelMap[channelNum].Air = ctl
-		} else if matches := padRe.FindStringSubmatch(ctl.Name); matches != nil {
-			fmt.Sscanf(matches[1], "%d", &channelNum)
-			if _, exists := channelMap[channelNum]; !exists {
-				channelMap[channelNum] = &PreampChannel{ChannelNum: channelNum}
-			}
-			channelMap[channelNum].Pad = ctl
-		} else if matches := impedanceRe.FindStringSubmatch(ctl.Name); matches != nil {
-			fmt.Sscanf(matches[1], "%d", &channelNum)
-			if _, exists := channelMap[channelNum]; !exists {
-				channelMap[channelNum] = &PreampChannel{ChannelNum: channelNum}
-			}
-			channelMap[channelNum].Impedance = ctl
-		} else if matches := levelRe.FindStringSubmatch(ctl.Name); matches != nil {
-			fmt.Sscanf(matches[1], "%d", &channelNum)
-			if _, exists := channelMap[channelNum]; !exists {
-				channelMap[channelNum] = &PreampChannel{ChannelNum: channelNum}
-			}
-			channelMap[channelNum].Level = ctl
-		} else if matches := autogainRe.FindStringSubmatch(ctl.Name); matches != nil {
-			fmt.Sscanf(matches[1], "%d", &channelNum)
-			if _, exists := channelMap[channelNum]; !exists {
-				channelMap[channelNum] = &PreampChannel{ChannelNum: channelNum}
-			}
-			channelMap[channelNum].Autogain = ctl
-		} else if matches := safeRe.FindStringSubmatch(ctl.Name); matches != nil {
-			fmt.Sscanf(matches[1], "%d", &channelNum)
-			if _, exists := channelMap[channelNum]; !exists {
-				channelMap[channelNum] = &PreampChannel{ChannelNum: channelNum}
+			if pair && !field.pair || !pair && field.pairOnly {
+				continue
 			}
-			channelMap[channelNum].Safe = ctl
-		} else if matches := linkRe.FindStringSubmatch(ctl.Name); matches != nil {
-			fmt.Sscanf(matches[1], "%d", &channelNum)
+
 			if _, exists := channelMap[channelNum]; !exists {
 				channelMap[channelNum] = &PreampChannel{ChannelNum: channelNum}
 			}
-			channelMap[channelNum].Link = ctl
+			field.set(channelMap[channelNum], ctl)
+			break
 		}
 	}
 
@@ -111,6 +124,177 @@ func (c *Card) GetPreampChannels() ([]PreampChannel, error) {
 	return channels, nil
 }
 
+// preampControlChannel returns the channel a preamp control index belongs to,
+// the name suffix that selects its field, and whether it is named for a channel
+// pair. Some devices expose a switch such as phantom power as one element with
+// an index per channel rather than an element per channel, either named for
+// the channel range ("Line In 1-2 Phantom Power Capture Switch" with two
+// values) or for no channel at all ("Phantom Power Capture Switch" with one
+// value per input); each index then maps to its own channel, so setters write
+// the right index.
+func preampControlChannel(ctl *Control) (channelNum int, suffix string, pair, ok bool) {
+	name, parsed := ParseControlName(ctl.Name)
+	switch {
+	case !parsed:
+		if ctl.Count > 1 {
+			return ctl.Index + 1, ctl.Name, false, true
+		}
+		return 0, "", false, false
+	case name.Family != "Line In":
+		return 0, "", false, false
+	case name.IsPair() && ctl.Count > 1 && ctl.Count == name.ChannelEnd-name.ChannelNum+1:
+		return name.ChannelNum + ctl.Index, name.Suffix, false, true
+	case ctl.Index > 0:
+		// further values of a single channel's control
+		return 0, "", false, false
+	default:
+		return name.ChannelNum, name.Suffix, name.IsPair(), true
+	}
+}
+
+// preampGainChannel returns the channel whose gain a control sets, if it is a
+// preamp gain control
+func preampGainChannel(ctl *Control) (int, bool) {
+	channelNum, suffix, pair, ok := preampControlChannel(ctl)
+	if !ok || pair || !preampGainRe.MatchString(suffix) {
+		return 0, false
+	}
+	return channelNum, true
+}
+
+// GetPreampState returns the resolved state of every preamp channel
+func (c *Card) GetPreampState() ([]PreampState, error) {
+	channels, err := c.GetPreampChannels()
+	if err != nil {
+		return nil, err
+	}
+
+	states := make([]PreampState, 0, len(channels))
+	for _, ch := range channels {
+		state := PreampState{Channel: ch.ChannelNum}
+
+		if ch.Gain != nil {
+			if value, err := ch.Gain.GetValue(); err == nil {
+				state.Gain = &value
+				if min, step, err := preampDecibelScale(ch.Gain); err == nil {
+					db := min + float64(value-ch.Gain.Min)*step
+					state.GainDB = &db
+				}
+			}
+		}
+
+		state.Phantom = boolState(ch.Phantom)
+		state.Air = stringState(ch.Air)
+		state.Pad = boolState(ch.Pad)
+		state.Impedance = boolState(ch.Impedance)
+		state.Level = stringState(ch.Level)
+		state.Input, _ = inputModeState(&ch)
+		state.Autogain = boolState(ch.Autogain)
+		state.Safe = boolState(ch.Safe)
+		state.Link = boolState(ch.Link)
+		state.GainHalo = stringState(ch.GainHalo)
+		state.GainLink = boolState(ch.GainLink)
+
+		states = append(states, state)
+	}
+
+	return states, nil
+}
+
+// preampDecibelScale returns the dB level of a gain control's minimum value and
+// the dB step per value, from the control's TLV or, for a control without one,
+// the scarlett2 preamp scale
+func preampDecibelScale(ctl *Control) (min, step float64, err error) {
+	min, _, step, err = ctl.DecibelRange()
+	if errors.Is(err, ErrNotSupported) {
+		return preampGainMinDB, preampGainStepDB, nil
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+	if step == 0 {
+		return 0, 0, fmt.Errorf("%s has no uniform dB steps: %w", ctl.Name, ErrNotSupported)
+	}
+	return min, step, nil
+}
+
+// boolState reads a switch control as a bool, or nil if it is missing or unreadable
+func boolState(ctl *Control) *bool {
+	if ctl == nil {
+		return nil
+	}
+	value, err := ctl.GetValue()
+	if err != nil {
+		return nil
+	}
+	enabled := value != 0
+	return &enabled
+}
+
+// stringState reads a control as its display string, or "" if it is missing or unreadable
+func stringState(ctl *Control) string {
+	if ctl == nil {
+		return ""
+	}
+	value, err := ctl.GetValueString()
+	if err != nil {
+		return ""
+	}
+	return value
+}
+
+// preampShorthandRe matches shorthand preamp control names: a field and a
+// channel, e.g. "gain1", "phantom2" or "air-1"
+var preampShorthandRe = regexp.MustCompile(`^([A-Za-z]+)[-_ ]?(\d+)$`)
+
+// preampShorthands maps shorthand field names to preamp channel controls
+var preampShorthands = map[string]func(ch *PreampChannel) *Control{
+	"gain":      func(ch *PreampChannel) *Control { return ch.Gain },
+	"phantom":   func(ch *PreampChannel) *Control { return ch.Phantom },
+	"air":       func(ch *PreampChannel) *Control { return ch.Air },
+	"pad":       func(ch *PreampChannel) *Control { return ch.Pad },
+	"impedance": func(ch *PreampChannel) *Control { return ch.Impedance },
+	"level":     func(ch *PreampChannel) *Control { return ch.Level },
+	"inst":      func(ch *PreampChannel) *Control { return ch.Level },
+	"input":     func(ch *PreampChannel) *Control { return ch.InputMode() },
+	"autogain":  func(ch *PreampChannel) *Control { return ch.Autogain },
+	"safe":      func(ch *PreampChannel) *Control { return ch.Safe },
+	"link":      func(ch *PreampChannel) *Control { return ch.Link },
+	"halo":      func(ch *PreampChannel) *Control { return ch.GainHalo },
+	"gainlink":  func(ch *PreampChannel) *Control { return ch.GainLink },
+}
+
+// findPreampShorthand resolves shorthand such as "gain1" to the channel's
+// control. It reports false when name isn't shorthand; a channel without the
+// control gives an error wrapping ErrNotSupported.
+func (c *Card) findPreampShorthand(name string) (*Control, bool, error) {
+	matches := preampShorthandRe.FindStringSubmatch(name)
+	if matches == nil {
+		return nil, false, nil
+	}
+	field, ok := preampShorthands[strings.ToLower(matches[1])]
+	if !ok {
+		return nil, false, nil
+	}
+
+	channelNum, err := strconv.Atoi(matches[2])
+	if err != nil {
+		return nil, false, nil
+	}
+
+	ch, err := c.GetPreampChannel(channelNum)
+	if err != nil {
+		return nil, true, err
+	}
+	ctl := field(ch)
+	if ctl == nil {
+		return nil, true, fmt.Errorf("channel %d %s: %w", channelNum, strings.ToLower(matches[1]), ErrNotSupported)
+	}
+
+	logger.Debug("resolved preamp shorthand", "name", name, "id", ctl.FullID())
+	return ctl, true, nil
+}
+
 // GetPreampChannel gets a specific preamp channel
 func (c *Card) GetPreampChannel(channelNum int) (*PreampChannel, error) {
 	channels, err := c.GetPreampChannels()
@@ -127,7 +311,9 @@ func (c *Card) GetPreampChannel(channelNum int) (*PreampChannel, error) {
 	return nil, fmt.Errorf("preamp channel %d not found", channelNum)
 }
 
-// SetPreampGain sets the gain for a preamp channel
+// SetPreampGain sets the gain for a preamp channel, returning an error wrapping
+// ErrVerifyFailed if the device doesn't take the exact value, or
+// ErrAboveGainCap if the gain is above the channel's cap
 func (c *Card) SetPreampGain(channelNum int, gain int64) error {
 	ch, err := c.GetPreampChannel(channelNum)
 	if err != nil {
@@ -138,7 +324,7 @@ func (c *Card) SetPreampGain(channelNum int, gain int64) error {
 		return fmt.Errorf("channel %d has no gain control", channelNum)
 	}
 
-	return ch.Gain.SetValue(gain)
+	return ch.Gain.SetValueVerified(gain, true)
 }
 
 // SetPreampPhantom sets phantom power for a preamp channel
@@ -160,6 +346,102 @@ func (c *Card) SetPreampPhantom(channelNum int, enabled bool) error {
 	return ch.Phantom.SetValue(value)
 }
 
+// warnPhantom runs the PhantomPowerWarning hook before channels are energized
+func (c *Card) warnPhantom(channels []int) error {
+	if c.PhantomPowerWarning == nil || len(channels) == 0 {
+		return nil
+	}
+	return c.PhantomPowerWarning(channels)
+}
+
+// warnPhantomWrite runs the PhantomPowerWarning hook when a write switches a
+// phantom power control from off to on
+func (c *Card) warnPhantomWrite(ctl *Control, value int64) error {
+	if c.PhantomPowerWarning == nil || value == 0 {
+		return nil
+	}
+	channels := phantomChannels(ctl)
+	if len(channels) == 0 {
+		return nil
+	}
+	if current, err := ctl.GetValue(); err == nil && current != 0 {
+		return nil
+	}
+	return c.warnPhantom(channels)
+}
+
+// phantomChannels returns the channels a phantom power control powers, or nil
+// for any other control
+func phantomChannels(ctl *Control) []int {
+	channelNum, suffix, pair, ok := preampControlChannel(ctl)
+	if !ok || !preampPhantomRe.MatchString(suffix) {
+		return nil
+	}
+	if !pair {
+		return []int{channelNum}
+	}
+
+	// one value switching a whole channel pair
+	name, _ := ParseControlName(ctl.Name)
+	var channels []int
+	for ch := name.ChannelNum; ch <= name.ChannelEnd; ch++ {
+		channels = append(channels, ch)
+	}
+	return channels
+}
+
+// SetAllPhantom sets phantom power on every preamp channel that has a phantom control
+// Channels already in the requested state are left alone, and the phantom power
+// persistence switch, if present, is not touched. It returns the channels that
+// were changed; on error, the channels changed before the failure are returned.
+func (c *Card) SetAllPhantom(enabled bool) ([]int, error) {
+	channels, err := c.GetPreampChannels()
+	if err != nil {
+		return nil, err
+	}
+
+	value := int64(0)
+	if enabled {
+		value = 1
+	}
+
+	// find the channels that need changing first, so the warning can list them
+	var pending []PreampChannel
+	var pendingNums []int
+	for _, ch := range channels {
+		if ch.Phantom == nil {
+			continue
+		}
+
+		current, err := ch.Phantom.GetValue()
+		if err != nil {
+			return nil, fmt.Errorf("channel %d: %v", ch.ChannelNum, err)
+		}
+		if current == value {
+			continue
+		}
+
+		pending = append(pending, ch)
+		pendingNums = append(pendingNums, ch.ChannelNum)
+	}
+
+	if enabled {
+		if err := c.warnPhantom(pendingNums); err != nil {
+			return nil, err
+		}
+	}
+
+	var changed []int
+	for _, ch := range pending {
+		if err := ch.Phantom.setValue(value, true); err != nil {
+			return changed, fmt.Errorf("channel %d: %v", ch.ChannelNum, err)
+		}
+		changed = append(changed, ch.ChannelNum)
+	}
+
+	return changed, nil
+}
+
 // SetPreampAir sets air mode for a preamp channel
 func (c *Card) SetPreampAir(channelNum int, enabled bool) error {
 	ch, err := c.GetPreampChannel(channelNum)
@@ -198,67 +480,271 @@ func (c *Card) SetPreampPad(channelNum int, enabled bool) error {
 	return ch.Pad.SetValue(value)
 }
 
+// SetAllAir sets air mode on every preamp channel that has an air control
+// For interfaces with several air modes, "on" selects the first mode other than
+// Off, and channels already in some air mode are left as they are. It returns
+// the channels that were changed; on error, the channels changed before the
+// failure are returned.
+func (c *Card) SetAllAir(enabled bool) ([]int, error) {
+	return c.setAllSwitch(enabled, func(ch PreampChannel) *Control { return ch.Air })
+}
+
+// SetAllPad sets pad on every preamp channel that has a pad control
+// It returns the channels that were changed; on error, the channels changed
+// before the failure are returned.
+func (c *Card) SetAllPad(enabled bool) ([]int, error) {
+	return c.setAllSwitch(enabled, func(ch PreampChannel) *Control { return ch.Pad })
+}
+
+// setAllSwitch switches one on/off control on every preamp channel that has it
+func (c *Card) setAllSwitch(enabled bool, pick func(PreampChannel) *Control) ([]int, error) {
+	channels, err := c.GetPreampChannels()
+	if err != nil {
+		return nil, err
+	}
+
+	var changed []int
+	for _, ch := range channels {
+		ctl := pick(ch)
+		if ctl == nil {
+			continue
+		}
+
+		current, err := ctl.GetValue()
+		if err != nil {
+			return changed, fmt.Errorf("channel %d: %v", ch.ChannelNum, err)
+		}
+
+		off := switchOffValue(ctl)
+		if (current != off) == enabled {
+			continue
+		}
+
+		value := off
+		if enabled {
+			value = switchOnValue(ctl)
+		}
+		if err := ctl.SetValue(value); err != nil {
+			return changed, fmt.Errorf("channel %d: %v", ch.ChannelNum, err)
+		}
+		changed = append(changed, ch.ChannelNum)
+	}
+
+	return changed, nil
+}
+
+// switchOffValue returns the value of a switch-like control's off state: the
+// "Off" item of an enum, or 0
+func switchOffValue(ctl *Control) int64 {
+	for i, item := range ctl.Items {
+		if strings.EqualFold(item, "Off") {
+			return int64(i)
+		}
+	}
+	return 0
+}
+
+// switchOnValue returns the value of a switch-like control's on state: the
+// first enum item other than "Off", or 1
+func switchOnValue(ctl *Control) int64 {
+	if ctl.Type != ControlTypeEnumerated {
+		return 1
+	}
+	for i, item := range ctl.Items {
+		if !strings.EqualFold(item, "Off") {
+			return int64(i)
+		}
+	}
+	return 1
+}
+
+// SetPreampGainHalo sets the gain halo for a preamp channel
+// The value uses the same syntax as Control.SetValueByString (e.g. "on" or an item name).
+func (c *Card) SetPreampGainHalo(channelNum int, value string) error {
+	ch, err := c.GetPreampChannel(channelNum)
+	if err != nil {
+		return err
+	}
+
+	if ch.GainHalo == nil {
+		return fmt.Errorf("channel %d has no gain halo control", channelNum)
+	}
+
+	return ch.GainHalo.SetValueByString(value)
+}
+
+// SetPreampGainLink sets gain link for a preamp channel
+func (c *Card) SetPreampGainLink(channelNum int, enabled bool) error {
+	ch, err := c.GetPreampChannel(channelNum)
+	if err != nil {
+		return err
+	}
+
+	if ch.GainLink == nil {
+		return fmt.Errorf("channel %d has no gain link control", channelNum)
+	}
+
+	value := int64(0)
+	if enabled {
+		value = 1
+	}
+
+	return ch.GainLink.SetValue(value)
+}
+
+// StartAutogain starts automatic gain adjustment on a preamp channel
+func (c *Card) StartAutogain(channelNum int) error {
+	ch, err := c.GetPreampChannel(channelNum)
+	if err != nil {
+		return err
+	}
+
+	if ch.Autogain == nil {
+		return fmt.Errorf("channel %d has no autogain control", channelNum)
+	}
+
+	return ch.Autogain.SetValue(1)
+}
+
+// autogain polling parameters
+const (
+	autogainPollInterval = 250 * time.Millisecond
+	autogainSettleTime   = 3 * time.Second
+)
+
+// WaitAutogain waits for a running autogain on a channel to finish and returns the gain it reached
+// Completion is detected by the autogain switch turning itself back off, or by
+// the gain settling at a new value for a few seconds on devices that leave the
+// switch on. Both are read from the hardware each time, as the device changes
+// them without any write going through the value cache.
+func (c *Card) WaitAutogain(channelNum int, timeout time.Duration) (int64, error) {
+	ch, err := c.GetPreampChannel(channelNum)
+	if err != nil {
+		return 0, err
+	}
+
+	if ch.Autogain == nil {
+		return 0, fmt.Errorf("channel %d has no autogain control", channelNum)
+	}
+	if ch.Gain == nil {
+		return 0, fmt.Errorf("channel %d has no gain control", channelNum)
+	}
+
+	initial, err := ch.Gain.readHardware()
+	if err != nil {
+		return 0, err
+	}
+
+	deadline := time.Now().Add(timeout)
+	last, lastChange := initial, time.Now()
+
+	for time.Now().Before(deadline) {
+		time.Sleep(autogainPollInterval)
+
+		running, err := ch.Autogain.readHardware()
+		if err != nil {
+			return 0, err
+		}
+
+		gain, err := ch.Gain.readHardware()
+		if err != nil {
+			return 0, err
+		}
+
+		if running == 0 {
+			return gain, nil
+		}
+
+		if gain != last {
+			last, lastChange = gain, time.Now()
+		} else if gain != initial && time.Since(lastChange) >= autogainSettleTime {
+			return gain, nil
+		}
+	}
+
+	return 0, fmt.Errorf("autogain on channel %d did not complete within %v", channelNum, timeout)
+}
+
 // PrintPreampState prints the current state of all preamp channels
 func (c *Card) PrintPreampState() error {
+	return c.RenderPreampState(NewRenderer(os.Stdout))
+}
+
+// RenderPreampState writes the current state of all preamp channels using the renderer
+func (c *Card) RenderPreampState(r *Renderer) error {
 	channels, err := c.GetPreampChannels()
 	if err != nil {
 		return err
 	}
 
 	if len(channels) == 0 {
-		fmt.Println("no preamp controls found")
+		r.Line("no preamp controls found")
 		return nil
 	}
 
-	fmt.Println("\npreamp state:")
-	fmt.Println("=============")
+	r.Printf("\npreamp state:\n")
+	r.Line("=============")
 
 	for _, ch := range channels {
-		fmt.Printf("\nchannel %d:\n", ch.ChannelNum)
+		r.Heading(fmt.Sprintf("channel %d", ch.ChannelNum))
 
 		if ch.Gain != nil {
 			value, _ := ch.Gain.GetValueString()
-			fmt.Printf("  gain:         %s [%d..%d]\n", value, ch.Gain.Min, ch.Gain.Max)
+			r.Line(fmt.Sprintf("  gain:         %s [%d..%d]", value, ch.Gain.Min, ch.Gain.Max))
 		}
 
 		if ch.Phantom != nil {
 			value, _ := ch.Phantom.GetValueString()
-			fmt.Printf("  phantom 48v:  %s\n", value)
+			r.Line(fmt.Sprintf("  phantom 48v:  %s", value))
 		}
 
 		if ch.Air != nil {
 			value, _ := ch.Air.GetValueString()
-			fmt.Printf("  air:          %s\n", value)
+			r.Line(fmt.Sprintf("  air:          %s", value))
 		}
 
 		if ch.Pad != nil {
 			value, _ := ch.Pad.GetValueString()
-			fmt.Printf("  pad:          %s\n", value)
+			r.Line(fmt.Sprintf("  pad:          %s", value))
 		}
 
 		if ch.Impedance != nil {
 			value, _ := ch.Impedance.GetValueString()
-			fmt.Printf("  impedance:    %s\n", value)
+			r.Line(fmt.Sprintf("  impedance:    %s", value))
 		}
 
 		if ch.Level != nil {
 			value, _ := ch.Level.GetValueString()
-			fmt.Printf("  level:        %s\n", value)
+			r.Line(fmt.Sprintf("  level:        %s", value))
+		}
+
+		if mode, ok := inputModeState(&ch); ok {
+			r.Line(fmt.Sprintf("  input:        %s", mode))
 		}
 
 		if ch.Autogain != nil {
 			value, _ := ch.Autogain.GetValueString()
-			fmt.Printf("  autogain:     %s\n", value)
+			r.Line(fmt.Sprintf("  autogain:     %s", value))
 		}
 
 		if ch.Safe != nil {
 			value, _ := ch.Safe.GetValueString()
-			fmt.Printf("  safe:         %s\n", value)
+			r.Line(fmt.Sprintf("  safe:         %s", value))
 		}
 
 		if ch.Link != nil {
 			value, _ := ch.Link.GetValueString()
-			fmt.Printf("  link:         %s\n", value)
+			r.Line(fmt.Sprintf("  link:         %s", value))
+		}
+
+		if ch.GainLink != nil {
+			value, _ := ch.GainLink.GetValueString()
+			r.Line(fmt.Sprintf("  gain link:    %s", value))
+		}
+
+		if ch.GainHalo != nil {
+			value, _ := ch.GainHalo.GetValueString()
+			r.Line(fmt.Sprintf("  gain halo:    %s", value))
 		}
 	}
 
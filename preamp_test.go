@@ -0,0 +1,287 @@
+package scarlettctl
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPreampControlChannel(t *testing.T) {
+	tests := []struct {
+		name    string
+		count   int
+		index   int
+		channel int
+		suffix  string
+		pair    bool
+		ok      bool
+	}{
+		// single-channel controls
+		{"Line In 1 Gain Capture Volume", 1, 0, 1, "Gain Capture Volume", false, true},
+		{"Line In 3 Pad Capture Switch", 1, 0, 3, "Pad Capture Switch", false, true},
+		{"Line In 2 Air Capture Enum", 2, 1, 0, "", false, false}, // further values of one channel
+
+		// a pair's element with a value per channel
+		{"Line In 1-2 Phantom Power Capture Switch", 2, 0, 1, "Phantom Power Capture Switch", false, true},
+		{"Line In 1-2 Phantom Power Capture Switch", 2, 1, 2, "Phantom Power Capture Switch", false, true},
+		{"Line In 3-4 Phantom Power Capture Switch", 2, 1, 4, "Phantom Power Capture Switch", false, true},
+
+		// a pair's element with a single value shared by both channels
+		{"Line In 1-2 Phantom Power Capture Switch", 1, 0, 1, "Phantom Power Capture Switch", true, true},
+		{"Line In 1-2 Link Capture Switch", 1, 0, 1, "Link Capture Switch", true, true},
+
+		// a count that doesn't match the pair is treated as shared
+		{"Line In 1-4 Phantom Power Capture Switch", 2, 0, 1, "Phantom Power Capture Switch", true, true},
+		{"Line In 1-4 Phantom Power Capture Switch", 2, 1, 0, "", false, false},
+
+		// unnumbered elements with a value per channel
+		{"Phantom Power Capture Switch", 2, 0, 1, "Phantom Power Capture Switch", false, true},
+		{"Phantom Power Capture Switch", 2, 1, 2, "Phantom Power Capture Switch", false, true},
+		{"Phantom Power Capture Switch", 1, 0, 0, "", false, false},
+
+		// other families
+		{"Line 01 (Monitor L) Playback Volume", 1, 0, 0, "", false, false},
+		{"Analogue Output 01 Playback Enum", 1, 0, 0, "", false, false},
+	}
+
+	for _, tt := range tests {
+		ctl := &Control{Name: tt.name, Count: tt.count, Index: tt.index}
+		channel, suffix, pair, ok := preampControlChannel(ctl)
+		if channel != tt.channel || suffix != tt.suffix || pair != tt.pair || ok != tt.ok {
+			t.Errorf("preampControlChannel(%q count %d index %d) = %d, %q, %v, %v; want %d, %q, %v, %v",
+				tt.name, tt.count, tt.index, channel, suffix, pair, ok, tt.channel, tt.suffix, tt.pair, tt.ok)
+		}
+	}
+}
+
+func TestGetPreampChannelsMultiIndexPhantom(t *testing.T) {
+	card, _ := newFakeCard(t,
+		fakeElement{numid: 1, name: "Line In 1 Gain Capture Volume", typ: ControlTypeInteger, max: 70},
+		fakeElement{numid: 2, name: "Line In 2 Gain Capture Volume", typ: ControlTypeInteger, max: 70},
+		fakeElement{numid: 3, name: "Line In 1-2 Phantom Power Capture Switch", typ: ControlTypeBoolean, count: 2, max: 1},
+		fakeElement{numid: 4, name: "Line In 1-2 Link Capture Switch", typ: ControlTypeBoolean, max: 1},
+	)
+
+	channels, err := card.GetPreampChannels()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(channels) != 2 {
+		t.Fatalf("got %d channels, want 2", len(channels))
+	}
+
+	for i, ch := range channels {
+		if ch.ChannelNum != i+1 {
+			t.Errorf("channel %d numbered %d", i+1, ch.ChannelNum)
+		}
+		if ch.Gain == nil || ch.Gain.NumID != uint(i+1) {
+			t.Errorf("channel %d gain = %v", i+1, ch.Gain)
+		}
+		if ch.Phantom == nil || ch.Phantom.NumID != 3 || ch.Phantom.Index != i {
+			t.Errorf("channel %d phantom = %v, want numid 3 index %d", i+1, ch.Phantom, i)
+		}
+	}
+	if channels[0].Link == nil || channels[1].Link != nil {
+		t.Errorf("link belongs to channel 1 only, got %v and %v", channels[0].Link, channels[1].Link)
+	}
+}
+
+func TestSetPhantomPowerMultiIndex(t *testing.T) {
+	card, dev := newFakeCard(t,
+		fakeElement{numid: 1, name: "Line In 1-2 Phantom Power Capture Switch", typ: ControlTypeBoolean, count: 2, max: 1},
+	)
+
+	if err := card.SetPreampPhantom(2, true); err != nil {
+		t.Fatal(err)
+	}
+	if dev.value(1, 0) != 0 || dev.value(1, 1) != 1 {
+		t.Errorf("phantom values = %d, %d; want 0, 1", dev.value(1, 0), dev.value(1, 1))
+	}
+}
+
+func TestGetPreampChannelsSkipsOddNames(t *testing.T) {
+	card, _ := newFakeCard(t,
+		fakeElement{numid: 1, name: "Line In 1 Gain Capture Volume", typ: ControlTypeInteger, max: 70},
+		fakeElement{numid: 2, name: "Line In 99999999999999999999 Gain Capture Volume", typ: ControlTypeInteger, max: 70},
+		fakeElement{numid: 3, name: "Line In 0x2 Gain Capture Volume", typ: ControlTypeInteger, max: 70},
+		fakeElement{numid: 4, name: "Line In Gain Capture Volume", typ: ControlTypeInteger, max: 70},
+	)
+
+	channels, err := card.GetPreampChannels()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(channels) != 1 || channels[0].ChannelNum != 1 || channels[0].Gain.NumID != 1 {
+		t.Fatalf("got channels %+v, want only channel 1 with numid 1's gain", channels)
+	}
+}
+
+func TestPhantomPowerWarning(t *testing.T) {
+	card, dev := newFakeCard(t,
+		fakeElement{numid: 1, name: "Line In 1-2 Phantom Power Capture Switch", typ: ControlTypeBoolean, count: 2, max: 1},
+		fakeElement{numid: 2, name: "Line In 3-4 Phantom Power Capture Switch", typ: ControlTypeBoolean, max: 1},
+		fakeElement{numid: 3, name: "Line In 1 Air Capture Switch", typ: ControlTypeBoolean, max: 1},
+	)
+	card.EnableUndo(10)
+
+	var warned [][]int
+	refuse := false
+	card.PhantomPowerWarning = func(channels []int) error {
+		warned = append(warned, channels)
+		if refuse {
+			return errors.New("refused")
+		}
+		return nil
+	}
+	expect := func(step string, want ...[]int) {
+		t.Helper()
+		if !reflect.DeepEqual(warned, want) {
+			t.Errorf("%s: warned %v, want %v", step, warned, want)
+		}
+		warned = nil
+	}
+
+	phantom2, err := card.FindControlByID("mixer:0.0/Line In 1-2 Phantom Power Capture Switch[1]")
+	if err != nil {
+		t.Fatal(err)
+	}
+	phantom34, err := card.FindControl("Line In 3-4 Phantom Power Capture Switch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	air, err := card.FindControl("Line In 1 Air Capture Switch")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := phantom2.SetValue(1); err != nil {
+		t.Fatal(err)
+	}
+	expect("off to on", []int{2})
+
+	if err := phantom2.SetValue(1); err != nil {
+		t.Fatal(err)
+	}
+	expect("already on")
+
+	if err := air.SetValue(1); err != nil {
+		t.Fatal(err)
+	}
+	expect("another control")
+
+	if err := phantom2.SetValue(0); err != nil {
+		t.Fatal(err)
+	}
+	expect("on to off")
+
+	// undoing the switch off switches it back on
+	if err := card.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	expect("undo", []int{2})
+
+	tx := card.Transaction()
+	if err := tx.Set(phantom34, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	expect("transaction on a shared pair control", []int{3, 4})
+
+	// refusing leaves the control off
+	refuse = true
+	if err := phantom34.SetValue(0); err != nil {
+		t.Fatal(err)
+	}
+	expect("switch off")
+	if err := phantom34.SetValue(1); err == nil {
+		t.Error("expected the refused write to fail")
+	}
+	expect("refused", []int{3, 4})
+	if dev.value(2, 0) != 0 {
+		t.Error("refused write reached the hardware")
+	}
+}
+
+func TestSetAllPhantomWarnsOnce(t *testing.T) {
+	card, dev := newFakeCard(t,
+		fakeElement{numid: 1, name: "Line In 1-2 Phantom Power Capture Switch", typ: ControlTypeBoolean, count: 2, max: 1},
+		fakeElement{numid: 2, name: "Line In 3 Phantom Power Capture Switch", typ: ControlTypeBoolean, max: 1},
+	)
+
+	var warned [][]int
+	card.PhantomPowerWarning = func(channels []int) error {
+		warned = append(warned, channels)
+		return nil
+	}
+
+	changed, err := card.SetAllPhantom(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{1, 2, 3}; !reflect.DeepEqual(changed, want) {
+		t.Errorf("changed %v, want %v", changed, want)
+	}
+	if want := [][]int{{1, 2, 3}}; !reflect.DeepEqual(warned, want) {
+		t.Errorf("warned %v, want %v", warned, want)
+	}
+	if dev.value(1, 0) != 1 || dev.value(1, 1) != 1 || dev.value(2, 0) != 1 {
+		t.Error("phantom power not switched on everywhere")
+	}
+}
+
+func TestWaitAutogainBypassesCache(t *testing.T) {
+	card, dev := newFakeCard(t,
+		fakeElement{numid: 1, name: "Line In 1 Gain Capture Volume", typ: ControlTypeInteger, max: 70, values: []int64{10}},
+		fakeElement{numid: 2, name: "Line In 1 Autogain Capture Switch", typ: ControlTypeBoolean, max: 1, values: []int64{1}},
+	)
+	card.EnableCache(true)
+
+	// fill the cache with the values from before autogain ran
+	if _, err := card.GetPreampState(); err != nil {
+		t.Fatal(err)
+	}
+	ch, err := card.GetPreampChannel(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ch.Autogain.GetValue(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the device finishes autogain without telling the cache
+	dev.set(1, 0, 42)
+	dev.set(2, 0, 0)
+
+	gain, err := card.WaitAutogain(1, 5*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gain != 42 {
+		t.Errorf("WaitAutogain() = %d, want 42", gain)
+	}
+}
+
+func TestPreampStateGainDB(t *testing.T) {
+	card, _ := newFakeCard(t,
+		fakeElement{numid: 1, name: "Line In 1 Gain Capture Volume", typ: ControlTypeInteger, max: 70, values: []int64{10}, tlv: dbScaleTLV(-1000, 50)},
+		fakeElement{numid: 2, name: "Line In 2 Gain Capture Volume", typ: ControlTypeInteger, max: 70, values: []int64{10}},
+	)
+
+	states, err := card.GetPreampState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(states) != 2 {
+		t.Fatalf("got %d states, want 2", len(states))
+	}
+
+	// the TLV scale wins over the scarlett2 default of -1 dB in 1 dB steps
+	for i, want := range []float64{-5, 9} {
+		if got := states[i].GainDB; got == nil || *got != want {
+			t.Errorf("channel %d gain_db = %v, want %v", states[i].Channel, got, want)
+		}
+	}
+}
@@ -0,0 +1,241 @@
+package scarlettctl
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+//go:embed presets/*.json
+var presetFiles embed.FS
+
+// Preset is a built-in configuration for a common setup. Presets describe
+// inputs, routes and monitoring rather than exact control names, so the same
+// preset applies to every generation: preamp settings go through the preamp
+// channel lookup, sinks are matched by pattern and sources by name.
+type Preset struct {
+	Name          string        `json:"-"`
+	Description   string        `json:"description"`
+	Inputs        []PresetInput `json:"inputs,omitempty"`
+	Routes        []PresetRoute `json:"routes,omitempty"`
+	DirectMonitor string        `json:"direct_monitor,omitempty"`
+}
+
+// PresetInput is the preamp setup of one input channel; unset fields are left alone
+type PresetInput struct {
+	Channel int    `json:"channel"`
+	Level   string `json:"level,omitempty"` // "Line" or "Inst"
+	Air     *bool  `json:"air,omitempty"`
+	Pad     *bool  `json:"pad,omitempty"`
+}
+
+// PresetRoute routes a source, by name, to the first sink matching a pattern
+type PresetRoute struct {
+	Sink   string `json:"sink"`
+	Source string `json:"source"`
+}
+
+// PresetResult lists the steps of a preset that were applied, and those
+// skipped because the card lacks the control
+type PresetResult struct {
+	Applied []string
+	Skipped []string
+}
+
+// Presets returns the built-in presets, sorted by name
+func Presets() ([]*Preset, error) {
+	entries, err := presetFiles.ReadDir("presets")
+	if err != nil {
+		return nil, err
+	}
+
+	var presets []*Preset
+	for _, entry := range entries {
+		data, err := presetFiles.ReadFile(path.Join("presets", entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		preset := &Preset{Name: strings.TrimSuffix(entry.Name(), ".json")}
+		if err := json.Unmarshal(data, preset); err != nil {
+			return nil, fmt.Errorf("failed to parse preset '%s': %v", preset.Name, err)
+		}
+		presets = append(presets, preset)
+	}
+
+	sort.Slice(presets, func(i, j int) bool { return presets[i].Name < presets[j].Name })
+	return presets, nil
+}
+
+// GetPreset returns the built-in preset with the given name (case-insensitive)
+func GetPreset(name string) (*Preset, error) {
+	presets, err := Presets()
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, preset := range presets {
+		if strings.EqualFold(preset.Name, name) {
+			return preset, nil
+		}
+		names = append(names, preset.Name)
+	}
+
+	return nil, fmt.Errorf("preset '%s' not found (available: %s)", name, strings.Join(names, ", "))
+}
+
+// ApplyPreset applies a preset to the card. Steps whose controls don't exist
+// on the card, such as air on an interface without it, are skipped and
+// reported rather than failing the preset; other errors stop it.
+func (c *Card) ApplyPreset(p *Preset) (*PresetResult, error) {
+	result := &PresetResult{}
+
+	for _, input := range p.Inputs {
+		if err := c.applyPresetInput(input, result); err != nil {
+			return result, err
+		}
+	}
+
+	if len(p.Routes) > 0 {
+		if _, err := c.GetControls(); err != nil {
+			return result, err
+		}
+		// with the controls readable, this only fails on cards without routing
+		sources, _ := c.GetRoutingSources()
+		for _, route := range p.Routes {
+			if err := c.applyPresetRoute(route, sources, result); err != nil {
+				return result, err
+			}
+		}
+	}
+
+	if p.DirectMonitor != "" {
+		step := fmt.Sprintf("direct monitor %s", p.DirectMonitor)
+		err := c.SetDirectMonitor(p.DirectMonitor)
+		switch {
+		case errors.Is(err, ErrNotSupported):
+			result.Skipped = append(result.Skipped, step)
+		case err != nil:
+			return result, err
+		default:
+			result.Applied = append(result.Applied, step)
+		}
+	}
+
+	return result, nil
+}
+
+// applyPresetInput sets the preamp fields of one input
+func (c *Card) applyPresetInput(input PresetInput, result *PresetResult) error {
+	channels, err := c.GetPreampChannels()
+	if err != nil {
+		return err
+	}
+
+	var ch *PreampChannel
+	for i := range channels {
+		if channels[i].ChannelNum == input.Channel {
+			ch = &channels[i]
+			break
+		}
+	}
+
+	var air, pad, level, impedance *Control
+	if ch != nil {
+		air, pad, level, impedance = ch.Air, ch.Pad, ch.Level, ch.Impedance
+	}
+
+	// apply runs one step, skipping it when the card lacks the control
+	apply := func(step string, ctl *Control, set func(*Control) error) error {
+		step = fmt.Sprintf("input %d %s", input.Channel, step)
+		if ctl == nil {
+			result.Skipped = append(result.Skipped, step)
+			return nil
+		}
+		if err := set(ctl); err != nil {
+			return fmt.Errorf("%s: %v", step, err)
+		}
+		result.Applied = append(result.Applied, step)
+		return nil
+	}
+
+	if input.Level != "" {
+		step := "level " + input.Level
+		var err error
+		if level != nil || impedance == nil {
+			err = apply(step, level, func(ctl *Control) error { return ctl.SetValueByString(input.Level) })
+		} else {
+			// older interfaces have an impedance switch instead, on for instrument
+			inst := strings.EqualFold(input.Level, "Inst")
+			err = apply(step, impedance, func(ctl *Control) error { return ctl.SetValue(boolValue(inst)) })
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if input.Air != nil {
+		step := "air " + onOff(*input.Air)
+		err := apply(step, air, func(ctl *Control) error {
+			if *input.Air {
+				return ctl.SetValue(switchOnValue(ctl))
+			}
+			return ctl.SetValue(switchOffValue(ctl))
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if input.Pad != nil {
+		step := "pad " + onOff(*input.Pad)
+		if err := apply(step, pad, func(ctl *Control) error { return ctl.SetValue(boolValue(*input.Pad)) }); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// applyPresetRoute routes one source, skipping sinks and sources the card lacks
+func (c *Card) applyPresetRoute(route PresetRoute, sources []RoutingSource, result *PresetResult) error {
+	step := fmt.Sprintf("route %s <- %s", route.Sink, route.Source)
+
+	sink, err := c.FindRoutingSink(route.Sink)
+	if err != nil {
+		result.Skipped = append(result.Skipped, step)
+		return nil
+	}
+
+	for _, src := range sources {
+		if strings.EqualFold(src.Name, route.Source) {
+			if err := sink.Control.SetValue(int64(src.ID)); err != nil {
+				return fmt.Errorf("%s: %v", step, err)
+			}
+			result.Applied = append(result.Applied, step)
+			return nil
+		}
+	}
+
+	result.Skipped = append(result.Skipped, step)
+	return nil
+}
+
+// boolValue converts a switch state to a control value
+func boolValue(enabled bool) int64 {
+	if enabled {
+		return 1
+	}
+	return 0
+}
+
+// onOff names a switch state
+func onOff(enabled bool) string {
+	if enabled {
+		return "on"
+	}
+	return "off"
+}
@@ -0,0 +1,167 @@
+package scarlettctl
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/sys/unix"
+)
+
+// DefaultWidth is the output width used when the terminal width cannot be detected
+const DefaultWidth = 80
+
+// maxRuleWidth caps the width of horizontal rules on very wide terminals
+const maxRuleWidth = 60
+
+// ansi color codes used for port categories
+var categoryColors = map[PortCategory]string{
+	PortCategoryOff: "\033[2m",
+	PortCategoryHW:  "\033[32m",
+	PortCategoryMix: "\033[33m",
+	PortCategoryDSP: "\033[35m",
+	PortCategoryPCM: "\033[36m",
+}
+
+const ansiReset = "\033[0m"
+
+// Renderer writes width-aware, optionally colorized text output
+// It is shared by the Print* functions and can be used by any front-end that
+// wants the same formatting on a different writer
+type Renderer struct {
+	w     io.Writer
+	Width int  // total output width in columns
+	Color bool // colorize categories with ansi escapes
+}
+
+// NewRenderer creates a renderer for w, detecting the terminal width and color
+// support when w is a terminal. Color is disabled when NO_COLOR is set.
+func NewRenderer(w io.Writer) *Renderer {
+	r := &Renderer{w: w, Width: DefaultWidth}
+
+	if f, ok := w.(*os.File); ok {
+		if width, ok := terminalWidth(f); ok {
+			r.Width = width
+			r.Color = os.Getenv("NO_COLOR") == ""
+		}
+	}
+
+	// honor an explicit COLUMNS when the terminal can't be queried
+	if r.Width == DefaultWidth {
+		if cols, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && cols > 0 {
+			r.Width = cols
+		}
+	}
+
+	return r
+}
+
+// terminalWidth returns the column count of f if it is a terminal
+func terminalWidth(f *os.File) (int, bool) {
+	ws, err := unix.IoctlGetWinsize(int(f.Fd()), unix.TIOCGWINSZ)
+	if err != nil || ws.Col == 0 {
+		return 0, false
+	}
+	return int(ws.Col), true
+}
+
+// Printf writes formatted output without width handling
+func (r *Renderer) Printf(format string, args ...interface{}) {
+	fmt.Fprintf(r.w, format, args...)
+}
+
+// Line writes a single line, truncated to the output width
+func (r *Renderer) Line(s string) {
+	fmt.Fprintln(r.w, truncate(s, r.Width))
+}
+
+// Rule writes a horizontal rule using the given character
+func (r *Renderer) Rule(ch string) {
+	fmt.Fprintln(r.w, strings.Repeat(ch, r.ruleWidth()))
+}
+
+// Banner writes a title centered between two double rules
+func (r *Renderer) Banner(title string) {
+	width := r.ruleWidth()
+	pad := (width - utf8.RuneCountInString(title)) / 2
+	if pad < 0 {
+		pad = 0
+	}
+
+	r.Rule("═")
+	r.Line(strings.Repeat(" ", pad) + title)
+	r.Rule("═")
+}
+
+// Heading writes a section heading preceded by a blank line
+func (r *Renderer) Heading(title string) {
+	fmt.Fprintf(r.w, "\n%s:\n", truncate(title, r.Width-1))
+}
+
+// Category colorizes s according to the port category when color is enabled
+func (r *Renderer) Category(category PortCategory, s string) string {
+	if !r.Color {
+		return s
+	}
+	code, ok := categoryColors[category]
+	if !ok {
+		return s
+	}
+	return code + s + ansiReset
+}
+
+// Columns computes the widths of two flexible columns that share the space left
+// after fixed is subtracted from the output width. Each column gets at most its
+// preferred width and at least min.
+func (r *Renderer) Columns(fixed, first, second, min int) (int, int) {
+	avail := r.Width - fixed
+	if avail >= first+second {
+		return first, second
+	}
+
+	// shrink proportionally to the preferred widths
+	a := avail * first / (first + second)
+	if a < min {
+		a = min
+	}
+	b := avail - a
+	if b < min {
+		b = min
+	}
+	return a, b
+}
+
+func (r *Renderer) ruleWidth() int {
+	if r.Width < maxRuleWidth {
+		return r.Width
+	}
+	return maxRuleWidth
+}
+
+// pad truncates or right-pads s to exactly width columns
+func pad(s string, width int) string {
+	s = truncate(s, width)
+	n := utf8.RuneCountInString(s)
+	if n < width {
+		s += strings.Repeat(" ", width-n)
+	}
+	return s
+}
+
+// truncate shortens s to width columns, marking the cut with an ellipsis
+func truncate(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	if utf8.RuneCountInString(s) <= width {
+		return s
+	}
+	runes := []rune(s)
+	if width == 1 {
+		return string(runes[:1])
+	}
+	return string(runes[:width-1]) + "…"
+}
@@ -2,7 +2,7 @@ package scarlettctl
 
 import (
 	"fmt"
-	"regexp"
+	"os"
 	"strconv"
 	"strings"
 )
@@ -20,16 +20,28 @@ var (
 		"ADAT Output %02d Playback Enum",
 	}
 
-	// port category detection regexes
-	portCategoryRegexes = map[PortCategory]*regexp.Regexp{
-		PortCategoryPCM: regexp.MustCompile(`^PCM \d+`),
-		PortCategoryMix: regexp.MustCompile(`^(Mixer Input|Mixer|Matrix) \d+`),
-		PortCategoryDSP: regexp.MustCompile(`^DSP Input \d+`),
-		PortCategoryHW:  regexp.MustCompile(`^(Analogue|S/PDIF|ADAT)( Output| Input)? \d+`),
+	// port categories by control name family
+	familyCategories = map[string]PortCategory{
+		"PCM":             PortCategoryPCM,
+		"Mixer Input":     PortCategoryMix,
+		"Mixer":           PortCategoryMix,
+		"Matrix":          PortCategoryMix,
+		"DSP":             PortCategoryDSP,
+		"DSP Input":       PortCategoryDSP,
+		"Analogue":        PortCategoryHW,
+		"Analogue Output": PortCategoryHW,
+		"Analogue Input":  PortCategoryHW,
+		"S/PDIF":          PortCategoryHW,
+		"S/PDIF Output":   PortCategoryHW,
+		"S/PDIF Input":    PortCategoryHW,
+		"ADAT":            PortCategoryHW,
+		"ADAT Output":     PortCategoryHW,
+		"ADAT Input":      PortCategoryHW,
 	}
 )
 
-// GetRoutingSources returns all routing sources available on the card
+// GetRoutingSources returns all routing sources available on the card, or an
+// error wrapping ErrNotSupported when it has no routing controls
 func (c *Card) GetRoutingSources() ([]RoutingSource, error) {
 	// find a routing sink control to extract source names from
 	controls, err := c.GetControls()
@@ -46,7 +58,7 @@ func (c *Card) GetRoutingSources() ([]RoutingSource, error) {
 	}
 
 	if sinkControl == nil {
-		return nil, fmt.Errorf("no routing controls found")
+		return nil, fmt.Errorf("no routing controls found: %w", ErrNotSupported)
 	}
 
 	sources := make([]RoutingSource, 0, len(sinkControl.Items))
@@ -76,7 +88,8 @@ func (c *Card) GetRoutingSources() ([]RoutingSource, error) {
 	return sources, nil
 }
 
-// GetRoutingSinks returns all routing sinks (destinations) on the card
+// GetRoutingSinks returns all routing sinks (destinations) on the card, or an
+// error wrapping ErrNotSupported when it has none
 func (c *Card) GetRoutingSinks() ([]RoutingSink, error) {
 	controls, err := c.GetControls()
 	if err != nil {
@@ -104,7 +117,7 @@ func (c *Card) GetRoutingSinks() ([]RoutingSink, error) {
 	}
 
 	if len(sinks) == 0 {
-		return nil, fmt.Errorf("no routing sinks found")
+		return nil, fmt.Errorf("no routing sinks found: %w", ErrNotSupported)
 	}
 
 	return sinks, nil
@@ -139,31 +152,167 @@ func (c *Card) SetRouting(sinkName string, sourceID int) error {
 
 	for _, sink := range sinks {
 		if sink.Name == sinkName {
-			return sink.Control.SetValue(int64(sourceID))
+			return sink.Control.SetValueVerified(int64(sourceID), true)
 		}
 	}
 
 	return fmt.Errorf("routing sink '%s' not found", sinkName)
 }
 
-// SetRoutingByNames sets a routing connection using source and sink names
-func (c *Card) SetRoutingByNames(sinkName, sourceName string) error {
-	// find the sink
+// FindRoutingSink returns the routing sink a pattern names, ignoring case: the
+// full or short name (e.g. "Analogue Output 01"), the same port with the channel
+// written without leading zeros ("Analogue Output 1"), or else the first sink
+// containing the pattern. A trailing channel number must match whole, so
+// "Output 1" never picks "Output 10".
+func (c *Card) FindRoutingSink(pattern string) (*RoutingSink, error) {
 	sinks, err := c.GetRoutingSinks()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	var targetSink *RoutingSink
-	for i := range sinks {
-		if strings.Contains(sinks[i].Name, sinkName) {
-			targetSink = &sinks[i]
-			break
+	names := make([]string, len(sinks))
+	shortNames := make([]string, len(sinks))
+	for i, sink := range sinks {
+		names[i] = sink.Name
+		shortNames[i] = shortSinkName(sink.Name)
+	}
+
+	if i := matchRoutingName(pattern, names, shortNames); i >= 0 {
+		return &sinks[i], nil
+	}
+	return nil, fmt.Errorf("sink matching '%s' not found", pattern)
+}
+
+// findRoutingSource returns the routing source a name picks out, with the same
+// precedence as FindRoutingSink
+func findRoutingSource(sources []RoutingSource, name string) (RoutingSource, bool) {
+	names := make([]string, len(sources))
+	for i, src := range sources {
+		names[i] = src.Name
+	}
+
+	if i := matchRoutingName(name, names); i >= 0 {
+		return sources[i], true
+	}
+	return RoutingSource{}, false
+}
+
+// matchRoutingName returns the index of the name a pattern picks out, or -1,
+// trying each way of matching across every name set before the next: an exact
+// match, then the same family and channel number, then a substring match that
+// doesn't cut a number short. Case is ignored throughout.
+func matchRoutingName(pattern string, nameSets ...[]string) int {
+	lower := strings.ToLower(pattern)
+	parsed, parsedOK := ParseControlName(pattern)
+
+	matchers := []func(name string) bool{
+		func(name string) bool { return strings.ToLower(name) == lower },
+		func(name string) bool {
+			if !parsedOK {
+				return false
+			}
+			n, ok := ParseControlName(name)
+			return ok && strings.EqualFold(n.Family, parsed.Family) && n.ChannelNum == parsed.ChannelNum &&
+				n.ChannelEnd == parsed.ChannelEnd && strings.EqualFold(n.Suffix, parsed.Suffix)
+		},
+		func(name string) bool { return containsWholeNumbers(strings.ToLower(name), lower) },
+	}
+
+	for _, match := range matchers {
+		for _, names := range nameSets {
+			for i, name := range names {
+				if match(name) {
+					return i
+				}
+			}
+		}
+	}
+	return -1
+}
+
+// containsWholeNumbers reports whether s contains substr at a position where
+// any number at either end of substr isn't part of a longer number in s
+func containsWholeNumbers(s, substr string) bool {
+	if substr == "" {
+		return false
+	}
+
+	isDigit := func(b byte) bool { return b >= '0' && b <= '9' }
+	for from := 0; from <= len(s)-len(substr); {
+		i := strings.Index(s[from:], substr)
+		if i < 0 {
+			return false
+		}
+		start, end := from+i, from+i+len(substr)
+		cutStart := isDigit(substr[0]) && start > 0 && isDigit(s[start-1])
+		cutEnd := isDigit(substr[len(substr)-1]) && end < len(s) && isDigit(s[end])
+		if !cutStart && !cutEnd {
+			return true
 		}
+		from = start + 1
+	}
+	return false
+}
+
+// FindSinksForSource returns every routing sink currently fed by a source,
+// e.g. to see what repurposing it would affect. The source is a numeric ID or
+// a name, matched as by SetRoutingByNames.
+func (c *Card) FindSinksForSource(source string) ([]RoutingSink, error) {
+	sources, err := c.GetRoutingSources()
+	if err != nil {
+		return nil, err
+	}
+
+	var src RoutingSource
+	if id, err := strconv.Atoi(source); err == nil {
+		if id < 0 || id >= len(sources) {
+			return nil, fmt.Errorf("routing source ID %d out of range [0, %d]", id, len(sources)-1)
+		}
+		src = sources[id]
+	} else if found, ok := findRoutingSource(sources, source); ok {
+		src = found
+	} else {
+		return nil, fmt.Errorf("routing source matching '%s' not found", source)
+	}
+
+	sinks, err := c.GetRoutingSinks()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []RoutingSink
+	for _, sink := range sinks {
+		value, err := sink.Control.GetValue()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read routing for %s: %w", sink.Name, err)
+		}
+		if value == int64(src.ID) {
+			result = append(result, sink)
+		}
+	}
+
+	return result, nil
+}
+
+// SetRoutingBySinkPattern routes a source, by numeric ID, to the sink
+// FindRoutingSink picks for sinkPattern. The write is checked as by
+// Control.SetValueVerified with exact set.
+func (c *Card) SetRoutingBySinkPattern(sinkPattern string, sourceID int) error {
+	sink, err := c.FindRoutingSink(sinkPattern)
+	if err != nil {
+		return err
 	}
 
-	if targetSink == nil {
-		return fmt.Errorf("routing sink matching '%s' not found", sinkName)
+	return sink.Control.SetValueVerified(int64(sourceID), true)
+}
+
+// SetRoutingByNames sets a routing connection using source and sink names,
+// checking the write as SetRoutingBySinkPattern does
+func (c *Card) SetRoutingByNames(sinkName, sourceName string) error {
+	// find the sink
+	targetSink, err := c.FindRoutingSink(sinkName)
+	if err != nil {
+		return err
 	}
 
 	// find the source ID
@@ -172,10 +321,8 @@ func (c *Card) SetRoutingByNames(sinkName, sourceName string) error {
 		return err
 	}
 
-	for _, src := range sources {
-		if strings.Contains(src.Name, sourceName) || src.Name == sourceName {
-			return targetSink.Control.SetValue(int64(src.ID))
-		}
+	if src, ok := findRoutingSource(sources, sourceName); ok {
+		return targetSink.Control.SetValueVerified(int64(src.ID), true)
 	}
 
 	return fmt.Errorf("routing source matching '%s' not found", sourceName)
@@ -193,13 +340,16 @@ func isRoutingSink(name string) bool {
 
 // parseRoutingSinkName extracts category and port number from sink name
 func parseRoutingSinkName(name string) (PortCategory, int) {
-	for category, regex := range portCategoryRegexes {
-		if regex.MatchString(name) {
-			portNum := extractPortNumber(name)
-			return category, portNum
-		}
+	parsed, ok := ParseControlName(name)
+	if !ok {
+		return PortCategoryOff, 0
 	}
-	return PortCategoryOff, 0
+
+	category, ok := familyCategories[parsed.Family]
+	if !ok {
+		return PortCategoryOff, 0
+	}
+	return category, parsed.ChannelNum
 }
 
 // parseRoutingSourceName extracts category and port number from source name
@@ -215,47 +365,26 @@ func parseRoutingSourceName(name string) (PortCategory, int) {
 		return PortCategoryMix, portNum
 	}
 
-	// check for PCM XX
-	if strings.HasPrefix(name, "PCM ") {
-		portNum := extractPortNumber(name)
-		return PortCategoryPCM, portNum - 1 // PCM is 1-indexed in names
-	}
-
-	// check for DSP X
-	if strings.HasPrefix(name, "DSP ") {
-		portNum := extractPortNumber(name)
-		return PortCategoryDSP, portNum - 1
+	// PCM, DSP and hardware sources are 1-indexed in names
+	parsed, ok := ParseControlName(name)
+	if !ok {
+		return PortCategoryOff, 0
 	}
 
-	// check for hardware (Analogue, S/PDIF, ADAT)
-	if strings.Contains(name, "Analogue") ||
-	   strings.Contains(name, "S/PDIF") ||
-	   strings.Contains(name, "ADAT") {
-		portNum := extractPortNumber(name)
-		return PortCategoryHW, portNum - 1
+	switch category := familyCategories[parsed.Family]; category {
+	case PortCategoryPCM, PortCategoryDSP, PortCategoryHW:
+		return category, parsed.ChannelNum - 1
 	}
-
 	return PortCategoryOff, 0
 }
 
-// extractPortNumber extracts a number from a string
-func extractPortNumber(s string) int {
-	// find all numbers in the string
-	re := regexp.MustCompile(`\d+`)
-	matches := re.FindAllString(s, -1)
-
-	if len(matches) > 0 {
-		num, err := strconv.Atoi(matches[0])
-		if err == nil {
-			return num
-		}
-	}
-
-	return 0
-}
-
 // PrintRoutingMatrix prints a human-readable routing matrix
 func (c *Card) PrintRoutingMatrix() error {
+	return c.RenderRoutingMatrix(NewRenderer(os.Stdout))
+}
+
+// RenderRoutingMatrix writes a human-readable routing matrix using the renderer
+func (c *Card) RenderRoutingMatrix(r *Renderer) error {
 	sources, err := c.GetRoutingSources()
 	if err != nil {
 		return err
@@ -267,9 +396,8 @@ func (c *Card) PrintRoutingMatrix() error {
 	}
 
 	// print available sources organized by category
-	fmt.Println("\n════════════════════════════════════════════════════════════")
-	fmt.Println("                    routing sources")
-	fmt.Println("════════════════════════════════════════════════════════════")
+	r.Printf("\n")
+	r.Banner("routing sources")
 
 	printSourcesByCategory := func(category PortCategory, title string) {
 		var categorySource []RoutingSource
@@ -280,13 +408,19 @@ func (c *Card) PrintRoutingMatrix() error {
 		}
 
 		if len(categorySource) > 0 {
-			fmt.Printf("\n%s:\n", title)
+			r.Heading(title)
+
+			// "  [nn] " prefix is 7 columns, plus a separating space
+			nameWidth, infoWidth := r.Columns(8, 20, 20, 8)
 			for _, src := range categorySource {
-				hwType := ""
+				info := src.Category.String()
 				if src.HardwareType != "" {
-					hwType = fmt.Sprintf(" [%s]", src.HardwareType)
+					info += fmt.Sprintf(" [%s]", src.HardwareType)
 				}
-				fmt.Printf("  [%2d] %-20s %s%s\n", src.ID, src.Name, src.Category, hwType)
+				r.Printf("  [%2d] %s %s\n",
+					src.ID,
+					pad(src.Name, nameWidth),
+					r.Category(src.Category, truncate(info, infoWidth)))
 			}
 		}
 	}
@@ -298,9 +432,11 @@ func (c *Card) PrintRoutingMatrix() error {
 	printSourcesByCategory(PortCategoryDSP, "dsp outputs")
 
 	// print routing organized by sink category
-	fmt.Println("\n════════════════════════════════════════════════════════════")
-	fmt.Println("                    routing matrix")
-	fmt.Println("════════════════════════════════════════════════════════════")
+	r.Printf("\n")
+	r.Banner("routing matrix")
+	if note := c.rateNote(); note != "" {
+		r.Line(note)
+	}
 
 	printSinksByCategory := func(category PortCategory, title string) {
 		var categorySinks []RoutingSink
@@ -311,21 +447,25 @@ func (c *Card) PrintRoutingMatrix() error {
 		}
 
 		if len(categorySinks) > 0 {
-			fmt.Printf("\n%s:\n", title)
-			fmt.Println(strings.Repeat("-", 60))
+			r.Heading(title)
+			r.Rule("-")
 
+			// "  " indent and " <- " separator take 6 columns
+			sinkWidth, sourceWidth := r.Columns(6, 35, 20, 8)
 			for _, sink := range categorySinks {
 				value, err := sink.Control.GetValue()
 				if err != nil {
-					fmt.Printf("  %-35s -> error: %v\n", sink.Name, err)
+					r.Line(fmt.Sprintf("  %s -> error: %v", pad(shortSinkName(sink.Name), sinkWidth), err))
 					continue
 				}
 
-				sourceName := "unknown"
+				sourceName := unknownValue(value)
 				sourceInfo := ""
-				if value >= 0 && int(value) < len(sources) {
+				sourceCategory := PortCategoryOff
+				if value >= 0 && value < int64(len(sources)) {
 					src := sources[value]
 					sourceName = src.Name
+					sourceCategory = src.Category
 					if src.Category != PortCategoryOff {
 						sourceInfo = fmt.Sprintf(" (%s)", src.Category)
 						if src.HardwareType != "" {
@@ -334,9 +474,14 @@ func (c *Card) PrintRoutingMatrix() error {
 					}
 				}
 
-				fmt.Printf("  %-35s <- %-20s%s\n",
-					shortSinkName(sink.Name),
-					sourceName,
+				// only show the source details when they fit
+				if 6+sinkWidth+sourceWidth+len(sourceInfo) > r.Width {
+					sourceInfo = ""
+				}
+
+				r.Printf("  %s <- %s%s\n",
+					pad(shortSinkName(sink.Name), sinkWidth),
+					r.Category(sourceCategory, pad(sourceName, sourceWidth)),
 					sourceInfo)
 			}
 		}
@@ -347,9 +492,53 @@ func (c *Card) PrintRoutingMatrix() error {
 	printSinksByCategory(PortCategoryMix, "mixer inputs")
 	printSinksByCategory(PortCategoryDSP, "dsp inputs")
 
-	fmt.Println("\n════════════════════════════════════════════════════════════")
-	fmt.Printf("total: %d sources, %d sinks\n", len(sources), len(sinks))
-	fmt.Println("════════════════════════════════════════════════════════════\n")
+	r.Printf("\n")
+	r.Rule("═")
+	r.Line(fmt.Sprintf("total: %d sources, %d sinks", len(sources), len(sinks)))
+	r.Rule("═")
+	r.Printf("\n")
+
+	return nil
+}
+
+// RenderRoutingSources writes the list of routing sources with their IDs
+func (c *Card) RenderRoutingSources(r *Renderer) error {
+	sources, err := c.GetRoutingSources()
+	if err != nil {
+		return err
+	}
+
+	// "  [nn] " prefix, category and port columns take 26 columns
+	nameWidth, _ := r.Columns(26, 30, 0, 8)
+	r.Line(fmt.Sprintf("  %-4s %s %-10s %4s", "id", pad("name", nameWidth), "category", "port"))
+	for _, src := range sources {
+		r.Printf("  [%2d] %s %s %4d\n",
+			src.ID,
+			pad(src.Name, nameWidth),
+			r.Category(src.Category, pad(src.Category.String(), 10)),
+			src.PortNum)
+	}
+
+	return nil
+}
+
+// RenderRoutingSinks writes the list of routing sinks with their indices
+func (c *Card) RenderRoutingSinks(r *Renderer) error {
+	sinks, err := c.GetRoutingSinks()
+	if err != nil {
+		return err
+	}
+
+	// index, category and port columns take 26 columns
+	nameWidth, _ := r.Columns(26, 40, 0, 8)
+	r.Line(fmt.Sprintf("  %-4s %s %-10s %4s", "idx", pad("name", nameWidth), "category", "port"))
+	for _, sink := range sinks {
+		r.Printf("  %4d %s %s %4d\n",
+			sink.Index,
+			pad(sink.Name, nameWidth),
+			r.Category(sink.Category, pad(sink.Category.String(), 10)),
+			sink.PortNum)
+	}
 
 	return nil
 }
@@ -0,0 +1,168 @@
+package scarlettctl
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestMatchRoutingName(t *testing.T) {
+	names := []string{
+		"Analogue Output 01 Playback Enum",
+		"Analogue Output 02 Playback Enum",
+		"Analogue Output 10 Playback Enum",
+		"Mixer Input 01 Capture Enum",
+		"PCM 01 Capture Enum",
+	}
+	shortNames := make([]string, len(names))
+	for i, name := range names {
+		shortNames[i] = shortSinkName(name)
+	}
+
+	tests := []struct {
+		pattern string
+		want    int
+	}{
+		{"Analogue Output 01 Playback Enum", 0},
+		{"analogue output 02 playback enum", 1},
+		{"Analogue Output 10", 2},
+		{"Analogue Output 1", 0},
+		{"Analogue Output 1 Playback Enum", 0},
+		{"Output 01", 0},
+		{"Output 10", 2},
+		{"Mixer Input 1", 3},
+		{"PCM 1", 4},
+		{"Output 3", -1},
+		{"Monitor", -1},
+	}
+
+	for _, tt := range tests {
+		if got := matchRoutingName(tt.pattern, names, shortNames); got != tt.want {
+			t.Errorf("matchRoutingName(%q) = %d, want %d", tt.pattern, got, tt.want)
+		}
+	}
+}
+
+func TestMatchRoutingNameDoesNotCutNumbers(t *testing.T) {
+	// "Analogue Output 1" must not pick "Analogue Output 10" even when it
+	// comes first and there is no channel 1
+	names := []string{"Analogue Output 10 Playback Enum", "Analogue Output 11 Playback Enum"}
+	if got := matchRoutingName("Analogue Output 1", names); got != -1 {
+		t.Errorf("matchRoutingName picked %q for \"Analogue Output 1\"", names[got])
+	}
+}
+
+func TestContainsWholeNumbers(t *testing.T) {
+	tests := []struct {
+		s, substr string
+		want      bool
+	}{
+		{"analogue output 1", "output 1", true},
+		{"analogue output 10", "output 1", false},
+		{"analogue output 10", "output 10", true},
+		{"pcm 21", "1", false},
+		{"pcm 21", "21", true},
+		{"analogue output 10 playback enum", "0 playback", false},
+		{"mix 1 input 10", "input 1", false},
+		{"mix 1 input 10 / input 1", "input 1", true},
+		{"s/pdif 1", "s/pdif", true},
+		{"anything", "", false},
+	}
+
+	for _, tt := range tests {
+		if got := containsWholeNumbers(tt.s, tt.substr); got != tt.want {
+			t.Errorf("containsWholeNumbers(%q, %q) = %v, want %v", tt.s, tt.substr, got, tt.want)
+		}
+	}
+}
+
+func TestFindRoutingSink(t *testing.T) {
+	sources := []string{"Off", "Analogue 1", "Analogue 2", "PCM 1", "PCM 2"}
+	card, _ := newFakeCard(t,
+		fakeElement{numid: 1, name: "Line In 1 Gain Capture Volume", typ: ControlTypeInteger, max: 70},
+		fakeElement{numid: 2, name: "Analogue Output 01 Playback Enum", typ: ControlTypeEnumerated, max: 4, items: sources},
+		fakeElement{numid: 3, name: "Analogue Output 02 Playback Enum", typ: ControlTypeEnumerated, max: 4, items: sources},
+		fakeElement{numid: 4, name: "Analogue Output 10 Playback Enum", typ: ControlTypeEnumerated, max: 4, items: sources},
+		fakeElement{numid: 5, name: "Mixer Input 01 Capture Enum", typ: ControlTypeEnumerated, max: 4, items: sources},
+		fakeElement{numid: 6, name: "PCM 01 Capture Enum", typ: ControlTypeEnumerated, max: 4, items: sources},
+	)
+
+	tests := []struct {
+		pattern string
+		numid   uint
+	}{
+		{"Analogue Output 01 Playback Enum", 2},
+		{"analogue output 02 playback enum", 3},
+		{"Analogue Output 02", 3},
+		{"Analogue Output 1", 2},
+		{"Output 10", 4},
+		{"Mixer Input 1", 5},
+		{"PCM 01", 6},
+	}
+
+	for _, tt := range tests {
+		sink, err := card.FindRoutingSink(tt.pattern)
+		if err != nil {
+			t.Errorf("FindRoutingSink(%q): %v", tt.pattern, err)
+			continue
+		}
+		if sink.Control.NumID != tt.numid {
+			t.Errorf("FindRoutingSink(%q) = %s, want numid %d", tt.pattern, sink.Name, tt.numid)
+		}
+	}
+
+	for _, pattern := range []string{"Output 3", "Analogue Output 100", "Line In 1", "ADAT"} {
+		if sink, err := card.FindRoutingSink(pattern); err == nil {
+			t.Errorf("FindRoutingSink(%q) = %s, want no match", pattern, sink.Name)
+		}
+	}
+}
+
+func TestFindSinksForSource(t *testing.T) {
+	sources := []string{"Off", "Analogue 1", "Analogue 2", "PCM 1", "PCM 2", "Mix A"}
+	card, dev := newFakeCard(t,
+		fakeElement{numid: 1, name: "Analogue Output 01 Playback Enum", typ: ControlTypeEnumerated, max: 5, items: sources, values: []int64{3}},
+		fakeElement{numid: 2, name: "Analogue Output 02 Playback Enum", typ: ControlTypeEnumerated, max: 5, items: sources, values: []int64{4}},
+		fakeElement{numid: 3, name: "Analogue Output 03 Playback Enum", typ: ControlTypeEnumerated, max: 5, items: sources, values: []int64{3}},
+		fakeElement{numid: 4, name: "Mixer Input 01 Capture Enum", typ: ControlTypeEnumerated, max: 5, items: sources, values: []int64{1}},
+	)
+
+	tests := []struct {
+		source string
+		numids []uint
+	}{
+		{"PCM 1", []uint{1, 3}},
+		{"pcm 1", []uint{1, 3}},
+		{"PCM 01", []uint{1, 3}},
+		{"3", []uint{1, 3}},
+		{"4", []uint{2}},
+		{"Analogue 1", []uint{4}},
+		{"Mix A", nil},
+	}
+
+	for _, tt := range tests {
+		sinks, err := card.FindSinksForSource(tt.source)
+		if err != nil {
+			t.Errorf("FindSinksForSource(%q): %v", tt.source, err)
+			continue
+		}
+		var numids []uint
+		for _, sink := range sinks {
+			numids = append(numids, sink.Control.NumID)
+		}
+		if !slices.Equal(numids, tt.numids) {
+			t.Errorf("FindSinksForSource(%q) = numids %v, want %v", tt.source, numids, tt.numids)
+		}
+	}
+
+	for _, source := range []string{"ADAT 1", "6", "-1"} {
+		if _, err := card.FindSinksForSource(source); err == nil {
+			t.Errorf("FindSinksForSource(%q) succeeded, want an error", source)
+		}
+	}
+
+	dev.readErr = ErrDeviceDisconnected
+	if _, err := card.FindSinksForSource("PCM 1"); !errors.Is(err, ErrDeviceDisconnected) {
+		t.Errorf("expected a read failure wrapping ErrDeviceDisconnected, got %v", err)
+	}
+}
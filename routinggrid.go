@@ -0,0 +1,149 @@
+package scarlettctl
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// sourceFamilyAbbrevs abbreviates routing source families for grid headers
+var sourceFamilyAbbrevs = map[string]string{
+	"Analogue": "A",
+	"S/PDIF":   "S",
+	"ADAT":     "O", // optical
+	"PCM":      "P",
+	"DSP":      "D",
+	"Mix":      "M",
+}
+
+// PrintRoutingGrid prints the routing matrix as a grid
+func (c *Card) PrintRoutingGrid() error {
+	return c.RenderRoutingGrid(NewRenderer(os.Stdout))
+}
+
+// RenderRoutingGrid writes the routing matrix as a grid with a row per sink
+// and a column per source, marking the source feeding each sink with an X.
+// A sink takes one source, so each row has at most one X; rows of unrouted
+// sinks are empty. Source columns are headed by abbreviations such as "A1"
+// for Analogue 1 or "MA" for Mix A, explained in a legend. The grid is as wide
+// as the sources require rather than the output width.
+func (c *Card) RenderRoutingGrid(r *Renderer) error {
+	sources, err := c.GetRoutingSources()
+	if err != nil {
+		return err
+	}
+
+	sinks, err := c.GetRoutingSinks()
+	if err != nil {
+		return err
+	}
+
+	// "Off" is the absence of a route, not a column
+	var columns []RoutingSource
+	var headers []string
+	colWidth := 2
+	for _, src := range sources {
+		if src.Name == "Off" {
+			continue
+		}
+		header := abbreviateSource(src.Name)
+		columns = append(columns, src)
+		headers = append(headers, header)
+		if len(header) > colWidth {
+			colWidth = len(header)
+		}
+	}
+	colWidth++ // separating space
+
+	labelWidth := 0
+	for _, sink := range sinks {
+		if n := len(shortSinkName(sink.Name)); n > labelWidth {
+			labelWidth = n
+		}
+	}
+
+	r.Printf("\n")
+	r.Banner("routing grid")
+	if note := c.rateNote(); note != "" {
+		r.Line(note)
+	}
+	r.Printf("\n")
+
+	var sb strings.Builder
+	sb.WriteString(strings.Repeat(" ", labelWidth+2))
+	for i, header := range headers {
+		sb.WriteString(r.Category(columns[i].Category, fmt.Sprintf("%*s", colWidth, header)))
+	}
+	r.Printf("%s\n", sb.String())
+
+	for _, sink := range sinks {
+		value, err := sink.Control.GetValue()
+		if err != nil {
+			return fmt.Errorf("failed to read routing for %s: %v", sink.Name, err)
+		}
+
+		sb.Reset()
+		sb.WriteString(pad(shortSinkName(sink.Name), labelWidth+2))
+		for _, src := range columns {
+			mark := fmt.Sprintf("%*s", colWidth, ".")
+			if int64(src.ID) == value {
+				mark = r.Category(src.Category, fmt.Sprintf("%*s", colWidth, "X"))
+			}
+			sb.WriteString(mark)
+		}
+		r.Printf("%s\n", sb.String())
+	}
+
+	r.Printf("\n")
+	r.Line(sourceLegend(columns))
+	r.Printf("\n")
+
+	return nil
+}
+
+// abbreviateSource abbreviates a routing source name for a grid header, e.g.
+// "Analogue 1" -> "A1", "Mix A" -> "MA"
+func abbreviateSource(name string) string {
+	_, abbrev, channel, ok := sourceFamily(name)
+	if !ok {
+		return name
+	}
+	return abbrev + channel
+}
+
+// sourceFamily splits a routing source name into its family, the family's
+// abbreviation and the channel, a number or a mix letter
+func sourceFamily(name string) (family, abbrev, channel string, ok bool) {
+	if letter, ok := strings.CutPrefix(name, "Mix "); ok {
+		return "Mix", sourceFamilyAbbrevs["Mix"], letter, true
+	}
+
+	parsed, ok := ParseControlName(name)
+	if !ok {
+		return "", "", "", false
+	}
+	abbrev, known := sourceFamilyAbbrevs[parsed.Family]
+	if !known {
+		// unknown families keep their initials, e.g. "Talkback Mic" -> "TM"
+		for _, word := range strings.Fields(parsed.Family) {
+			abbrev += word[:1]
+		}
+	}
+	return parsed.Family, abbrev, strconv.Itoa(parsed.ChannelNum), true
+}
+
+// sourceLegend explains the abbreviations of the families among the columns
+func sourceLegend(columns []RoutingSource) string {
+	seen := make(map[string]bool)
+	var parts []string
+	for _, src := range columns {
+		family, abbrev, _, ok := sourceFamily(src.Name)
+		if !ok || seen[family] {
+			continue
+		}
+		seen[family] = true
+		parts = append(parts, fmt.Sprintf("%s = %s", abbrev, family))
+	}
+	return strings.Join(parts, ", ")
+}
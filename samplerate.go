@@ -0,0 +1,180 @@
+package scarlettctl
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// asoundRoot is where the kernel publishes per-card stream status
+var asoundRoot = "/proc/asound"
+
+var (
+	// sampleRateRe matches a driver sample rate control, e.g. "Sample Rate Enum"
+	sampleRateRe = regexp.MustCompile(`(?i)^(?:Clock )?Sample Rate`)
+	// momentaryFreqRe matches the running rate in /proc/asound/cardN/streamN
+	momentaryFreqRe = regexp.MustCompile(`Momentary freq = (\d+) Hz`)
+	// rateDigitsRe pulls a rate out of an enum item such as "48000" or "96 kHz"
+	rateDigitsRe = regexp.MustCompile(`^(\d+(?:\.\d+)?)\s*(k?)Hz$|^(\d+)$`)
+)
+
+// SampleRate returns the card's current sample rate in Hz. The driver's
+// sample rate control is used when there is one; otherwise the rate is read
+// from the running USB stream, so it is only known while audio is playing or
+// recording.
+func (c *Card) SampleRate() (int, error) {
+	controls, err := c.GetControls()
+	if err != nil {
+		return 0, err
+	}
+
+	for _, ctl := range controls {
+		if !sampleRateRe.MatchString(ctl.Name) {
+			continue
+		}
+		value, err := ctl.GetValue()
+		if err != nil {
+			return 0, err
+		}
+		if ctl.Type == ControlTypeEnumerated {
+			if rate, ok := parseRate(ctl.FormatValue(value)); ok {
+				return rate, nil
+			}
+			continue
+		}
+		if value > 0 {
+			return int(value), nil
+		}
+	}
+
+	return c.streamRate()
+}
+
+// streamRate reads the momentary rate of the card's running USB stream
+func (c *Card) streamRate() (int, error) {
+	f, err := os.Open(filepath.Join(asoundRoot, fmt.Sprintf("card%d", c.Number), "stream0"))
+	if err != nil {
+		return 0, fmt.Errorf("sample rate: %w", ErrNotSupported)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if matches := momentaryFreqRe.FindStringSubmatch(scanner.Text()); matches != nil {
+			return strconv.Atoi(matches[1])
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+
+	return 0, fmt.Errorf("sample rate unknown (no stream running)")
+}
+
+// parseRate parses a rate from an enum item, e.g. "48000", "48kHz" or "44.1 kHz"
+func parseRate(item string) (int, bool) {
+	matches := rateDigitsRe.FindStringSubmatch(strings.TrimSpace(item))
+	if matches == nil {
+		return 0, false
+	}
+	if matches[3] != "" {
+		rate, err := strconv.Atoi(matches[3])
+		return rate, err == nil
+	}
+
+	rate, err := strconv.ParseFloat(matches[1], 64)
+	if err != nil {
+		return 0, false
+	}
+	if matches[2] != "" {
+		rate *= 1000
+	}
+	return int(rate), true
+}
+
+// adatDivisor returns how many ADAT channels share one at a sample rate:
+// ADAT carries 8 channels at single rate, 4 (S/MUX) at double rate and 2 at
+// quad rate
+func adatDivisor(rate int) int {
+	switch {
+	case rate >= 176400:
+		return 4
+	case rate >= 88200:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// ActiveChannels reports how many hardware input and output channels are live
+// at the current sample rate. ADAT ports lose channels at higher rates, so
+// some routing sources and sinks carry no signal.
+func (c *Card) ActiveChannels() (inputs, outputs int, err error) {
+	rate, err := c.SampleRate()
+	if err != nil {
+		return 0, 0, err
+	}
+	return c.activeChannels(adatDivisor(rate))
+}
+
+// activeChannels counts the hardware ports with ADAT channels divided down
+func (c *Card) activeChannels(divisor int) (inputs, outputs int, err error) {
+	sources, err := c.GetRoutingSources()
+	if err != nil {
+		return 0, 0, err
+	}
+	adatIn := 0
+	for _, src := range sources {
+		if src.Category != PortCategoryHW {
+			continue
+		}
+		inputs++
+		if src.HardwareType == "ADAT" {
+			adatIn++
+		}
+	}
+
+	sinks, err := c.GetRoutingSinks()
+	if err != nil {
+		return 0, 0, err
+	}
+	adatOut := 0
+	for _, sink := range sinks {
+		if sink.Category != PortCategoryHW {
+			continue
+		}
+		outputs++
+		if strings.HasPrefix(sink.Name, "ADAT") {
+			adatOut++
+		}
+	}
+
+	inputs -= adatIn - adatIn/divisor
+	outputs -= adatOut - adatOut/divisor
+	return inputs, outputs, nil
+}
+
+// rateNote explains reduced ADAT channels at the current sample rate, or
+// returns "" when every channel is live or the rate is unknown
+func (c *Card) rateNote() string {
+	rate, err := c.SampleRate()
+	if err != nil || adatDivisor(rate) == 1 {
+		return ""
+	}
+
+	inputs, outputs, err := c.activeChannels(adatDivisor(rate))
+	if err != nil {
+		return ""
+	}
+	allInputs, allOutputs, err := c.activeChannels(1)
+	if err != nil || inputs == allInputs && outputs == allOutputs {
+		return "" // no ADAT ports
+	}
+
+	return fmt.Sprintf("note: at %d Hz only %d of %d hardware inputs and %d of %d outputs are live (ADAT carries %d channels)",
+		rate, inputs, allInputs, outputs, allOutputs, 8/adatDivisor(rate))
+}
@@ -0,0 +1,108 @@
+package scarlettctl
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Scenes maps scene names to the partial control sets they apply
+// Unlike a full snapshot, a scene only lists the controls that matter for it,
+// so applying a scene leaves all other state untouched.
+type Scenes map[string]*Snapshot
+
+// DefaultScenesPath returns the default scenes file location (~/.config/scarlettctl/scenes.json)
+func DefaultScenesPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "scarlettctl", "scenes.json"), nil
+}
+
+// LoadScenes reads a scenes file. A missing file yields an empty set of scenes.
+func LoadScenes(path string) (Scenes, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return make(Scenes), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	scenes := make(Scenes)
+	if err := json.Unmarshal(data, &scenes); err != nil {
+		return nil, fmt.Errorf("failed to parse scenes file '%s': %v", path, err)
+	}
+	return scenes, nil
+}
+
+// Save writes the scenes file, creating its directory if needed
+func (s Scenes) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// ApplyScene applies the named scene from the default scenes file
+func (c *Card) ApplyScene(name string) error {
+	path, err := DefaultScenesPath()
+	if err != nil {
+		return err
+	}
+
+	scenes, err := LoadScenes(path)
+	if err != nil {
+		return err
+	}
+
+	scene, ok := scenes[name]
+	if !ok {
+		return fmt.Errorf("scene '%s' not found", name)
+	}
+
+	return c.RestoreSnapshot(scene)
+}
+
+// CaptureScene captures the current values of a scene's controls
+// When previous is non-nil the same controls are captured again; otherwise the
+// routing and mixer controls are captured.
+func (c *Card) CaptureScene(previous *Snapshot) (*Snapshot, error) {
+	ids := make(map[string]bool)
+
+	if previous != nil {
+		for _, entry := range previous.Controls {
+			ids[entry.ID] = true
+		}
+	} else {
+		if sinks, err := c.GetRoutingSinks(); err == nil {
+			for _, sink := range sinks {
+				ids[sink.Control.FullID()] = true
+			}
+		}
+
+		inputs, err := c.GetMixerInputs()
+		if err != nil {
+			return nil, err
+		}
+		for _, input := range inputs {
+			ids[input.Control.FullID()] = true
+		}
+	}
+
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("no routing or mixer controls to capture")
+	}
+
+	return c.TakeSnapshot(func(ctl *Control) bool {
+		return ids[ctl.FullID()]
+	})
+}
@@ -0,0 +1,159 @@
+package scarlettctl
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// hardware output level controls, e.g. "Line 01 Mute Playback Switch" and
+// "Line 01 (Monitor L) Playback Volume"
+var (
+	lineMuteRe   = regexp.MustCompile(`^Line (\d+) Mute Playback Switch$`)
+	lineVolumeRe = regexp.MustCompile(`^Line (\d+)(?: \(.*\))? Playback Volume$`)
+)
+
+// ShutdownResult lists the controls SafeShutdown silenced, with the values it
+// replaced so they can be put back with RestoreSnapshot
+type ShutdownResult struct {
+	Muted    []string
+	Previous *Snapshot
+}
+
+// shutdownWrite is a write that silences one output
+type shutdownWrite struct {
+	ctl   *Control
+	value int64
+}
+
+// SafeShutdown parks the hardware outputs so nothing can reach the monitors.
+// Each line output is muted, or turned to its minimum volume where it has no
+// mute switch; hardware outputs without a writable mute or volume, including
+// S/PDIF and ADAT, are routed to Off. Outputs already silent are left alone.
+// The values replaced are snapshotted before anything is written; on error the
+// result lists what was silenced before the failure.
+func (c *Card) SafeShutdown() (*ShutdownResult, error) {
+	writes, err := c.shutdownWrites()
+	if err != nil {
+		return nil, err
+	}
+
+	touched := make(map[ControlKey]bool, len(writes))
+	for _, w := range writes {
+		touched[w.ctl.Key()] = true
+	}
+
+	previous, err := c.TakeSnapshot(func(ctl *Control) bool { return touched[ctl.Key()] })
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ShutdownResult{Previous: previous}
+	for _, w := range writes {
+		if err := w.ctl.SetValue(w.value); err != nil {
+			return result, fmt.Errorf("%s: %v", w.ctl.Name, err)
+		}
+		result.Muted = append(result.Muted, w.ctl.Name)
+	}
+
+	return result, nil
+}
+
+// shutdownWrites finds the writes that silence every hardware output, skipping
+// controls that already hold the silent value
+func (c *Card) shutdownWrites() ([]shutdownWrite, error) {
+	controls, err := c.GetControls()
+	if err != nil {
+		return nil, err
+	}
+
+	// prefer a line's mute switch, falling back to its volume
+	mutes := make(map[int]*Control)
+	volumes := make(map[int]*Control)
+	var lines []int
+	for _, ctl := range controls {
+		if !ctl.Writable {
+			continue
+		}
+		if m := lineMuteRe.FindStringSubmatch(ctl.Name); m != nil && ctl.Type == ControlTypeBoolean {
+			line, _ := strconv.Atoi(m[1])
+			if mutes[line] == nil && volumes[line] == nil {
+				lines = append(lines, line)
+			}
+			mutes[line] = ctl
+		} else if m := lineVolumeRe.FindStringSubmatch(ctl.Name); m != nil && ctl.Type == ControlTypeInteger {
+			line, _ := strconv.Atoi(m[1])
+			if mutes[line] == nil && volumes[line] == nil {
+				lines = append(lines, line)
+			}
+			volumes[line] = ctl
+		}
+	}
+
+	var candidates []shutdownWrite
+	silenced := make(map[int]bool)
+	for _, line := range lines {
+		if ctl := mutes[line]; ctl != nil {
+			candidates = append(candidates, shutdownWrite{ctl: ctl, value: 1})
+		} else {
+			ctl := volumes[line]
+			candidates = append(candidates, shutdownWrite{ctl: ctl, value: ctl.Min})
+		}
+		silenced[line] = true
+	}
+
+	routed, err := c.parkedSinks(silenced)
+	if err != nil && len(candidates) == 0 {
+		return nil, fmt.Errorf("no hardware output controls found: %w", ErrNotSupported)
+	}
+	candidates = append(candidates, routed...)
+
+	var writes []shutdownWrite
+	for _, w := range candidates {
+		current, err := w.ctl.GetValue()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", w.ctl.Name, err)
+		}
+		if current != w.value {
+			writes = append(writes, w)
+		}
+	}
+	return writes, nil
+}
+
+// parkedSinks returns writes routing Off to the hardware output sinks whose
+// line isn't already silenced by a mute or volume
+func (c *Card) parkedSinks(silenced map[int]bool) ([]shutdownWrite, error) {
+	sources, err := c.GetRoutingSources()
+	if err != nil {
+		return nil, err
+	}
+
+	off := -1
+	for _, src := range sources {
+		if src.Category == PortCategoryOff {
+			off = src.ID
+			break
+		}
+	}
+	if off < 0 {
+		return nil, fmt.Errorf("no Off routing source")
+	}
+
+	sinks, err := c.GetRoutingSinks()
+	if err != nil {
+		return nil, err
+	}
+
+	var writes []shutdownWrite
+	for _, sink := range sinks {
+		if sink.Category != PortCategoryHW {
+			continue
+		}
+		if parsed, ok := ParseControlName(sink.Name); ok && parsed.Family == "Analogue Output" && silenced[parsed.ChannelNum] {
+			continue
+		}
+		writes = append(writes, shutdownWrite{ctl: sink.Control, value: int64(off)})
+	}
+	return writes, nil
+}
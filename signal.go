@@ -0,0 +1,115 @@
+package scarlettctl
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// SignalFloorDB is the meter level, in dB below full scale, under which an
+// input counts as silent
+const SignalFloorDB = -60.0
+
+// SignalState is the signal presence on an input
+type SignalState int
+
+const (
+	SignalSilent SignalState = iota
+	SignalPresent
+	SignalClipping
+)
+
+func (s SignalState) String() string {
+	switch s {
+	case SignalSilent:
+		return "silent"
+	case SignalPresent:
+		return "present"
+	case SignalClipping:
+		return "clipping"
+	default:
+		return "unknown"
+	}
+}
+
+// SignalStatus is the metered level of one analogue input
+type SignalStatus struct {
+	Channel int    // input number, from 1
+	Name    string // routing source name, e.g. "Analogue 1"
+	Level   int64  // raw meter value
+	Max     int64  // meter full scale
+	State   SignalState
+}
+
+// LevelDB returns the level in dB relative to full scale, or -Inf for silence
+func (s SignalStatus) LevelDB() float64 {
+	if s.Level <= 0 || s.Max <= 0 {
+		return math.Inf(-1)
+	}
+	return 20 * math.Log10(float64(s.Level)/float64(s.Max))
+}
+
+// InputSignalStatus reads the level meters and reports, per analogue input,
+// whether a signal is present, silent (below SignalFloorDB) or clipping (the
+// meter at its maximum). The driver's "Level Meter" values follow the order of
+// the routing sources, less "Off". Cards without level meters return an error
+// wrapping ErrNotSupported.
+func (c *Card) InputSignalStatus() ([]SignalStatus, error) {
+	controls, err := c.GetControls()
+	if err != nil {
+		return nil, err
+	}
+
+	var meters []*Control
+	for _, ctl := range controls {
+		if ctl.Name == "Level Meter" && ctl.Type == ControlTypeInteger {
+			meters = append(meters, ctl)
+		}
+	}
+	if len(meters) == 0 {
+		return nil, fmt.Errorf("level meters: %w", ErrNotSupported)
+	}
+	sort.Slice(meters, func(i, j int) bool { return meters[i].Index < meters[j].Index })
+
+	sources, err := c.GetRoutingSources()
+	if err != nil {
+		return nil, err
+	}
+
+	values := c.readValues(meters)
+
+	var statuses []SignalStatus
+	meter := 0
+	for _, src := range sources {
+		if src.Name == "Off" {
+			continue
+		}
+		if meter >= len(meters) {
+			break
+		}
+		ctl := meters[meter]
+		meter++
+
+		if src.Category != PortCategoryHW || src.HardwareType != "Analogue" {
+			continue
+		}
+
+		value, ok := values[ctl.Key()]
+		if !ok {
+			return nil, fmt.Errorf("failed to read %s", ctl.FullID())
+		}
+
+		status := SignalStatus{Channel: src.PortNum + 1, Name: src.Name, Level: value, Max: ctl.Max}
+		switch {
+		case value >= ctl.Max:
+			status.State = SignalClipping
+		case status.LevelDB() >= SignalFloorDB:
+			status.State = SignalPresent
+		default:
+			status.State = SignalSilent
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
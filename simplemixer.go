@@ -0,0 +1,187 @@
+package scarlettctl
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SimpleMixer accesses a card through ALSA's simple mixer API (snd_mixer_*), as
+// amixer and alsamixer do. Its elements group a volume's channels and convert
+// to and from dB using the driver's TLV data, complementing the raw controls
+// for volumes that map cleanly to simple mixer elements.
+//
+// Writes through a SimpleMixer honor the card's lock and invalidate its value
+// cache, but bypass dry-run, verify and undo, which work on raw controls.
+type SimpleMixer struct {
+	card     *Card
+	handle   *alsaHandle
+	elements []*SimpleElement
+}
+
+// SimpleElement is a simple mixer element, e.g. "Line 01" or "Master". Its
+// volume operations apply to the playback volume when it has one, otherwise to
+// the capture volume; the switch operations likewise.
+type SimpleElement struct {
+	Name           string
+	Index          int
+	PlaybackVolume bool
+	CaptureVolume  bool
+	PlaybackSwitch bool
+	CaptureSwitch  bool
+
+	Min, Max     int64   // raw volume range
+	HasDB        bool    // whether the driver reports a dB range
+	MinDB, MaxDB float64 // dB range, when HasDB
+
+	mixer *SimpleMixer
+	elem  uintptr // snd_mixer_elem_t* as uintptr, owned by the mixer
+}
+
+// OpenSimpleMixer opens the card's simple mixer. Close it when done; it stays
+// usable after the card is closed.
+func (c *Card) OpenSimpleMixer() (*SimpleMixer, error) {
+	if err := c.checkOpen(); err != nil {
+		return nil, err
+	}
+
+	handle, err := openSimpleMixer(c.Device)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &SimpleMixer{card: c, handle: handle}
+	m.elements = listSimpleElements(handle)
+	for _, e := range m.elements {
+		e.mixer = m
+	}
+	return m, nil
+}
+
+// Close closes the simple mixer; its elements can no longer be used
+func (m *SimpleMixer) Close() error {
+	return closeSimpleMixer(m.handle)
+}
+
+// Elements returns the mixer's active elements
+func (m *SimpleMixer) Elements() []*SimpleElement {
+	return m.elements
+}
+
+// Element returns the element with the given name (case-insensitive) and
+// index 0
+func (m *SimpleMixer) Element(name string) (*SimpleElement, error) {
+	for _, e := range m.elements {
+		if e.Index == 0 && strings.EqualFold(e.Name, name) {
+			return e, nil
+		}
+	}
+	return nil, fmt.Errorf("simple mixer element '%s' not found", name)
+}
+
+// String returns the element's name as amixer shows it, e.g. "'Line 01',0"
+func (e *SimpleElement) String() string {
+	return fmt.Sprintf("'%s',%d", e.Name, e.Index)
+}
+
+// HasVolume reports whether the element has a playback or capture volume
+func (e *SimpleElement) HasVolume() bool {
+	return e.PlaybackVolume || e.CaptureVolume
+}
+
+// HasSwitch reports whether the element has a playback or capture switch
+func (e *SimpleElement) HasSwitch() bool {
+	return e.PlaybackSwitch || e.CaptureSwitch
+}
+
+// GetVolume reads the raw volume
+func (e *SimpleElement) GetVolume() (int64, error) {
+	if err := e.checkVolume(); err != nil {
+		return 0, err
+	}
+	return simpleGetVolume(e.mixer.handle, e.elem, !e.PlaybackVolume)
+}
+
+// SetVolume sets the raw volume on all of the element's channels
+func (e *SimpleElement) SetVolume(value int64) error {
+	if err := e.checkVolume(); err != nil {
+		return err
+	}
+	if value < e.Min || value > e.Max {
+		return fmt.Errorf("value %d out of range [%d, %d]", value, e.Min, e.Max)
+	}
+	return e.write(func() error {
+		return simpleSetVolume(e.mixer.handle, e.elem, !e.PlaybackVolume, value)
+	})
+}
+
+// GetDB reads the volume in dB
+func (e *SimpleElement) GetDB() (float64, error) {
+	if err := e.checkDB(); err != nil {
+		return 0, err
+	}
+	return simpleGetDB(e.mixer.handle, e.elem, !e.PlaybackVolume)
+}
+
+// SetDB sets the volume in dB on all of the element's channels, rounding down
+// to the nearest step the control supports
+func (e *SimpleElement) SetDB(db float64) error {
+	if err := e.checkDB(); err != nil {
+		return err
+	}
+	if db < e.MinDB || db > e.MaxDB {
+		return fmt.Errorf("%.2f dB out of range [%.2f, %.2f]", db, e.MinDB, e.MaxDB)
+	}
+	return e.write(func() error {
+		return simpleSetDB(e.mixer.handle, e.elem, !e.PlaybackVolume, db)
+	})
+}
+
+// GetSwitch reads the switch (e.g. on meaning unmuted)
+func (e *SimpleElement) GetSwitch() (bool, error) {
+	if !e.HasSwitch() {
+		return false, fmt.Errorf("%s has no switch", e)
+	}
+	return simpleGetSwitch(e.mixer.handle, e.elem, !e.PlaybackSwitch)
+}
+
+// SetSwitch sets the switch on all of the element's channels
+func (e *SimpleElement) SetSwitch(on bool) error {
+	if !e.HasSwitch() {
+		return fmt.Errorf("%s has no switch", e)
+	}
+	return e.write(func() error {
+		return simpleSetSwitch(e.mixer.handle, e.elem, !e.PlaybackSwitch, on)
+	})
+}
+
+// checkVolume checks the element has a volume
+func (e *SimpleElement) checkVolume() error {
+	if !e.HasVolume() {
+		return fmt.Errorf("%s has no volume", e)
+	}
+	return nil
+}
+
+// checkDB checks the element has a volume with a dB range
+func (e *SimpleElement) checkDB() error {
+	if err := e.checkVolume(); err != nil {
+		return err
+	}
+	if !e.HasDB {
+		return fmt.Errorf("%s has no dB range: %w", e, ErrNotSupported)
+	}
+	return nil
+}
+
+// write makes a write through the simple mixer, refusing it while the card is
+// locked and discarding the card's cached values afterwards
+func (e *SimpleElement) write(fn func() error) error {
+	c := e.mixer.card
+	if c.locked {
+		return fmt.Errorf("cannot write %s: %w", e, ErrLocked)
+	}
+
+	err := fn()
+	c.InvalidateCache()
+	return err
+}
@@ -0,0 +1,216 @@
+package scarlettctl
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Snapshot is a saved set of control values that can be restored later
+type Snapshot struct {
+	Card     string          `json:"card,omitempty"`
+	Controls []SnapshotEntry `json:"controls"`
+}
+
+// SnapshotEntry is a single saved control value, keyed by the control's full ID
+// Enumerated values also record the item name, since item indices can change
+// between firmware versions; the index is only used if the name is gone.
+type SnapshotEntry struct {
+	ID    string `json:"id"`
+	Value int64  `json:"value"`
+	Item  string `json:"item,omitempty"`
+}
+
+// TakeSnapshot captures the current values of all controls accepted by filter
+// A nil filter captures every readable control.
+func (c *Card) TakeSnapshot(filter func(*Control) bool) (*Snapshot, error) {
+	controls, err := c.GetControls()
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &Snapshot{Card: c.Name}
+	values := c.readValues(controls)
+
+	for _, ctl := range controls {
+		if filter != nil && !filter(ctl) {
+			continue
+		}
+
+		value, ok := values[ctl.Key()]
+		if !ok {
+			continue // skip controls we can't read
+		}
+
+		entry := SnapshotEntry{
+			ID:    ctl.FullID(),
+			Value: value,
+		}
+		if ctl.Type == ControlTypeEnumerated && ctl.valueInRange(value) {
+			entry.Item = ctl.Items[value]
+		}
+
+		snapshot.Controls = append(snapshot.Controls, entry)
+	}
+
+	return snapshot, nil
+}
+
+// RestoreSnapshot writes the snapshot's values back to the card
+// Only the controls listed in the snapshot are touched. Every entry is
+// attempted; failures are collected and returned together.
+func (c *Card) RestoreSnapshot(s *Snapshot) error {
+	controls, err := c.GetControls()
+	if err != nil {
+		return err
+	}
+
+	byID := make(map[string]*Control, len(controls))
+	for _, ctl := range controls {
+		byID[ctl.FullID()] = ctl
+	}
+
+	var failures []string
+	for _, entry := range s.Controls {
+		ctl, ok := byID[entry.ID]
+		if !ok {
+			failures = append(failures, fmt.Sprintf("%s: control not found", entry.ID))
+			continue
+		}
+
+		if err := ctl.SetValue(entry.resolve(ctl)); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", entry.ID, err))
+		}
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf("failed to restore %d control(s):\n  %s", len(failures), strings.Join(failures, "\n  "))
+	}
+
+	return nil
+}
+
+// RestoreSnapshotAtomic writes the snapshot's values back to the card as one
+// transaction: every entry must resolve to a control before anything is
+// written, and if a write fails the controls already restored are rolled back
+func (c *Card) RestoreSnapshotAtomic(s *Snapshot) error {
+	tx, err := c.StageSnapshot(s)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// StageSnapshot stages a snapshot's values in a new transaction, so they can
+// be committed together with other writes
+func (c *Card) StageSnapshot(s *Snapshot) (*Tx, error) {
+	controls, err := c.GetControls()
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]*Control, len(controls))
+	for _, ctl := range controls {
+		byID[ctl.FullID()] = ctl
+	}
+
+	tx := c.Transaction()
+	for _, entry := range s.Controls {
+		ctl, ok := byID[entry.ID]
+		if !ok {
+			return nil, fmt.Errorf("%s: control not found", entry.ID)
+		}
+		if err := tx.Set(ctl, entry.resolve(ctl)); err != nil {
+			return nil, err
+		}
+	}
+
+	return tx, nil
+}
+
+// SaveSubset writes a snapshot of the writable controls accepted by filter,
+// e.g. InGroups(GroupPreamp) for just the preamps. A nil filter saves every
+// writable control.
+func (c *Card) SaveSubset(w io.Writer, filter func(*Control) bool) error {
+	snapshot, err := c.TakeSnapshot(func(ctl *Control) bool {
+		return ctl.Writable && (filter == nil || filter(ctl))
+	})
+	if err != nil {
+		return err
+	}
+
+	return WriteSnapshot(w, snapshot)
+}
+
+// LoadSubset reads a snapshot and restores the entries whose control is
+// accepted by filter, so part of a full snapshot can be restored. Entries for
+// controls missing from the card are kept so the restore reports them.
+func (c *Card) LoadSubset(r io.Reader, filter func(*Control) bool) error {
+	snapshot, err := c.ReadSubset(r, filter)
+	if err != nil {
+		return err
+	}
+
+	return c.RestoreSnapshot(snapshot)
+}
+
+// ReadSubset reads a snapshot, keeping the entries whose control is accepted by
+// filter and those for controls missing from the card. A nil filter keeps every
+// entry.
+func (c *Card) ReadSubset(r io.Reader, filter func(*Control) bool) (*Snapshot, error) {
+	snapshot, err := ReadSnapshot(r)
+	if err != nil {
+		return nil, err
+	}
+	if filter == nil {
+		return snapshot, nil
+	}
+
+	controls, err := c.GetControls()
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]*Control, len(controls))
+	for _, ctl := range controls {
+		byID[ctl.FullID()] = ctl
+	}
+
+	subset := &Snapshot{Card: snapshot.Card}
+	for _, entry := range snapshot.Controls {
+		if ctl, ok := byID[entry.ID]; !ok || filter(ctl) {
+			subset.Controls = append(subset.Controls, entry)
+		}
+	}
+	return subset, nil
+}
+
+// resolve returns the value to restore, mapping a saved item name back to
+// its current index and falling back to the saved index if the name is gone
+func (e SnapshotEntry) resolve(ctl *Control) int64 {
+	if e.Item != "" && ctl.Type == ControlTypeEnumerated {
+		for i, item := range ctl.Items {
+			if item == e.Item {
+				return int64(i)
+			}
+		}
+	}
+	return e.Value
+}
+
+// WriteSnapshot encodes a snapshot as JSON
+func WriteSnapshot(w io.Writer, s *Snapshot) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(s)
+}
+
+// ReadSnapshot decodes a snapshot from JSON
+func ReadSnapshot(r io.Reader) (*Snapshot, error) {
+	var s Snapshot
+	if err := json.NewDecoder(r).Decode(&s); err != nil {
+		return nil, fmt.Errorf("failed to decode snapshot: %v", err)
+	}
+	return &s, nil
+}
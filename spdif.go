@@ -0,0 +1,166 @@
+package scarlettctl
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SPDIFStatus is the decoded AES3/IEC958 channel status of a digital stream
+type SPDIFStatus struct {
+	Professional  bool   `json:"professional"`   // professional (AES3) rather than consumer (S/PDIF) format
+	Audio         bool   `json:"audio"`          // false for non-audio data such as compressed streams
+	CopyPermitted bool   `json:"copy_permitted"` // consumer only: copyright not asserted
+	Emphasis      string `json:"emphasis"`
+	SampleRate    int    `json:"sample_rate"` // in Hz, zero when not indicated
+	Category      byte   `json:"category"`    // consumer only: source category code
+	Raw           []byte `json:"raw"`         // the channel status bytes
+}
+
+// iec958 channel status bits, as in <sound/asoundef.h>
+const (
+	iec958AES0Professional = 0x01
+	iec958AES0NonAudio     = 0x02
+	iec958AES0ConNotCopy   = 0x04 // set when copying is permitted
+	iec958AES0ConEmphasis  = 0x38
+	iec958AES0ProEmphasis  = 0x1c
+	iec958AES0ProFS        = 0xc0
+	iec958AES3ConFS        = 0x0f
+)
+
+// consumer sample rate codes (byte 3, bits 0-3)
+var iec958ConsumerRates = map[byte]int{
+	0x0: 44100,
+	0x2: 48000,
+	0x3: 32000,
+	0x4: 22050,
+	0x6: 24000,
+	0x8: 88200,
+	0x9: 768000,
+	0xa: 96000,
+	0xc: 176400,
+	0xe: 192000,
+}
+
+// professional sample rate codes (byte 0, bits 6-7)
+var iec958ProfessionalRates = map[byte]int{
+	0x40: 48000,
+	0x80: 44100,
+	0xc0: 32000,
+}
+
+// DecodeIEC958 interprets AES3/IEC958 channel status bytes
+func DecodeIEC958(status []byte) (*SPDIFStatus, error) {
+	if len(status) < 5 {
+		return nil, fmt.Errorf("iec958 status too short: %d bytes", len(status))
+	}
+
+	s := &SPDIFStatus{
+		Professional: status[0]&iec958AES0Professional != 0,
+		Audio:        status[0]&iec958AES0NonAudio == 0,
+		Raw:          status,
+	}
+
+	if s.Professional {
+		s.SampleRate = iec958ProfessionalRates[status[0]&iec958AES0ProFS]
+		switch status[0] & iec958AES0ProEmphasis {
+		case 0x10:
+			s.Emphasis = "none"
+		case 0x18:
+			s.Emphasis = "50/15us"
+		case 0x1c:
+			s.Emphasis = "CCITT J.17"
+		default:
+			s.Emphasis = "not indicated"
+		}
+		return s, nil
+	}
+
+	s.CopyPermitted = status[0]&iec958AES0ConNotCopy != 0
+	s.Category = status[1]
+	s.SampleRate = iec958ConsumerRates[status[3]&iec958AES3ConFS]
+	if status[0]&iec958AES0ConEmphasis == 0x08 {
+		s.Emphasis = "50/15us"
+	} else {
+		s.Emphasis = "none"
+	}
+	return s, nil
+}
+
+// String summarizes the status on one line
+func (s *SPDIFStatus) String() string {
+	var parts []string
+
+	if s.Professional {
+		parts = append(parts, "professional")
+	} else {
+		parts = append(parts, "consumer")
+	}
+
+	if s.Audio {
+		parts = append(parts, "audio")
+	} else {
+		parts = append(parts, "non-audio")
+	}
+
+	if s.SampleRate > 0 {
+		parts = append(parts, fmt.Sprintf("%d Hz", s.SampleRate))
+	} else {
+		parts = append(parts, "rate not indicated")
+	}
+
+	parts = append(parts, "emphasis "+s.Emphasis)
+
+	if !s.Professional {
+		if s.CopyPermitted {
+			parts = append(parts, "copy permitted")
+		} else {
+			parts = append(parts, "copy protected")
+		}
+		parts = append(parts, fmt.Sprintf("category 0x%02x", s.Category))
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// GetIEC958Status reads and decodes the channel status of an IEC958 control
+func (ctl *Control) GetIEC958Status() (*SPDIFStatus, error) {
+	if ctl.Type != ControlTypeIEC958 {
+		return nil, fmt.Errorf("%s is not an IEC958 control", ctl.Name)
+	}
+	if err := ctl.checkCard(); err != nil {
+		return nil, err
+	}
+
+	var status []byte
+	err := ctl.card.call(func(h *alsaHandle) (err error) {
+		status, err = readIEC958(h, ctl.NumID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return DecodeIEC958(status)
+}
+
+// GetSPDIFStatus decodes the channel status of the card's S/PDIF stream
+// The capture-side IEC958 control, describing the digital input, is preferred.
+func (c *Card) GetSPDIFStatus() (*SPDIFStatus, error) {
+	controls, err := c.GetControlsByType(ControlTypeIEC958)
+	if err != nil {
+		return nil, err
+	}
+	if len(controls) == 0 {
+		return nil, fmt.Errorf("s/pdif status: %w", ErrNotSupported)
+	}
+
+	ctl := controls[0]
+	for _, candidate := range controls {
+		if strings.Contains(candidate.Name, "Capture") {
+			ctl = candidate
+			break
+		}
+	}
+
+	return ctl.GetIEC958Status()
+}
@@ -0,0 +1,54 @@
+package scarlettctl
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// speakerSwitchRe matches the speaker switching control of the larger
+// interfaces (e.g., "Speaker Switching Playback Enum", items "Off"/"Main"/"Alt")
+var speakerSwitchRe = regexp.MustCompile(`^Speaker Switching (?:Playback )?Enum$`)
+
+// findSpeakerSwitch locates the speaker switching control
+func (c *Card) findSpeakerSwitch() (*Control, error) {
+	controls, err := c.GetControls()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ctl := range controls {
+		if ctl.Type == ControlTypeEnumerated && speakerSwitchRe.MatchString(ctl.Name) {
+			return ctl, nil
+		}
+	}
+
+	return nil, fmt.Errorf("speaker switching: %w", ErrNotSupported)
+}
+
+// GetSpeakerSwitch returns the selected monitor speakers (e.g., "Main" or "Alt",
+// or "Off" when speaker switching is disabled)
+func (c *Card) GetSpeakerSwitch() (string, error) {
+	ctl, err := c.findSpeakerSwitch()
+	if err != nil {
+		return "", err
+	}
+
+	return ctl.GetValueString()
+}
+
+// SetSpeakerSwitch selects the monitor speakers by item name (case-insensitive)
+func (c *Card) SetSpeakerSwitch(target string) error {
+	ctl, err := c.findSpeakerSwitch()
+	if err != nil {
+		return err
+	}
+
+	for i, item := range ctl.Items {
+		if strings.EqualFold(item, target) {
+			return ctl.SetValue(int64(i))
+		}
+	}
+
+	return fmt.Errorf("invalid speaker switch target '%s' (valid: %s)", target, strings.Join(ctl.Items, ", "))
+}
@@ -0,0 +1,89 @@
+package scarlettctl
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// ChangeCount is the number of changes seen for one control value
+type ChangeCount struct {
+	Control *Control
+	Changes int
+}
+
+// ChangeStats counts control changes seen by an event monitor, to find
+// controls that something keeps rewriting. Level meters are not counted.
+type ChangeStats struct {
+	mu       sync.Mutex
+	started  time.Time
+	last     map[ControlKey]int64
+	counts   map[ControlKey]int
+	controls map[ControlKey]*Control
+}
+
+// NewChangeStats creates empty change statistics
+func NewChangeStats() *ChangeStats {
+	return &ChangeStats{
+		started:  time.Now(),
+		last:     make(map[ControlKey]int64),
+		counts:   make(map[ControlKey]int),
+		controls: make(map[ControlKey]*Control),
+	}
+}
+
+// Callback returns an EventMonitor.WatchControls callback that counts changes
+// The first value seen for each control is its baseline, not a change.
+func (s *ChangeStats) Callback() func(control *Control, value int64) error {
+	return func(control *Control, value int64) error {
+		if isMeter(control) {
+			return nil
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		key := control.Key()
+		if lastValue, exists := s.last[key]; exists && lastValue != value {
+			s.counts[key]++
+			s.controls[key] = control
+		}
+		s.last[key] = value
+		return nil
+	}
+}
+
+// Top returns the n most frequently changed controls, most changes first
+// A non-positive n returns every control that changed.
+func (s *ChangeStats) Top(n int) []ChangeCount {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	counts := make([]ChangeCount, 0, len(s.counts))
+	for key, changes := range s.counts {
+		counts = append(counts, ChangeCount{Control: s.controls[key], Changes: changes})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Changes != counts[j].Changes {
+			return counts[i].Changes > counts[j].Changes
+		}
+		return counts[i].Control.FullID() < counts[j].Control.FullID()
+	})
+
+	if n > 0 && len(counts) > n {
+		counts = counts[:n]
+	}
+	return counts
+}
+
+// Total returns the number of changes counted and how long counting has run
+func (s *ChangeStats) Total() (int, time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	total := 0
+	for _, changes := range s.counts {
+		total += changes
+	}
+	return total, time.Since(s.started)
+}
@@ -0,0 +1,201 @@
+package scarlettctl
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// RoutingPair is a stereo pair of routing sinks, odd channel left and even
+// channel right, with the sources feeding them. A sink without a partner forms
+// a pair of its own with a nil Right.
+type RoutingPair struct {
+	Name        string // e.g. "Analogue Output 01/02"
+	Left        RoutingSink
+	Right       *RoutingSink
+	LeftSource  int64
+	RightSource int64
+	// SourceName names the stereo source feeding both channels, e.g. "Mix A/B"
+	// or "Off"; it is empty when the channels don't route a consistent pair.
+	SourceName string
+}
+
+// Consistent reports whether both channels of the pair route one stereo source
+func (p RoutingPair) Consistent() bool {
+	return p.SourceName != ""
+}
+
+// GetRoutingPairs groups the routing sinks into stereo pairs and reads the
+// sources feeding them
+func (c *Card) GetRoutingPairs() ([]RoutingPair, error) {
+	sources, err := c.GetRoutingSources()
+	if err != nil {
+		return nil, err
+	}
+
+	sinks, err := c.GetRoutingSinks()
+	if err != nil {
+		return nil, err
+	}
+
+	var pairs []RoutingPair
+	for i := 0; i < len(sinks); i++ {
+		pair := RoutingPair{Name: shortSinkName(sinks[i].Name), Left: sinks[i]}
+		if i+1 < len(sinks) && isSinkPair(sinks[i], sinks[i+1]) {
+			pair.Right = &sinks[i+1]
+			pair.Name = sinkPairName(sinks[i])
+			i++
+		}
+
+		if pair.LeftSource, err = pair.Left.Control.GetValue(); err != nil {
+			return nil, fmt.Errorf("failed to read routing for %s: %v", pair.Left.Name, err)
+		}
+		if pair.Right != nil {
+			if pair.RightSource, err = pair.Right.Control.GetValue(); err != nil {
+				return nil, fmt.Errorf("failed to read routing for %s: %v", pair.Right.Name, err)
+			}
+			pair.SourceName = sourcePairName(sources, pair.LeftSource, pair.RightSource)
+		}
+
+		pairs = append(pairs, pair)
+	}
+
+	return pairs, nil
+}
+
+// isSinkPair reports whether two sinks are the left (odd) and right (even)
+// channels of one port family
+func isSinkPair(left, right RoutingSink) bool {
+	l, ok := ParseControlName(left.Name)
+	if !ok || l.ChannelNum%2 == 0 {
+		return false
+	}
+	r, ok := ParseControlName(right.Name)
+	return ok && r.Family == l.Family && r.Suffix == l.Suffix && r.ChannelNum == l.ChannelNum+1
+}
+
+// sinkPairName names a sink pair from its left sink, e.g. "Analogue Output 01/02"
+func sinkPairName(left RoutingSink) string {
+	parsed, _ := ParseControlName(left.Name)
+	return fmt.Sprintf("%s %02d/%02d", parsed.Family, parsed.ChannelNum, parsed.ChannelNum+1)
+}
+
+// sourcePairName names the stereo source routed to a sink pair, e.g. "Mix A/B"
+// or "PCM 1/2", or returns "" when the two sources aren't the left and right
+// channels of one port. Two unrouted channels give "Off".
+func sourcePairName(sources []RoutingSource, left, right int64) string {
+	if left < 0 || left >= int64(len(sources)) || right < 0 || right >= int64(len(sources)) {
+		return ""
+	}
+
+	l, r := sources[left], sources[right]
+	if l.Category == PortCategoryOff && r.Category == PortCategoryOff {
+		return l.Name
+	}
+	if r.ID != l.ID+1 || l.Category != r.Category || l.HardwareType != r.HardwareType || l.PortNum%2 != 0 {
+		return ""
+	}
+
+	// mixes are lettered, everything else numbered
+	if l.Category == PortCategoryMix {
+		return l.Name + "/" + strings.TrimPrefix(r.Name, "Mix ")
+	}
+	lp, ok := ParseControlName(l.Name)
+	if !ok {
+		return ""
+	}
+	rp, ok := ParseControlName(r.Name)
+	if !ok || rp.Family != lp.Family {
+		return ""
+	}
+	return fmt.Sprintf("%s %d/%d", lp.Family, lp.ChannelNum, rp.ChannelNum)
+}
+
+// PrintPairedRoutingMatrix prints the routing matrix with stereo pairs collapsed
+func (c *Card) PrintPairedRoutingMatrix() error {
+	return c.RenderPairedRoutingMatrix(NewRenderer(os.Stdout))
+}
+
+// RenderPairedRoutingMatrix writes the routing matrix with stereo pairs
+// collapsed: a sink pair fed by one stereo source takes a single line, e.g.
+// "Analogue Output 01/02 <- Mix A/B", while a pair routed any other way is
+// shown one channel per line.
+func (c *Card) RenderPairedRoutingMatrix(r *Renderer) error {
+	sources, err := c.GetRoutingSources()
+	if err != nil {
+		return err
+	}
+
+	pairs, err := c.GetRoutingPairs()
+	if err != nil {
+		return err
+	}
+
+	r.Printf("\n")
+	r.Banner("routing matrix (stereo pairs)")
+	if note := c.rateNote(); note != "" {
+		r.Line(note)
+	}
+
+	// "  " indent and " <- " separator take 6 columns
+	sinkWidth, sourceWidth := r.Columns(6, 35, 20, 8)
+	sourceCategory := func(value int64) PortCategory {
+		if value >= 0 && value < int64(len(sources)) {
+			return sources[value].Category
+		}
+		return PortCategoryOff
+	}
+	printRoute := func(sink, source string, category PortCategory) {
+		r.Printf("  %s <- %s\n", pad(sink, sinkWidth), r.Category(category, pad(source, sourceWidth)))
+	}
+
+	printPairsByCategory := func(category PortCategory, title string) {
+		var categoryPairs []RoutingPair
+		for _, pair := range pairs {
+			if pair.Left.Category == category {
+				categoryPairs = append(categoryPairs, pair)
+			}
+		}
+		if len(categoryPairs) == 0 {
+			return
+		}
+
+		r.Heading(title)
+		r.Rule("-")
+		for _, pair := range categoryPairs {
+			if pair.Consistent() {
+				printRoute(pair.Name, pair.SourceName, sourceCategory(pair.LeftSource))
+				continue
+			}
+			printRoute(shortSinkName(pair.Left.Name), pair.Left.Control.FormatValue(pair.LeftSource), sourceCategory(pair.LeftSource))
+			if pair.Right != nil {
+				printRoute(shortSinkName(pair.Right.Name), pair.Right.Control.FormatValue(pair.RightSource), sourceCategory(pair.RightSource))
+			}
+		}
+	}
+
+	printPairsByCategory(PortCategoryHW, "hardware outputs (to speakers/monitors)")
+	printPairsByCategory(PortCategoryPCM, "PCM capture (to computer/DAW)")
+	printPairsByCategory(PortCategoryMix, "mixer inputs")
+	printPairsByCategory(PortCategoryDSP, "dsp inputs")
+
+	sinks, stereo, collapsed := 0, 0, 0
+	for _, pair := range pairs {
+		sinks++
+		if pair.Right != nil {
+			sinks++
+			stereo++
+		}
+		if pair.Consistent() {
+			collapsed++
+		}
+	}
+
+	r.Printf("\n")
+	r.Rule("═")
+	r.Line(fmt.Sprintf("total: %d sinks, %d of %d pairs routed as stereo", sinks, collapsed, stereo))
+	r.Rule("═")
+	r.Printf("\n")
+
+	return nil
+}
@@ -0,0 +1,321 @@
+package scarlettctl
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// maxTLVSize bounds the TLV data read from a control, in bytes
+const maxTLVSize = 4096
+
+// tlvHeaderSize is the size of a TLV type and length header, in bytes
+const tlvHeaderSize = 8
+
+// TLV types from <sound/tlv.h>
+const (
+	TLVTypeContainer    = 0
+	TLVTypeDBScale      = 1
+	TLVTypeDBLinear     = 2
+	TLVTypeDBRange      = 3
+	TLVTypeDBMinMax     = 4
+	TLVTypeDBMinMaxMute = 5
+	TLVTypeChmapFixed   = 0x101
+	TLVTypeChmapVar     = 0x102
+	TLVTypeChmapPaired  = 0x103
+)
+
+// tlvDBScaleMute is the mute flag in the second word of a dB scale
+const tlvDBScaleMute = 0x10000
+
+// TLV is one decoded ALSA TLV (type-length-value) block
+type TLV struct {
+	Type     uint32
+	Data     []uint32 // payload words
+	Children []TLV    // nested blocks of a container or dB range
+
+	// for the children of a dB range, the raw values the block applies to
+	RangeMin int64
+	RangeMax int64
+}
+
+// ReadTLV reads the raw TLV blob of the control, as returned by
+// snd_ctl_elem_tlv_read
+func (ctl *Control) ReadTLV() ([]byte, error) {
+	if err := ctl.checkCard(); err != nil {
+		return nil, err
+	}
+
+	var raw []byte
+	err := ctl.card.call(func(h *alsaHandle) (err error) {
+		raw, err = hardware.tlv(h, ctl.NumID)
+		return err
+	})
+	return raw, err
+}
+
+// ParseTLV decodes a raw TLV blob into its blocks
+func ParseTLV(raw []byte) ([]TLV, error) {
+	if len(raw)%4 != 0 {
+		return nil, fmt.Errorf("tlv length %d is not a whole number of words", len(raw))
+	}
+
+	words := make([]uint32, len(raw)/4)
+	for i := range words {
+		words[i] = binary.NativeEndian.Uint32(raw[i*4:])
+	}
+
+	return parseTLVWords(words)
+}
+
+// parseTLVWords decodes a sequence of TLV blocks
+func parseTLVWords(words []uint32) ([]TLV, error) {
+	var blocks []TLV
+	for len(words) > 0 {
+		if len(words) < 2 {
+			return nil, fmt.Errorf("truncated tlv header")
+		}
+
+		typ, size := words[0], words[1]
+		if size%4 != 0 || int(size/4) > len(words)-2 {
+			return nil, fmt.Errorf("tlv type %d has bad length %d", typ, size)
+		}
+
+		block := TLV{Type: typ, Data: words[2 : 2+size/4]}
+		words = words[2+size/4:]
+
+		var err error
+		switch typ {
+		case TLVTypeContainer:
+			block.Children, err = parseTLVWords(block.Data)
+		case TLVTypeDBRange:
+			block.Children, err = parseDBRange(block.Data)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		blocks = append(blocks, block)
+	}
+
+	return blocks, nil
+}
+
+// parseDBRange decodes the (min, max, tlv) entries of a dB range
+func parseDBRange(words []uint32) ([]TLV, error) {
+	var entries []TLV
+	for len(words) > 0 {
+		if len(words) < 4 {
+			return nil, fmt.Errorf("truncated db range entry")
+		}
+
+		lo, hi := int64(int32(words[0])), int64(int32(words[1]))
+		size := words[3]
+		if size%4 != 0 || int(size/4) > len(words)-4 {
+			return nil, fmt.Errorf("db range entry has bad length %d", size)
+		}
+
+		blocks, err := parseTLVWords(words[2 : 4+size/4])
+		if err != nil {
+			return nil, err
+		}
+		for _, block := range blocks {
+			block.RangeMin = lo
+			block.RangeMax = hi
+			entries = append(entries, block)
+		}
+
+		words = words[4+size/4:]
+	}
+
+	return entries, nil
+}
+
+// TypeName returns the SND_CTL_TLVT_* name of the block's type
+func (t TLV) TypeName() string {
+	switch t.Type {
+	case TLVTypeContainer:
+		return "CONTAINER"
+	case TLVTypeDBScale:
+		return "DB_SCALE"
+	case TLVTypeDBLinear:
+		return "DB_LINEAR"
+	case TLVTypeDBRange:
+		return "DB_RANGE"
+	case TLVTypeDBMinMax:
+		return "DB_MINMAX"
+	case TLVTypeDBMinMaxMute:
+		return "DB_MINMAX_MUTE"
+	case TLVTypeChmapFixed:
+		return "CHMAP_FIXED"
+	case TLVTypeChmapVar:
+		return "CHMAP_VAR"
+	case TLVTypeChmapPaired:
+		return "CHMAP_PAIRED"
+	default:
+		return fmt.Sprintf("UNKNOWN(%#x)", t.Type)
+	}
+}
+
+// Summary returns a one-line human description of the block
+// dB values are stored in hundredths of a dB.
+func (t TLV) Summary() string {
+	switch t.Type {
+	case TLVTypeDBScale:
+		if len(t.Data) < 2 {
+			break
+		}
+		return fmt.Sprintf("%s min %s step %s mute %v",
+			t.TypeName(), formatCentiDB(t.Data[0]), formatCentiDB(t.Data[1]&0xffff), t.Data[1]&tlvDBScaleMute != 0)
+
+	case TLVTypeDBLinear, TLVTypeDBMinMax, TLVTypeDBMinMaxMute:
+		if len(t.Data) < 2 {
+			break
+		}
+		return fmt.Sprintf("%s min %s max %s mute %v",
+			t.TypeName(), formatCentiDB(t.Data[0]), formatCentiDB(t.Data[1]), t.Type == TLVTypeDBMinMaxMute)
+
+	case TLVTypeContainer, TLVTypeDBRange:
+		return fmt.Sprintf("%s (%d entries)", t.TypeName(), len(t.Children))
+	}
+
+	return fmt.Sprintf("%s (%d bytes)", t.TypeName(), len(t.Data)*4)
+}
+
+// formatCentiDB formats a signed hundredths-of-a-dB word
+func formatCentiDB(w uint32) string {
+	return fmt.Sprintf("%.2fdB", float64(int32(w))/100)
+}
+
+// DescribeTLV renders blocks as an indented, human-readable summary
+func DescribeTLV(blocks []TLV) string {
+	var sb strings.Builder
+	describeTLV(&sb, blocks, 0, false)
+	return sb.String()
+}
+
+// describeTLV writes blocks at the given depth; ranged blocks are the entries of
+// a dB range and are prefixed with the values they apply to
+func describeTLV(sb *strings.Builder, blocks []TLV, depth int, ranged bool) {
+	indent := strings.Repeat("  ", depth)
+	for _, block := range blocks {
+		sb.WriteString(indent)
+		if ranged {
+			sb.WriteString(fmt.Sprintf("[%d..%d] ", block.RangeMin, block.RangeMax))
+		}
+		sb.WriteString(block.Summary())
+		sb.WriteString("\n")
+		describeTLV(sb, block.Children, depth+1, block.Type == TLVTypeDBRange)
+	}
+}
+
+// DecibelRange returns the control's dB range from its TLV: the levels of its
+// minimum and maximum values and the dB step per value, which is 0 when the
+// steps aren't uniform (a linear scale, or a dB range whose segments differ).
+// Controls without a dB scale return an error wrapping ErrNotSupported.
+func (ctl *Control) DecibelRange() (min, max, step float64, err error) {
+	if ctl.Type != ControlTypeInteger && ctl.Type != ControlTypeInteger64 {
+		return 0, 0, 0, fmt.Errorf("%s has no dB scale: %w", ctl.Name, ErrNotSupported)
+	}
+
+	raw, err := ctl.ReadTLV()
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	blocks, err := ParseTLV(raw)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	for _, block := range blocks {
+		if min, max, step, ok := block.decibelRange(ctl.Min, ctl.Max); ok {
+			return min, max, step, nil
+		}
+	}
+	return 0, 0, 0, fmt.Errorf("%s has no dB scale: %w", ctl.Name, ErrNotSupported)
+}
+
+// hasDecibelScale reports whether a control's element has a dB scale,
+// remembering the answer so the TLV is only read once per element. Failures
+// of the card itself, such as a timeout, aren't remembered.
+func (c *Card) hasDecibelScale(ctl *Control) bool {
+	c.decibelMu.Lock()
+	known, ok := c.decibel[ctl.NumID]
+	c.decibelMu.Unlock()
+	if ok {
+		return known
+	}
+
+	_, _, _, err := ctl.DecibelRange()
+	if errors.Is(err, ErrClosed) || errors.Is(err, ErrDeviceDisconnected) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	c.decibelMu.Lock()
+	defer c.decibelMu.Unlock()
+	if c.decibel == nil {
+		c.decibel = make(map[uint]bool)
+	}
+	c.decibel[ctl.NumID] = err == nil
+	return err == nil
+}
+
+// decibelRange returns the dB range a block gives the raw values lo to hi, and
+// false when the block isn't a dB scale
+func (t TLV) decibelRange(lo, hi int64) (min, max, step float64, ok bool) {
+	centi := func(w uint32) float64 { return float64(int32(w)) / 100 }
+
+	switch t.Type {
+	case TLVTypeDBScale:
+		if len(t.Data) < 2 {
+			return 0, 0, 0, false
+		}
+		min, step = centi(t.Data[0]), float64(t.Data[1]&0xffff)/100
+		return min, min + step*float64(hi-lo), step, true
+
+	case TLVTypeDBMinMax, TLVTypeDBMinMaxMute:
+		if len(t.Data) < 2 {
+			return 0, 0, 0, false
+		}
+		min, max = centi(t.Data[0]), centi(t.Data[1])
+		if hi > lo {
+			step = (max - min) / float64(hi-lo)
+		}
+		return min, max, step, true
+
+	case TLVTypeDBLinear:
+		if len(t.Data) < 2 {
+			return 0, 0, 0, false
+		}
+		return centi(t.Data[0]), centi(t.Data[1]), 0, true
+
+	case TLVTypeDBRange:
+		// segments are in value order; the range spans the first to the last
+		found := false
+		for _, child := range t.Children {
+			cMin, cMax, cStep, ok := child.decibelRange(child.RangeMin, child.RangeMax)
+			if !ok {
+				continue
+			}
+			if !found {
+				min, step = cMin, cStep
+				found = true
+			} else if cStep != step {
+				step = 0
+			}
+			max = cMax
+		}
+		return min, max, step, found
+
+	case TLVTypeContainer:
+		for _, child := range t.Children {
+			if min, max, step, ok := child.decibelRange(lo, hi); ok {
+				return min, max, step, true
+			}
+		}
+	}
+
+	return 0, 0, 0, false
+}
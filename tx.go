@@ -0,0 +1,112 @@
+package scarlettctl
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Tx batches control writes so they apply together. Writes are staged with Set
+// and made by Commit; if one fails, the controls already written are put back
+// to the values they had when they were staged.
+type Tx struct {
+	card   *Card
+	writes []txWrite
+	index  map[ControlKey]int // position of each staged control in writes
+	done   bool
+}
+
+// txWrite is a staged write with the value to roll back to
+type txWrite struct {
+	ctl      *Control
+	value    int64
+	previous int64
+}
+
+// Transaction starts a batch of writes to the card
+func (c *Card) Transaction() *Tx {
+	return &Tx{card: c, index: make(map[ControlKey]int)}
+}
+
+// Set stages a write, reading the control's current value to roll back to.
+// Staging a control again replaces its value but keeps the original rollback
+// value.
+func (tx *Tx) Set(ctl *Control, value int64) error {
+	if tx.done {
+		return fmt.Errorf("transaction already committed")
+	}
+	if ctl.card != tx.card {
+		return fmt.Errorf("%s belongs to another card", ctl.Name)
+	}
+
+	if i, ok := tx.index[ctl.Key()]; ok {
+		tx.writes[i].value = value
+		return nil
+	}
+
+	previous, err := ctl.GetValue()
+	if err != nil {
+		return fmt.Errorf("%s: %v", ctl.Name, err)
+	}
+
+	tx.index[ctl.Key()] = len(tx.writes)
+	tx.writes = append(tx.writes, txWrite{ctl: ctl, value: value, previous: previous})
+	return nil
+}
+
+// SetByString stages a write of a value string, parsed as for SetValueByString
+func (tx *Tx) SetByString(ctl *Control, valueStr string) error {
+	value, err := ctl.ParseValue(valueStr)
+	if err != nil {
+		return fmt.Errorf("%s: %v", ctl.Name, err)
+	}
+	return tx.Set(ctl, value)
+}
+
+// Len returns the number of staged writes
+func (tx *Tx) Len() int {
+	return len(tx.writes)
+}
+
+// Commit makes the staged writes in the order they were staged. If a write
+// fails, the writes before it are rolled back in reverse order, as is the
+// failed one when verification showed the device altered it, and the write's
+// error is returned along with any rollback failures.
+func (tx *Tx) Commit() error {
+	if tx.done {
+		return fmt.Errorf("transaction already committed")
+	}
+	tx.done = true
+
+	for i, w := range tx.writes {
+		if err := w.ctl.SetValue(w.value); err != nil {
+			failure := fmt.Errorf("%s: %w", w.ctl.Name, err)
+			last := i - 1
+			if errors.Is(err, ErrVerifyFailed) {
+				last = i
+			}
+			if rollbackErr := tx.rollback(last); rollbackErr != nil {
+				return fmt.Errorf("%w; %v", failure, rollbackErr)
+			}
+			return failure
+		}
+	}
+
+	return nil
+}
+
+// rollback restores the controls staged up to and including index last
+func (tx *Tx) rollback(last int) error {
+	var failures []string
+	for i := last; i >= 0; i-- {
+		w := tx.writes[i]
+		if err := w.ctl.SetValue(w.previous); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", w.ctl.Name, err))
+		}
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf("rollback failed for %d control(s): %s", len(failures), strings.Join(failures, ", "))
+	}
+	return nil
+}
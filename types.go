@@ -1,5 +1,10 @@
 package scarlettctl
 
+import (
+	"sync"
+	"time"
+)
+
 // ControlType represents the type of an ALSA control element
 type ControlType int
 
@@ -98,7 +103,37 @@ func (p PortCategory) String() string {
 type Card struct {
 	Number int
 	Name   string
-	handle *alsaHandle
+	Device string // ALSA control device, e.g. "hw:1"
+
+	// PhantomPowerWarning, if set, is called with the channels about to receive
+	// 48V phantom power whenever a write switches a phantom power control on;
+	// returning an error aborts the change
+	PhantomPowerWarning func(channels []int) error
+
+	handleMu sync.Mutex
+	handle   *alsaHandle
+	stalled  int           // calls abandoned after a timeout that haven't returned yet
+	timeout  time.Duration // per-call hardware timeout, zero for none
+
+	dryRun func(ctl *Control, oldValue, newValue int64) // reports writes instead of making them
+	locked bool                                         // refuse all writes
+	verify bool                                         // read back every write
+
+	gainCaps map[int]int64 // highest gain SetPreampGain accepts, by channel
+
+	cacheMu sync.Mutex
+	cache   map[ControlKey]int64 // nil when caching is disabled
+
+	undoMu    sync.Mutex
+	undoDepth int // maximum undo entries, zero when undo is disabled
+	undo      []UndoEntry
+
+	decibelMu sync.Mutex
+	decibel   map[uint]bool // whether each element has a dB scale, once looked up
+
+	discoveryMu  sync.Mutex
+	discoveryDir string          // control metadata cache directory, empty when disabled
+	discovered   []cachedElement // metadata known to match the card, nil until loaded
 }
 
 // Control represents an ALSA control element
@@ -112,11 +147,15 @@ type Control struct {
 	Interface InterfaceType // interface type (mixer, pcm, card, etc.)
 	Device    uint          // device number
 	Subdevice uint          // subdevice number
+	Writable  bool          // false for read-only controls such as meters
 	// for integer/enumerated types
-	Min int64
-	Max int64
+	Min  int64
+	Max  int64
+	Step int64 // value granularity, zero when unrestricted
 	// for enumerated types
 	Items []string
+	// set when another element on the card has the same name
+	ambiguous bool
 }
 
 // RoutingSource represents a routing source endpoint
@@ -137,11 +176,35 @@ type RoutingSink struct {
 	Control  *Control
 }
 
+// PCMDirection is the direction of a PCM channel as seen by the computer
+type PCMDirection string
+
+const (
+	PCMCapture  PCMDirection = "capture"  // audio recorded by the computer
+	PCMPlayback PCMDirection = "playback" // audio played by the computer
+)
+
+// PCMChannel correlates a PCM channel, as numbered in the DAW, PipeWire or
+// JACK, with the hardware routing connected to it
+type PCMChannel struct {
+	Direction PCMDirection `json:"direction"`
+	Channel   int          `json:"channel"` // 1-based channel number
+	Port      string       `json:"port"`    // routing sink (capture) or source (playback) name
+	Routes    []string     `json:"routes"`  // source feeding a capture channel, or sinks fed by a playback channel
+}
+
+// ControlKey identifies a single value of a control element by numid and index
+type ControlKey struct {
+	NumID uint
+	Index int
+}
+
 // EventCallback is called when a control changes value
 type EventCallback func(control *Control)
 
 // alsaHandle wraps the C ALSA control handle (internal use only)
 type alsaHandle struct {
-	ptr     uintptr // snd_ctl_t* as uintptr
+	mu      sync.Mutex // serializes calls on the handle, which isn't thread-safe
+	ptr     uintptr    // snd_ctl_t* as uintptr
 	pollFds []int
 }
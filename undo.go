@@ -0,0 +1,162 @@
+package scarlettctl
+
+import (
+	"fmt"
+	"time"
+)
+
+// UndoEntry records a single control write so it can be reverted
+type UndoEntry struct {
+	Control  *Control
+	Previous int64 // value before the write
+	Value    int64 // value that was written
+	Time     time.Time
+}
+
+// EnableUndo turns on undo recording for writes made through this card,
+// keeping at most depth entries. Recording costs an extra read per write, so
+// it is off by default; a depth of zero or less disables it and clears the history.
+func (c *Card) EnableUndo(depth int) {
+	c.undoMu.Lock()
+	defer c.undoMu.Unlock()
+
+	if depth <= 0 {
+		c.undoDepth = 0
+		c.undo = nil
+		return
+	}
+
+	c.undoDepth = depth
+	if len(c.undo) > depth {
+		c.undo = c.undo[len(c.undo)-depth:]
+	}
+}
+
+// Undo reverts the most recent recorded write. If the restore is refused or
+// fails, the entry stays in the history; in dry-run mode the restore is only
+// reported and the history is left as it is.
+func (c *Card) Undo() error {
+	c.undoMu.Lock()
+	if len(c.undo) == 0 {
+		c.undoMu.Unlock()
+		return fmt.Errorf("nothing to undo")
+	}
+	entry := c.undo[len(c.undo)-1]
+	if c.dryRun != nil {
+		c.undoMu.Unlock()
+		return c.write(entry.Control, entry.Previous, false)
+	}
+	c.undo = c.undo[:len(c.undo)-1]
+	c.undoMu.Unlock()
+
+	// restore without recording, so repeated undos walk back through history
+	if err := c.warnPhantomWrite(entry.Control, entry.Previous); err != nil {
+		c.pushUndo(entry)
+		return err
+	}
+	if err := c.writeRaw(entry.Control, entry.Previous); err != nil {
+		c.pushUndo(entry)
+		return fmt.Errorf("failed to undo %s: %w", entry.Control.Name, err)
+	}
+
+	return nil
+}
+
+// UndoHistory returns the recorded writes, oldest first
+func (c *Card) UndoHistory() []UndoEntry {
+	c.undoMu.Lock()
+	defer c.undoMu.Unlock()
+
+	history := make([]UndoEntry, len(c.undo))
+	copy(history, c.undo)
+	return history
+}
+
+// undoEnabled reports whether writes should be recorded
+func (c *Card) undoEnabled() bool {
+	c.undoMu.Lock()
+	defer c.undoMu.Unlock()
+	return c.undoDepth > 0
+}
+
+// pushUndo records an entry, discarding the oldest beyond the configured depth
+func (c *Card) pushUndo(entry UndoEntry) {
+	c.undoMu.Lock()
+	defer c.undoMu.Unlock()
+
+	if c.undoDepth <= 0 {
+		return
+	}
+
+	c.undo = append(c.undo, entry)
+	if len(c.undo) > c.undoDepth {
+		c.undo = c.undo[len(c.undo)-c.undoDepth:]
+	}
+}
+
+// UndoRecord is an UndoEntry that can be stored between runs, naming its
+// control by full ID
+type UndoRecord struct {
+	Control  string    `json:"control"`
+	Previous int64     `json:"previous"`
+	Value    int64     `json:"value"`
+	Time     time.Time `json:"time"`
+}
+
+// Record converts the entry for storage
+func (e UndoEntry) Record() UndoRecord {
+	return UndoRecord{Control: e.Control.FullID(), Previous: e.Previous, Value: e.Value, Time: e.Time}
+}
+
+// Revert restores the value a stored record replaced. Like Undo, the restore
+// is not itself recorded; in dry-run mode it is only reported.
+func (c *Card) Revert(r UndoRecord) error {
+	ctl, err := c.FindControlByID(r.Control)
+	if err != nil {
+		return err
+	}
+	if c.dryRun != nil {
+		return c.write(ctl, r.Previous, false)
+	}
+	if err := c.warnPhantomWrite(ctl, r.Previous); err != nil {
+		return err
+	}
+	if err := c.writeRaw(ctl, r.Previous); err != nil {
+		return fmt.Errorf("failed to undo %s: %w", ctl.Name, err)
+	}
+	return nil
+}
+
+// PushUndo appends records to the undo stack stored under a card's StateKey,
+// keeping at most depth records
+func (s *State) PushUndo(cardKey string, records []UndoRecord, depth int) {
+	if len(records) == 0 {
+		return
+	}
+	if s.Undo == nil {
+		s.Undo = make(map[string][]UndoRecord)
+	}
+
+	stack := append(s.Undo[cardKey], records...)
+	if depth > 0 && len(stack) > depth {
+		stack = stack[len(stack)-depth:]
+	}
+	s.Undo[cardKey] = stack
+}
+
+// PopUndo removes and returns the newest record on the undo stack stored under
+// a card's StateKey
+func (s *State) PopUndo(cardKey string) (UndoRecord, bool) {
+	stack := s.Undo[cardKey]
+	if len(stack) == 0 {
+		return UndoRecord{}, false
+	}
+
+	record := stack[len(stack)-1]
+	if len(stack) == 1 {
+		delete(s.Undo, cardKey)
+	} else {
+		s.Undo[cardKey] = stack[:len(stack)-1]
+	}
+	return record, true
+}
@@ -0,0 +1,71 @@
+package scarlettctl
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestUndoKeepsEntryOnFailure(t *testing.T) {
+	card, dev := newFakeCard(t,
+		fakeElement{numid: 1, name: "Line In 1 Phantom Power Capture Switch", typ: ControlTypeBoolean, max: 1, values: []int64{1}},
+	)
+	card.EnableUndo(10)
+
+	phantom, err := card.FindControl("Line In 1 Phantom Power Capture Switch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := phantom.SetValue(0); err != nil {
+		t.Fatal(err)
+	}
+
+	// undoing switches phantom power back on, which is refused
+	card.PhantomPowerWarning = func(channels []int) error { return errors.New("refused") }
+	if err := card.Undo(); err == nil {
+		t.Fatal("expected the refused undo to fail")
+	}
+	if n := len(card.UndoHistory()); n != 1 {
+		t.Fatalf("history has %d entries after a refused undo, want 1", n)
+	}
+	card.PhantomPowerWarning = nil
+
+	// a failed write keeps the entry too
+	write := hardware.write
+	hardware.write = func(h *alsaHandle, ctl *Control, value int64) error { return errors.New("write failed") }
+	if err := card.Undo(); err == nil {
+		t.Fatal("expected the failed undo to fail")
+	}
+	if n := len(card.UndoHistory()); n != 1 {
+		t.Fatalf("history has %d entries after a failed undo, want 1", n)
+	}
+	hardware.write = write
+
+	// a dry run reports the restore without making it or using up the entry
+	var reported []int64
+	card.SetDryRun(func(ctl *Control, oldValue, newValue int64) {
+		reported = append(reported, oldValue, newValue)
+	})
+	if err := card.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	if len(reported) != 2 || reported[0] != 0 || reported[1] != 1 {
+		t.Errorf("dry-run undo reported %v, want [0 1]", reported)
+	}
+	if v := dev.value(1, 0); v != 0 {
+		t.Errorf("dry-run undo wrote %d", v)
+	}
+	if n := len(card.UndoHistory()); n != 1 {
+		t.Fatalf("history has %d entries after a dry-run undo, want 1", n)
+	}
+	card.SetDryRun(nil)
+
+	if err := card.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	if v := dev.value(1, 0); v != 1 {
+		t.Errorf("undo restored %d, want 1", v)
+	}
+	if n := len(card.UndoHistory()); n != 0 {
+		t.Errorf("history has %d entries after undo, want 0", n)
+	}
+}
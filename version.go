@@ -0,0 +1,52 @@
+package scarlettctl
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// firmwareVersionRe matches the firmware version control newer scarlett2
+// drivers expose on the card interface
+var firmwareVersionRe = regexp.MustCompile(`^Firmware Version$`)
+
+// FirmwareVersion returns the device firmware version (e.g. "2115"), read from
+// the firmware version control. Drivers without that control return an error
+// wrapping ErrNotSupported.
+func (c *Card) FirmwareVersion() (string, error) {
+	controls, err := c.GetControls()
+	if err != nil {
+		return "", err
+	}
+
+	for _, ctl := range controls {
+		if ctl.Type == ControlTypeInteger && firmwareVersionRe.MatchString(ctl.Name) {
+			value, err := ctl.GetValue()
+			if err != nil {
+				return "", err
+			}
+			return strconv.FormatInt(value, 10), nil
+		}
+	}
+
+	return "", fmt.Errorf("firmware version: %w", ErrNotSupported)
+}
+
+// DriverVersion returns the version of the kernel's ALSA driver (e.g.
+// "k6.8.0-45-generic"), from /proc/asound/version
+func DriverVersion() (string, error) {
+	data, err := os.ReadFile(filepath.Join(asoundRoot, "version"))
+	if err != nil {
+		return "", err
+	}
+
+	// "Advanced Linux Sound Architecture Driver Version k6.8.0-45-generic."
+	line := strings.TrimSpace(strings.SplitN(string(data), "\n", 2)[0])
+	if i := strings.LastIndex(line, "Version "); i >= 0 {
+		line = line[i+len("Version "):]
+	}
+	return strings.TrimSuffix(line, "."), nil
+}
@@ -0,0 +1,82 @@
+package scarlettctl
+
+import (
+	"fmt"
+)
+
+// ControlWriter writes one control repeatedly, e.g. for fader automation or
+// ramps, with less work per write than SetValue: the ALSA value container is
+// allocated once, and for single-value controls the read that SetValue makes
+// before every write is skipped. A ControlWriter is not safe for concurrent use.
+//
+// Writes honor the card's lock and keep the value cache current. When dry-run,
+// verify or undo recording is enabled on the card, Write falls back to
+// SetValue so those still apply.
+type ControlWriter struct {
+	ctl   *Control
+	value uintptr // snd_ctl_elem_value_t* as uintptr, zero once closed
+}
+
+// PrepareWriter returns a writer for repeated writes to the control. Close it
+// when done to free its ALSA value container.
+func (ctl *Control) PrepareWriter() (*ControlWriter, error) {
+	if err := ctl.checkCard(); err != nil {
+		return nil, err
+	}
+	switch ctl.Type {
+	case ControlTypeBoolean, ControlTypeInteger, ControlTypeInteger64, ControlTypeEnumerated:
+	default:
+		return nil, fmt.Errorf("unsupported control type for writing: %v", ctl.Type)
+	}
+
+	value, err := newElemValue(ctl)
+	if err != nil {
+		return nil, err
+	}
+	return &ControlWriter{ctl: ctl, value: value}, nil
+}
+
+// Write writes a value to the control
+func (w *ControlWriter) Write(value int64) error {
+	if w.value == 0 {
+		return fmt.Errorf("writer for %s is closed", w.ctl.Name)
+	}
+
+	c := w.ctl.card
+	if c.dryRun != nil || c.verify || c.undoEnabled() {
+		return w.ctl.SetValue(value)
+	}
+
+	if err := w.ctl.checkCard(); err != nil {
+		return err
+	}
+	if err := w.ctl.checkValue(value); err != nil {
+		return err
+	}
+	if c.locked {
+		return fmt.Errorf("cannot write %s: %w", w.ctl.Name, ErrLocked)
+	}
+	if err := c.warnPhantomWrite(w.ctl, value); err != nil {
+		return err
+	}
+
+	err := c.call(func(h *alsaHandle) error {
+		return writeElemValue(h, w.value, w.ctl, value)
+	})
+	if err != nil {
+		logger.Debug("write failed", "id", w.ctl.FullID(), "value", value, "error", err)
+		return err
+	}
+	logger.Debug("write", "id", w.ctl.FullID(), "value", value)
+
+	c.storeValue(w.ctl.Key(), value)
+	return nil
+}
+
+// Close frees the writer's ALSA value container; further writes fail
+func (w *ControlWriter) Close() {
+	if w.value != 0 {
+		freeElemValue(w.value)
+		w.value = 0
+	}
+}
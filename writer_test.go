@@ -0,0 +1,53 @@
+package scarlettctl
+
+import "testing"
+
+// benchmarkControl opens the first Scarlett card and returns a writable,
+// single-value integer control and its current value; benchmarks write that
+// value back so the card is left as it was. Skips when no card is attached.
+func benchmarkControl(b *testing.B) (*Control, int64) {
+	card := benchmarkCard(b)
+	controls, err := card.GetControlsByType(ControlTypeInteger)
+	if err != nil {
+		b.Fatal(err)
+	}
+	for _, ctl := range controls {
+		if ctl.Writable && ctl.Count == 1 && ctl.Interface == InterfaceMixer {
+			value, err := ctl.GetValue()
+			if err != nil {
+				continue
+			}
+			return ctl, value
+		}
+	}
+	b.Skip("no writable single-value integer control")
+	return nil, 0
+}
+
+func BenchmarkSetValue(b *testing.B) {
+	ctl, value := benchmarkControl(b)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := ctl.SetValue(value); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkControlWriter(b *testing.B) {
+	ctl, value := benchmarkControl(b)
+
+	w, err := ctl.PrepareWriter()
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer w.Close()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := w.Write(value); err != nil {
+			b.Fatal(err)
+		}
+	}
+}